@@ -0,0 +1,59 @@
+package app
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/auction"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/cdp"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/pool"
+)
+
+// ModuleAccountAddrs returns the addresses of the module accounts, whose coins can only be moved by their modules
+func ModuleAccountAddrs() []sdk.AccAddress {
+	return []sdk.AccAddress{
+		pool.ModuleAddress,
+		cdp.LiquidatorAccountAddress,
+		auction.ModuleAddress,
+	}
+}
+
+// NewAnteHandler returns the auth ante handler, rejecting beforehand any transaction sending coins or a CDP to a blocked address
+func NewAnteHandler(ak auth.AccountKeeper, fck auth.FeeCollectionKeeper, blockedAddrs []sdk.AccAddress) sdk.AnteHandler {
+	authAnteHandler := auth.NewAnteHandler(ak, fck, auth.DefaultSigVerificationGasConsumer)
+	return func(ctx sdk.Context, tx sdk.Tx, simulate bool) (sdk.Context, sdk.Result, bool) {
+		for _, msg := range tx.GetMsgs() {
+			err := checkRecipients(msg, blockedAddrs)
+			if err != nil {
+				return ctx, err.Result(), true
+			}
+		}
+		return authAnteHandler(ctx, tx, simulate)
+	}
+}
+
+// checkRecipients returns an error if the msg sends coins or a CDP to any of the blocked addresses
+func checkRecipients(msg sdk.Msg, blockedAddrs []sdk.AccAddress) sdk.Error {
+	var recipients []sdk.AccAddress
+	switch msg := msg.(type) {
+	case bank.MsgSend:
+		recipients = append(recipients, msg.ToAddress)
+	case bank.MsgMultiSend:
+		for _, output := range msg.Outputs {
+			recipients = append(recipients, output.Address)
+		}
+	case cdp.MsgTransferCDP:
+		recipients = append(recipients, msg.Receiver)
+	}
+	for _, recipient := range recipients {
+		for _, blocked := range blockedAddrs {
+			if recipient.Equals(blocked) {
+				return sdk.ErrUnauthorized(fmt.Sprintf("%s is a module account and can't receive coins or CDPs from users", recipient))
+			}
+		}
+	}
+	return nil
+}
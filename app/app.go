@@ -185,6 +185,7 @@ func NewKavaApp(logger log.Logger, db dbm.DB, traceStore io.Writer, loadLatest b
 	crisisSubspace := app.paramsKeeper.Subspace(crisis.DefaultParamspace)
 	cdpSubspace := app.paramsKeeper.Subspace("cdp")
 	liquidatorSubspace := app.paramsKeeper.Subspace("liquidator")
+	pricefeedSubspace := app.paramsKeeper.Subspace(pricefeed.DefaultParamspace)
 
 	// add keepers
 	app.accountKeeper = auth.NewAccountKeeper(app.cdc, app.keyAccount, authSubspace, auth.ProtoBaseAccount)
@@ -200,13 +201,25 @@ func NewKavaApp(logger log.Logger, db dbm.DB, traceStore io.Writer, loadLatest b
 	app.crisisKeeper = crisis.NewKeeper(crisisSubspace, invCheckPeriod, app.distrKeeper,
 		app.bankKeeper, app.feeCollectionKeeper)
 
-	app.pricefeedKeeper = pricefeed.NewKeeper(app.keyPricefeed, app.cdc, pricefeed.DefaultCodespace, app.cdpKeeper)
+	app.poolKeeper = pool.NewKeeper(
+		app.keyPool,
+		app.bankKeeper,
+		app.cdc,
+	)
+	app.pricefeedKeeper = pricefeed.NewKeeper(
+		app.keyPricefeed,
+		app.cdc,
+		pricefeedSubspace,
+		pricefeed.DefaultCodespace,
+		&app.cdpKeeper, // the CDP keeper is created below, as it needs the pricefeed keeper itself
+	)
 	app.cdpKeeper = cdp.NewKeeper(
 		app.cdc,
 		app.keyCdp,
 		cdpSubspace,
 		app.pricefeedKeeper,
 		app.bankKeeper,
+		app.poolKeeper,
 	)
 	app.auctionKeeper = auction.NewKeeper(
 		app.cdc,
@@ -221,17 +234,14 @@ func NewKavaApp(logger log.Logger, db dbm.DB, traceStore io.Writer, loadLatest b
 		app.auctionKeeper,
 		app.cdpKeeper, // CDP keeper standing in for bank
 	)
-	app.poolKeeper = pool.NewKeeper(
-		app.keyPool,
-		app.bankKeeper,
-		app.cdc,
-	)
 
 	// register the proposal types
 	govRouter := gov.NewRouter()
 	govRouter.AddRoute(gov.RouterKey, gov.ProposalHandler).
 		AddRoute(params.RouterKey, params.NewParamChangeProposalHandler(app.paramsKeeper)).
-		AddRoute(distr.RouterKey, distr.NewCommunityPoolSpendProposalHandler(app.distrKeeper))
+		AddRoute(distr.RouterKey, distr.NewCommunityPoolSpendProposalHandler(app.distrKeeper)).
+		AddRoute(pricefeed.RouterKey, pricefeed.NewOracleProposalHandler(app.pricefeedKeeper)).
+		AddRoute(liquidator.ModuleName, liquidator.NewCollateralProposalHandler(app.liquidatorKeeper, app.pricefeedKeeper))
 	app.govKeeper = gov.NewKeeper(app.cdc, app.keyGov, app.paramsKeeper, govSubspace,
 		app.bankKeeper, &stakingKeeper, gov.DefaultCodespace, govRouter)
 
@@ -262,10 +272,13 @@ func NewKavaApp(logger log.Logger, db dbm.DB, traceStore io.Writer, loadLatest b
 	// During begin block slashing happens after distr.BeginBlocker so that
 	// there is nothing left over in the validator fee pool, so as to keep the
 	// CanWithdrawInvariant invariant.
-	app.mm.SetOrderBeginBlockers(mint.ModuleName, distr.ModuleName, slashing.ModuleName)
+	// The other modules migrate the state left by older versions of the app.
+	app.mm.SetOrderBeginBlockers(mint.ModuleName, distr.ModuleName, slashing.ModuleName,
+		cdp.ModuleName, pool.ModuleName, auction.ModuleName, pricefeed.ModuleName)
 
-	// During the endblock, governance proposals expire, staking rewards are distributed, and the pricefeed updates
-	app.mm.SetOrderEndBlockers(gov.ModuleName, staking.ModuleName, pricefeed.ModuleName)
+	// During the endblock, governance proposals expire, staking rewards are distributed, auctions close,
+	// the queued pool withdrawals are paid, and the pricefeed updates
+	app.mm.SetOrderEndBlockers(gov.ModuleName, staking.ModuleName, auction.ModuleName, pool.ModuleName, pricefeed.ModuleName)
 
 	// genutils must occur after staking so that pools are properly
 	// initialized with tokens from genesis accounts.
@@ -300,8 +313,8 @@ func NewKavaApp(logger log.Logger, db dbm.DB, traceStore io.Writer, loadLatest b
 	// The initChainer handles translating the genesis.json file into initial state for the network
 	app.SetInitChainer(app.InitChainer)
 	app.SetBeginBlocker(app.BeginBlocker)
-	// The AnteHandler handles signature verification and transaction pre-processing
-	app.SetAnteHandler(auth.NewAnteHandler(app.accountKeeper, app.feeCollectionKeeper, auth.DefaultSigVerificationGasConsumer))
+	// The AnteHandler handles signature verification and transaction pre-processing, and blocks sends to module accounts
+	app.SetAnteHandler(NewAnteHandler(app.accountKeeper, app.feeCollectionKeeper, ModuleAccountAddrs()))
 	// Set the function to be run at the end of every block
 	app.SetEndBlocker(app.EndBlocker)
 
@@ -9,6 +9,16 @@ import (
 	"github.com/tendermint/tendermint/libs/log"
 
 	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/auction"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/cdp"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/liquidator"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/pool"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/pricefeed"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
 
 	abci "github.com/tendermint/tendermint/abci/types"
 )
@@ -24,6 +34,140 @@ func TestGaiadExport(t *testing.T) {
 	require.NoError(t, err, "ExportAppStateAndValidators should not have an error")
 }
 
+func TestValidateGenesisState(t *testing.T) {
+	cdc := MakeCodec()
+	require.NoError(t, ValidateGenesisState(cdc, NewDefaultGenesisState()))
+
+	// Collateral without liquidator params
+	genesisState := NewDefaultGenesisState()
+	liquidatorGenesis := liquidator.DefaultGenesisState()
+	liquidatorGenesis.LiquidatorModuleParams.CollateralParams = liquidatorGenesis.LiquidatorModuleParams.CollateralParams[:1]
+	genesisState[liquidator.ModuleName] = cdc.MustMarshalJSON(liquidatorGenesis)
+	require.Error(t, ValidateGenesisState(cdc, genesisState))
+
+	// Collateral without a pricefeed asset
+	genesisState = NewDefaultGenesisState()
+	pricefeedGenesis := pricefeed.DefaultGenesisState()
+	pricefeedGenesis.Assets = pricefeedGenesis.Assets[1:]
+	genesisState[pricefeed.ModuleName] = cdc.MustMarshalJSON(pricefeedGenesis)
+	require.Error(t, ValidateGenesisState(cdc, genesisState))
+
+	// Global debt not matching the seized debt
+	genesisState = NewDefaultGenesisState()
+	liquidatorGenesis = liquidator.DefaultGenesisState()
+	liquidatorGenesis.SeizedDebt.Total = sdk.NewInt(10)
+	genesisState[liquidator.ModuleName] = cdc.MustMarshalJSON(liquidatorGenesis)
+	require.Error(t, ValidateGenesisState(cdc, genesisState))
+	cdpGenesis := cdp.DefaultGenesisState()
+	cdpGenesis.GlobalDebt = sdk.NewInt(10)
+	genesisState[cdp.ModuleName] = cdc.MustMarshalJSON(cdpGenesis)
+	require.NoError(t, ValidateGenesisState(cdc, genesisState))
+
+	// Invalid module genesis
+	genesisState = NewDefaultGenesisState()
+	cdpGenesis = cdp.DefaultGenesisState()
+	cdpGenesis.CdpModuleParams.CollateralParams[0].LiquidationRatio = sdk.OneDec()
+	genesisState[cdp.ModuleName] = cdc.MustMarshalJSON(cdpGenesis)
+	require.Error(t, ValidateGenesisState(cdc, genesisState))
+}
+
+func TestAnteHandler_BlocksModuleAccounts(t *testing.T) {
+	gapp := NewKavaApp(log.NewNopLogger(), db.NewMemDB(), nil, true, 0)
+	ctx := gapp.BaseApp.NewContext(true, abci.Header{})
+	anteHandler := NewAnteHandler(gapp.accountKeeper, gapp.feeCollectionKeeper, ModuleAccountAddrs())
+	sender := sdk.AccAddress([]byte("sender"))
+	coins := sdk.NewCoins(sdk.NewInt64Coin("uatom", 10))
+
+	for _, addr := range []sdk.AccAddress{pool.ModuleAddress, cdp.LiquidatorAccountAddress, auction.ModuleAddress} {
+		msgs := []sdk.Msg{
+			bank.NewMsgSend(sender, addr, coins),
+			bank.NewMsgMultiSend([]bank.Input{bank.NewInput(sender, coins)}, []bank.Output{bank.NewOutput(addr, coins)}),
+			cdp.NewMsgTransferCDP(sender, addr, cdp.NewCollateralToken("xrp", "")),
+		}
+		for _, msg := range msgs {
+			tx := auth.NewStdTx([]sdk.Msg{msg}, auth.NewStdFee(200000, nil), nil, "")
+			_, res, abort := anteHandler(ctx, tx, false)
+			require.True(t, abort)
+			require.Equal(t, sdk.CodeUnauthorized, res.Code)
+		}
+	}
+	require.NoError(t, checkRecipients(bank.NewMsgSend(sender, sdk.AccAddress([]byte("receiver")), coins), ModuleAccountAddrs()))
+}
+
+func TestEndBlocker_PaysQueuedWithdrawals(t *testing.T) {
+	gapp := NewKavaApp(log.NewNopLogger(), db.NewMemDB(), nil, true, 0)
+	require.NoError(t, setGenesis(gapp))
+	header := abci.Header{Height: gapp.LastBlockHeight() + 1}
+	gapp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := gapp.BaseApp.NewContext(false, header)
+
+	// queue a withdrawal while the pool liquidity is lent out
+	depositor, borrower := sdk.AccAddress([]byte("depositor")), sdk.AccAddress([]byte("borrower"))
+	denom := cdp.DefaultStableDenom
+	gapp.bankKeeper.AddCoins(ctx, depositor, sdk.NewCoins(sdk.NewInt64Coin(denom, 100)))
+	require.NoError(t, gapp.poolKeeper.DepositFundFromAddress(ctx, depositor, sdk.NewInt64Coin(denom, 100)))
+	require.NoError(t, gapp.poolKeeper.DrawLiquidity(ctx, borrower, sdk.NewInt64Coin(denom, 100)))
+	queued, err := gapp.poolKeeper.RequestWithdrawal(ctx, depositor, sdk.NewInt64Coin(denom, 60))
+	require.NoError(t, err)
+	require.True(t, queued)
+
+	// repay the liquidity and check the withdrawal is paid at the end of the block
+	require.NoError(t, gapp.poolKeeper.RepayLiquidity(ctx, borrower, sdk.NewInt64Coin(denom, 100)))
+	require.True(t, gapp.bankKeeper.GetCoins(ctx, depositor).Empty())
+	gapp.EndBlock(abci.RequestEndBlock{Height: header.Height})
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin(denom, 60)), gapp.bankKeeper.GetCoins(ctx, depositor))
+	_, found := gapp.poolKeeper.GetQueuedWithdrawal(ctx, depositor, denom)
+	require.False(t, found)
+}
+
+func TestBeginBlocker_RunsMigrations(t *testing.T) {
+	gapp := NewKavaApp(log.NewNopLogger(), db.NewMemDB(), nil, true, 0)
+	require.NoError(t, setGenesis(gapp))
+	header := abci.Header{Height: gapp.LastBlockHeight() + 1}
+	gapp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := gapp.BaseApp.NewContext(false, header)
+
+	// turn the stores back into the ones of a chain upgraded from an older version
+	versionKeys := map[sdk.StoreKey][]string{
+		gapp.keyCdp:       {"cdpKeysVersion"},
+		gapp.keyPool:      {"depositsVersion", "moduleAccountVersion"},
+		gapp.keyAuction:   {"escrowVersion"},
+		gapp.keyPricefeed: {pricefeed.StoreKey + ":priceKeysVersion"},
+	}
+	for storeKey, keys := range versionKeys {
+		for _, key := range keys {
+			ctx.KVStore(storeKey).Delete([]byte(key))
+		}
+	}
+	owner := sdk.AccAddress([]byte("owner_of_legacy_cdp_"))
+	legacyCDP := types.CDP{
+		Owner:           owner,
+		Collateral:      types.Collateral{Token: cdp.BaseFT{TokenName: "xrp"}, Amount: sdk.NewInt(10), InitialPrice: sdk.ZeroInt()},
+		Liquidity:       types.Liquidity{Coin: sdk.NewInt64Coin(cdp.DefaultStableDenom, 5), InitialPrice: sdk.ZeroInt()},
+		AccumulatedFees: sdk.ZeroInt(),
+	}
+	ctx.KVStore(gapp.keyCdp).Set([]byte("cdpxrp"+owner.String()), gapp.cdc.MustMarshalBinaryLengthPrefixed(legacyCDP))
+	ctx.KVStore(gapp.keyPool).Set(owner, gapp.cdc.MustMarshalBinaryBare(sdk.NewInt64Coin(cdp.DefaultStableDenom, 30)))
+	legacyPrice := types.CurrentPrice{AssetCode: "", Price: sdk.NewInt(3), Expiry: sdk.NewInt(100)}
+	ctx.KVStore(gapp.keyPricefeed).Set([]byte(pricefeed.CurrentPricePrefix+"++xrp"), gapp.cdc.MustMarshalBinaryBare(legacyPrice))
+
+	// check the next block migrates them
+	gapp.Commit()
+	header = abci.Header{Height: gapp.LastBlockHeight() + 1}
+	gapp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx = gapp.BaseApp.NewContext(false, header)
+	readCDP, found := gapp.cdpKeeper.GetCDP(ctx, owner, "xrp", "")
+	require.True(t, found)
+	require.Equal(t, legacyCDP, readCDP)
+	require.Equal(t, sdk.NewInt64Coin(cdp.DefaultStableDenom, 30), gapp.poolKeeper.GetAccountDenomShares(ctx, owner, cdp.DefaultStableDenom))
+	require.Equal(t, sdk.NewInt(3), gapp.pricefeedKeeper.GetCurrentPrice(ctx, "", "xrp").Price)
+	for storeKey, keys := range versionKeys {
+		for _, key := range keys {
+			require.True(t, ctx.KVStore(storeKey).Has([]byte(key)), key)
+		}
+	}
+}
+
 func setGenesis(gapp *KavaApp) error {
 
 	genesisState := NewDefaultGenesisState()
@@ -2,6 +2,13 @@ package app
 
 import (
 	"encoding/json"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/cdp"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/liquidator"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/pricefeed"
 )
 
 // GenesisState The genesis state of the blockchain is represented here as a map of raw json
@@ -17,3 +24,75 @@ type GenesisState map[string]json.RawMessage
 func NewDefaultGenesisState() GenesisState {
 	return ModuleBasics.DefaultGenesis()
 }
+
+// ValidateGenesisState validates the genesis state of each module, then checks the modules are configured consistently with each other.
+// Every collateral type of the cdp module needs liquidator params, to be able to auction it off, and a pricefeed asset, to be priced.
+// The stable coin needs a pricefeed asset too, as the debt of CDPs is valued at its price.
+// The cdp global debt must also account for the debt seized by the liquidator.
+func ValidateGenesisState(cdc *codec.Codec, genesisState GenesisState) error {
+	if err := ModuleBasics.ValidateGenesis(genesisState); err != nil {
+		return err
+	}
+
+	var cdpGenesis cdp.GenesisState
+	if err := cdc.UnmarshalJSON(genesisState[cdp.ModuleName], &cdpGenesis); err != nil {
+		return fmt.Errorf("failed to unmarshal %s genesis state: %s", cdp.ModuleName, err)
+	}
+	var liquidatorGenesis liquidator.GenesisState
+	if err := cdc.UnmarshalJSON(genesisState[liquidator.ModuleName], &liquidatorGenesis); err != nil {
+		return fmt.Errorf("failed to unmarshal %s genesis state: %s", liquidator.ModuleName, err)
+	}
+	var pricefeedGenesis pricefeed.GenesisState
+	if err := cdc.UnmarshalJSON(genesisState[pricefeed.ModuleName], &pricefeedGenesis); err != nil {
+		return fmt.Errorf("failed to unmarshal %s genesis state: %s", pricefeed.ModuleName, err)
+	}
+
+	for _, cp := range cdpGenesis.CdpModuleParams.CollateralParams {
+		found := false
+		for _, lcp := range liquidatorGenesis.LiquidatorModuleParams.CollateralParams {
+			if lcp.Denom == cp.Denom {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("cdp collateral %s has no %s collateral params", cp.Denom, liquidator.ModuleName)
+		}
+
+		found = false
+		for _, asset := range pricefeedGenesis.Assets {
+			if asset.AssetName == cp.Denom {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("cdp collateral %s has no %s asset", cp.Denom, pricefeed.ModuleName)
+		}
+	}
+
+	stableDenom := cdpGenesis.CdpModuleParams.StableDenom
+	if stableDenom == "" {
+		stableDenom = cdp.DefaultStableDenom
+	}
+	found := false
+	for _, asset := range pricefeedGenesis.Assets {
+		if asset.AssetName == stableDenom {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("cdp stable denom %s has no %s asset", stableDenom, pricefeed.ModuleName)
+	}
+
+	// The global debt is split between the CDPs and the debt seized from them by the liquidator
+	debt := liquidatorGenesis.SeizedDebt.Total
+	for _, cs := range cdpGenesis.CollateralStates {
+		debt = debt.Add(cs.TotalDebt)
+	}
+	if !debt.Equal(cdpGenesis.GlobalDebt) {
+		return fmt.Errorf("cdp global debt %s doesn't match the collateral debts plus the seized debt %s", cdpGenesis.GlobalDebt, debt)
+	}
+	return nil
+}
@@ -50,9 +50,13 @@ import (
 	auctionclient "github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/auction/client"
 	auctionrest "github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/auction/client/rest"
 	cdpclient "github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/cdp/client"
+	cdprest "github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/cdp/client/rest"
 	liquidatorclient "github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/liquidator/client"
+	liquidatorcmd "github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/liquidator/client/cli"
+	liquidatorrest "github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/liquidator/client/rest"
 	poolclient "github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/pool/client"
 	priceclient "github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/pricefeed/client"
+	pricecmd "github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/pricefeed/client/cli"
 	pricerest "github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/pricefeed/client/rest"
 
 	_ "github.com/cosmos/gaia/cmd/gaiacli/statik"
@@ -69,7 +73,13 @@ func main() {
 	app.SetAddressPrefixes()
 
 	mc := []sdk.ModuleClient{
-		govClient.NewModuleClient(gv.StoreKey, cdc, paramcli.GetCmdSubmitProposal(cdc), distrcli.GetCmdSubmitProposal(cdc)),
+		govClient.NewModuleClient(gv.StoreKey, cdc,
+			paramcli.GetCmdSubmitProposal(cdc),
+			distrcli.GetCmdSubmitProposal(cdc),
+			pricecmd.GetCmdSubmitAddOracleProposal(cdc),
+			pricecmd.GetCmdSubmitRemoveOracleProposal(cdc),
+			liquidatorcmd.GetCmd_SubmitAddCollateralProposal(cdc),
+		),
 		distClient.NewModuleClient(distcmd.StoreKey, cdc),
 		stakingclient.NewModuleClient(st.StoreKey, cdc),
 		mintclient.NewModuleClient(mint.StoreKey, cdc),
@@ -176,8 +186,9 @@ func registerRoutes(rs *lcd.RestServer) {
 	gov.RegisterRoutes(rs.CliCtx, rs.Mux, rs.Cdc, paramsrest.ProposalRESTHandler(rs.CliCtx, rs.Cdc), dist.ProposalRESTHandler(rs.CliCtx, rs.Cdc))
 	mintrest.RegisterRoutes(rs.CliCtx, rs.Mux, rs.Cdc)
 	pricerest.RegisterRoutes(rs.CliCtx, rs.Mux, rs.Cdc, "pricefeed")
+	cdprest.RegisterRoutes(rs.CliCtx, rs.Mux, rs.Cdc, "cdp")
 	auctionrest.RegisterRoutes(rs.CliCtx, rs.Mux, rs.Cdc)
-	//liquidatorrest.RegisterRoutes(rs.CliCtx, rs.Mux, rs.Cdc)
+	liquidatorrest.RegisterRoutes(rs.CliCtx, rs.Mux, rs.Cdc)
 }
 
 func registerSwaggerUI(rs *lcd.RestServer) {
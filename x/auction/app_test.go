@@ -28,8 +28,9 @@ func TestApp_ForwardAuction(t *testing.T) {
 	mapp.EndBlock(abci.RequestEndBlock{})
 	mapp.Commit()
 
-	// Check seller's coins have decreased
+	// Check seller's coins have decreased, and the lot is held in escrow
 	mock.CheckBalance(t, mapp, seller, sdk.NewCoins(sdk.NewInt64Coin("token1", 80), sdk.NewInt64Coin("token2", 100)))
+	mock.CheckBalance(t, mapp, ModuleAddress, sdk.NewCoins(sdk.NewInt64Coin("token1", 20)))
 
 	// Deliver a block that contains a PlaceBid tx (bid: 10 t2, lot: same as starting)
 	msgs := []sdk.Msg{NewMsgPlaceBid(0, buyer, sdk.NewInt64Coin("token2", 10), sdk.NewInt64Coin("token1", 20))} // bid, lot
@@ -122,6 +123,8 @@ func TestApp_ForwardReverseAuction(t *testing.T) {
 	mock.CheckBalance(t, mapp, seller, sdk.NewCoins(sdk.NewInt64Coin("token1", 80), sdk.NewInt64Coin("token2", 150)))
 	// Check "recipient" has received coins
 	mock.CheckBalance(t, mapp, recipient, sdk.NewCoins(sdk.NewInt64Coin("token1", 105), sdk.NewInt64Coin("token2", 100)))
+	// Check the rest of the lot is still held in escrow
+	mock.CheckBalance(t, mapp, ModuleAddress, sdk.NewCoins(sdk.NewInt64Coin("token1", 15)))
 
 	// Deliver empty blocks until the auction should be closed (bid placed on block 3)
 	for h := mapp.LastBlockHeight() + 1; h < int64(BidDuration)+4; h++ {
@@ -20,6 +20,8 @@ type Auction interface {
 	SetID(ID)
 	PlaceBid(currentBlockHeight endTime, bidder sdk.AccAddress, lot sdk.Coin, bid sdk.Coin) ([]bankOutput, []bankInput, sdk.Error)
 	GetEndTime() endTime // auctions close at the end of the block with blockheight EndTime (ie bids placed in that block are valid)
+	GetInitiator() sdk.AccAddress
+	GetLot() sdk.Coin // coins held in escrow until the auction closes, bids are paid on as soon as they're placed
 	GetPayout() bankInput
 	String() string
 }
@@ -68,13 +70,19 @@ func (a *BaseAuction) SetID(id ID) { a.ID = id }
 // GetEndTime getter for auction end time
 func (a BaseAuction) GetEndTime() endTime { return a.EndTime }
 
+// GetInitiator getter for auction initiator
+func (a BaseAuction) GetInitiator() sdk.AccAddress { return a.Initiator }
+
+// GetLot getter for auction lot
+func (a BaseAuction) GetLot() sdk.Coin { return a.Lot }
+
 // GetPayout implements Auction
 func (a BaseAuction) GetPayout() bankInput {
 	return bankInput{a.Bidder, a.Lot}
 }
 
 func (e endTime) String() string {
-	return string(e)
+	return strconv.FormatInt(int64(e), 10)
 }
 
 func (a BaseAuction) String() string {
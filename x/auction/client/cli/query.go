@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/auction"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
 	"github.com/cosmos/cosmos-sdk/client/context"
 	"github.com/cosmos/cosmos-sdk/codec"
 	"github.com/spf13/cobra"
@@ -30,3 +31,23 @@ func GetCmdGetAuctions(queryRoute string, cdc *codec.Codec) *cobra.Command {
 		},
 	}
 }
+
+// GetCmdGetModuleAccount queries the auction module account holding the coins in escrow
+func GetCmdGetModuleAccount(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "module-account",
+		Short: "get the auction module account",
+		Long:  "Get the address of the auction module account and its balance, the lots and bids of the active auctions held in escrow.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			res, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", queryRoute, auction.QueryGetModuleAccount), nil)
+			if err != nil {
+				return err
+			}
+			var account types.ModuleAccount
+			cdc.MustUnmarshalJSON(res, &account)
+			return cliCtx.PrintOutput(account)
+		},
+	}
+}
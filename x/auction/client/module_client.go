@@ -28,6 +28,7 @@ func (mc ModuleClient) GetQueryCmd() *cobra.Command {
 
 	auctionQueryCmd.AddCommand(client.GetCommands(
 		auctioncmd.GetCmdGetAuctions(mc.storeKey, mc.cdc),
+		auctioncmd.GetCmdGetModuleAccount(mc.storeKey, mc.cdc),
 	)...)
 
 	return auctionQueryCmd
@@ -5,6 +5,7 @@ import (
 )
 
 type bankKeeper interface {
+	GetCoins(sdk.Context, sdk.AccAddress) sdk.Coins
 	SubtractCoins(sdk.Context, sdk.AccAddress, sdk.Coins) (sdk.Coins, sdk.Error)
 	AddCoins(sdk.Context, sdk.AccAddress, sdk.Coins) (sdk.Coins, sdk.Error)
 }
@@ -1,29 +1,63 @@
 package auction
 
 import (
+	"fmt"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
-// GenesisState - crisis genesis state
+// GenesisState - auction genesis state
+// The end time queue isn't stored as it's rebuilt from the auctions' end times when they are imported.
 type GenesisState struct {
+	NextAuctionID ID        `json:"next_auction_id"`
+	Auctions      []Auction `json:"auctions"` // live auctions, their lots and bids are held by the module until they close
 }
 
 // NewGenesisState creates a new GenesisState object
-func NewGenesisState() GenesisState {
-	return GenesisState{}
+func NewGenesisState(nextID ID, auctions []Auction) GenesisState {
+	return GenesisState{
+		NextAuctionID: nextID,
+		Auctions:      auctions,
+	}
 }
 
 // DefaultGenesisState creates a default GenesisState object
 func DefaultGenesisState() GenesisState {
-	return GenesisState{}
+	return NewGenesisState(ID(0), []Auction{})
+}
+
+// InitGenesis sets the genesis state in the keeper.
+func InitGenesis(ctx sdk.Context, keeper Keeper, data GenesisState) {
+	keeper.setNextAuctionID(ctx, data.NextAuctionID)
+	for _, a := range data.Auctions {
+		keeper.setAuction(ctx, a) // also adds it to the queue
+	}
+	keeper.setEscrowMigrated(ctx) // the lots of imported auctions are held by the module account
 }
 
 // ValidateGenesis validates genesis state
 func ValidateGenesis(data GenesisState) error {
+	ids := make(map[ID]bool)
+	for _, a := range data.Auctions {
+		if a == nil {
+			return fmt.Errorf("auction must not be empty")
+		}
+		if a.GetID() >= data.NextAuctionID {
+			return fmt.Errorf("auction %d has an ID not below the next auction ID %d", a.GetID(), data.NextAuctionID)
+		}
+		if ids[a.GetID()] {
+			return fmt.Errorf("auction %d is repeated", a.GetID())
+		}
+		ids[a.GetID()] = true
+	}
 	return nil
 }
 
 // ExportGenesis returns a GenesisState for a given context and keeper.
 func ExportGenesis(ctx sdk.Context, keeper Keeper) GenesisState {
-	return NewGenesisState()
+	nextAuctionID, err := keeper.getNextAuctionID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return NewGenesisState(nextAuctionID, keeper.GetAuctions(ctx))
 }
@@ -0,0 +1,59 @@
+package auction
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+func TestExportImportGenesis(t *testing.T) {
+	// Setup
+	mapp, keeper, addresses, _ := setUpMockApp()
+	header := abci.Header{Height: mapp.LastBlockHeight() + 1}
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := mapp.BaseApp.NewContext(false, header)
+	_, err := keeper.StartForwardAuction(ctx, addresses[0], sdk.NewInt64Coin("token1", 20), sdk.NewInt64Coin("token2", 0))
+	require.NoError(t, err)
+	_, err = keeper.StartReverseAuction(ctx, addresses[0], sdk.NewInt64Coin("token1", 20), sdk.NewInt64Coin("token2", 50))
+	require.NoError(t, err)
+	id, err := keeper.StartForwardReverseAuction(ctx, addresses[0], sdk.NewInt64Coin("token1", 20), sdk.NewInt64Coin("token2", 50), addresses[2])
+	require.NoError(t, err)
+	require.NoError(t, keeper.PlaceBid(ctx, id, addresses[1], sdk.NewInt64Coin("token2", 10), sdk.NewInt64Coin("token1", 20)))
+
+	// Run test function
+	exported := ExportGenesis(ctx, keeper)
+	require.NoError(t, ValidateGenesis(exported))
+	var imported GenesisState
+	moduleCdc.MustUnmarshalJSON(moduleCdc.MustMarshalJSON(exported), &imported)
+
+	mapp2, keeper2, addresses2, _ := setUpMockApp()
+	header2 := abci.Header{Height: mapp2.LastBlockHeight() + 1}
+	mapp2.BeginBlock(abci.RequestBeginBlock{Header: header2})
+	ctx2 := mapp2.BaseApp.NewContext(false, header2)
+	InitGenesis(ctx2, keeper2, imported)
+
+	// Check
+	require.Equal(t, ID(3), exported.NextAuctionID)
+	require.Len(t, exported.Auctions, 3)
+	require.Equal(t, exported, ExportGenesis(ctx2, keeper2))
+	// auctions are back in the queue
+	iter := keeper2.getQueueIterator(ctx2, exported.Auctions[0].GetEndTime()) // the bid has brought the last auction's end time forward
+	require.Equal(t, []ID{2, 0, 1}, convertIteratorToSlice(keeper2, iter))
+	iter.Close()
+	// new auctions don't reuse IDs
+	newID, err := keeper2.StartForwardAuction(ctx2, addresses2[0], sdk.NewInt64Coin("token1", 20), sdk.NewInt64Coin("token2", 0))
+	require.NoError(t, err)
+	require.Equal(t, ID(3), newID)
+}
+
+func TestValidateGenesis(t *testing.T) {
+	auction, _ := NewForwardAuction(sdk.AccAddress([]byte("someName")), sdk.NewInt64Coin("usdx", 100), sdk.NewInt64Coin("kava", 0), endTime(1000))
+	auction.SetID(2)
+
+	require.NoError(t, ValidateGenesis(DefaultGenesisState()))
+	require.NoError(t, ValidateGenesis(NewGenesisState(3, []Auction{&auction})))
+	require.Error(t, ValidateGenesis(NewGenesisState(2, []Auction{&auction})))
+	require.Error(t, ValidateGenesis(NewGenesisState(3, []Auction{&auction, &auction})))
+}
@@ -3,11 +3,16 @@ package auction
 import (
 	"bytes"
 	"fmt"
+	"sort"
 
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
+// ModuleAddress is the address of the module account holding the lots and bids of the live auctions in escrow
+var ModuleAddress = types.NewModuleAddress(ModuleName)
+
 type Keeper struct {
 	bankKeeper bankKeeper
 	storeKey   sdk.StoreKey
@@ -72,8 +77,8 @@ func (k Keeper) startAuction(ctx sdk.Context, auction Auction, initiatorOutput b
 	// set ID
 	auction.SetID(newAuctionID)
 
-	// subtract coins from initiator
-	_, err = k.bankKeeper.SubtractCoins(ctx, initiatorOutput.Address, sdk.NewCoins(initiatorOutput.Coin))
+	// move coins from initiator into escrow
+	err = k.escrowCoins(ctx, initiatorOutput.Address, initiatorOutput.Coin)
 	if err != nil {
 		return 0, err
 	}
@@ -99,16 +104,16 @@ func (k Keeper) PlaceBid(ctx sdk.Context, auctionID ID, bidder sdk.AccAddress, b
 		return err
 	}
 	// TODO this will fail if someone tries to update their bid without the full bid amount sitting in their account
-	// sub outputs
+	// move outputs into escrow
 	for _, output := range coinOutputs {
-		_, err = k.bankKeeper.SubtractCoins(ctx, output.Address, sdk.NewCoins(output.Coin)) // TODO handle errors properly here. All coin transfers should be atomic. InputOutputCoins may work
+		err = k.escrowCoins(ctx, output.Address, output.Coin) // TODO handle errors properly here. All coin transfers should be atomic. InputOutputCoins may work
 		if err != nil {
 			panic(err)
 		}
 	}
-	// add inputs
+	// pay inputs out of escrow
 	for _, input := range coinInputs {
-		_, err = k.bankKeeper.AddCoins(ctx, input.Address, sdk.NewCoins(input.Coin)) // TODO errors
+		err = k.releaseCoins(ctx, input.Address, input.Coin) // TODO errors
 		if err != nil {
 			panic(err)
 		}
@@ -135,7 +140,7 @@ func (k Keeper) CloseAuction(ctx sdk.Context, auctionID ID) sdk.Error {
 	}
 	// payout to the last bidder
 	coinInput := auction.GetPayout()
-	_, err := k.bankKeeper.AddCoins(ctx, coinInput.Address, sdk.NewCoins(coinInput.Coin))
+	err := k.releaseCoins(ctx, coinInput.Address, coinInput.Coin)
 	if err != nil {
 		return err
 	}
@@ -146,6 +151,67 @@ func (k Keeper) CloseAuction(ctx sdk.Context, auctionID ID) sdk.Error {
 	return nil
 }
 
+// escrowCoins moves coins from an account into the auction module account, where they're held until the auction pays them out.
+func (k Keeper) escrowCoins(ctx sdk.Context, from sdk.AccAddress, coin sdk.Coin) sdk.Error {
+	_, err := k.bankKeeper.SubtractCoins(ctx, from, sdk.NewCoins(coin))
+	if err != nil {
+		return err
+	}
+	_, err = k.bankKeeper.AddCoins(ctx, ModuleAddress, sdk.NewCoins(coin))
+	return err
+}
+
+// releaseCoins pays coins held in escrow out of the auction module account.
+func (k Keeper) releaseCoins(ctx sdk.Context, to sdk.AccAddress, coin sdk.Coin) sdk.Error {
+	_, err := k.bankKeeper.SubtractCoins(ctx, ModuleAddress, sdk.NewCoins(coin))
+	if err != nil {
+		return err
+	}
+	_, err = k.bankKeeper.AddCoins(ctx, to, sdk.NewCoins(coin))
+	return err
+}
+
+// GetModuleAccount returns the address and balance of the auction module account
+func (k Keeper) GetModuleAccount(ctx sdk.Context) types.ModuleAccount {
+	return types.ModuleAccount{Address: ModuleAddress, Coins: k.bankKeeper.GetCoins(ctx, ModuleAddress)}
+}
+
+// escrowVersionKey stores the version of the escrow of the auction lots, see MigrateEscrow.
+// Version 1 holds the lots in the auction module account.
+var escrowVersionKey = []byte("escrowVersion")
+
+const escrowVersion byte = 1
+
+func (k Keeper) getEscrowVersion(ctx sdk.Context) byte {
+	bz := ctx.KVStore(k.storeKey).Get(escrowVersionKey)
+	if len(bz) != 1 {
+		return 0
+	}
+	return bz[0]
+}
+func (k Keeper) setEscrowMigrated(ctx sdk.Context) {
+	ctx.KVStore(k.storeKey).Set(escrowVersionKey, []byte{escrowVersion})
+}
+
+// MigrateEscrow puts in escrow the lots of the auctions started by older versions.
+// Those took the lot out of the initiator account without holding it anywhere, minting it again when paying it out.
+// It does nothing once the store is up to date, stores initialized from genesis already are.
+func (k Keeper) MigrateEscrow(ctx sdk.Context) {
+	if k.getEscrowVersion(ctx) >= escrowVersion {
+		return
+	}
+	lots := sdk.NewCoins()
+	for _, a := range k.GetAuctions(ctx) {
+		lots = lots.Add(sdk.NewCoins(a.GetLot()))
+	}
+	_, err := k.bankKeeper.AddCoins(ctx, ModuleAddress, lots)
+	if err != nil {
+		ctx.Logger().Error(fmt.Sprintf("could not escrow the lots of the live auctions: %s", err))
+		return // retried next block
+	}
+	k.setEscrowMigrated(ctx)
+}
+
 // ---------- Store methods ----------
 // Use these to add and remove auction from the store.
 
@@ -185,6 +251,28 @@ func (k Keeper) incrementNextAuctionID(ctx sdk.Context) sdk.Error {
 	return nil
 }
 
+// setNextAuctionID sets the global ID that the next auction will get, used at genesis
+func (k Keeper) setNextAuctionID(ctx sdk.Context, auctionID ID) {
+	store := ctx.KVStore(k.storeKey)
+	bz := k.cdc.MustMarshalBinaryLengthPrefixed(auctionID)
+	store.Set(k.getNextAuctionIDKey(), bz)
+}
+
+// GetAuctions returns all the auctions in the store, ordered by ID
+func (k Keeper) GetAuctions(ctx sdk.Context) []Auction {
+	iter := k.GetAuctionIterator(ctx)
+	defer iter.Close()
+
+	auctions := []Auction{}
+	for ; iter.Valid(); iter.Next() {
+		var auction Auction
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iter.Value(), &auction)
+		auctions = append(auctions, auction)
+	}
+	sort.Slice(auctions, func(i, j int) bool { return auctions[i].GetID() < auctions[j].GetID() })
+	return auctions
+}
+
 // setAuction puts the auction into the database and adds it to the queue
 // it overwrites any pre-existing auction with same ID
 func (k Keeper) setAuction(ctx sdk.Context, auction Auction) {
@@ -237,7 +325,7 @@ func (k Keeper) getNextAuctionIDKey() []byte {
 	return []byte("nextAuctionID")
 }
 func (k Keeper) getAuctionKey(auctionID ID) []byte {
-	return []byte(fmt.Sprintf("auctions:%d", auctionID))
+	return []byte(fmt.Sprintf("%s%d", auctionKeyPrefix, auctionID))
 }
 
 // Inserts a AuctionID into the queue at endTime
@@ -273,9 +361,11 @@ func (k Keeper) getQueueIterator(ctx sdk.Context, endTime endTime) sdk.Iterator
 // GetAuctionIterator returns an iterator over all auctions in the store
 func (k Keeper) GetAuctionIterator(ctx sdk.Context) sdk.Iterator {
 	store := ctx.KVStore(k.storeKey)
-	return sdk.KVStorePrefixIterator(store, nil)
+	return sdk.KVStorePrefixIterator(store, auctionKeyPrefix)
 }
 
+var auctionKeyPrefix = []byte("auctions:")
+
 var queueKeyPrefix = []byte("queue")
 var keyDelimiter = []byte(":")
 
@@ -85,3 +85,31 @@ func convertIteratorToSlice(keeper Keeper, iterator sdk.Iterator) []ID {
 	}
 	return queue
 }
+
+func TestKeeper_MigrateEscrow(t *testing.T) {
+	// setup keeper and the auctions started by an older version, which didn't hold their lots in escrow
+	mapp, keeper, addresses, _ := setUpMockApp()
+	header := abci.Header{Height: mapp.LastBlockHeight() + 1}
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := mapp.BaseApp.NewContext(false, header)
+	forward, _ := NewForwardAuction(addresses[0], sdk.NewInt64Coin("usdx", 100), sdk.NewInt64Coin("kava", 0), endTime(1))
+	forward.SetID(0)
+	keeper.setAuction(ctx, &forward)
+	reverse, _ := NewReverseAuction(addresses[1], sdk.NewInt64Coin("usdx", 50), sdk.NewInt64Coin("kava", 20), endTime(1000))
+	reverse.SetID(1)
+	keeper.setAuction(ctx, &reverse)
+	require.True(t, keeper.GetModuleAccount(ctx).Coins.Empty())
+
+	keeper.MigrateEscrow(ctx)
+
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("kava", 20), sdk.NewInt64Coin("usdx", 100)), keeper.GetModuleAccount(ctx).Coins)
+	require.Equal(t, escrowVersion, keeper.getEscrowVersion(ctx))
+
+	// check the lots can be paid out of escrow
+	require.NoError(t, keeper.CloseAuction(ctx, 0))
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("kava", 20)), keeper.GetModuleAccount(ctx).Coins)
+
+	// migrating again does nothing
+	keeper.MigrateEscrow(ctx)
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("kava", 20)), keeper.GetModuleAccount(ctx).Coins)
+}
@@ -16,8 +16,8 @@ func TestMsgPlaceBid_ValidateBasic(t *testing.T) {
 	}{
 		{"normal", MsgPlaceBid{0, addr, sdk.NewInt64Coin("usdx", 10), sdk.NewInt64Coin("kava", 20)}, true},
 		{"emptyAddr", MsgPlaceBid{0, sdk.AccAddress{}, sdk.NewInt64Coin("usdx", 10), sdk.NewInt64Coin("kava", 20)}, false},
-		{"negativeBid", MsgPlaceBid{0, addr, sdk.Coin{"usdx", sdk.NewInt(-10)}, sdk.NewInt64Coin("kava", 20)}, false},
-		{"negativeLot", MsgPlaceBid{0, addr, sdk.NewInt64Coin("usdx", 10), sdk.Coin{"kava", sdk.NewInt(-20)}}, false},
+		{"negativeBid", MsgPlaceBid{0, addr, sdk.Coin{Denom: "usdx", Amount: sdk.NewInt(-10)}, sdk.NewInt64Coin("kava", 20)}, false},
+		{"negativeLot", MsgPlaceBid{0, addr, sdk.NewInt64Coin("usdx", 10), sdk.Coin{Denom: "kava", Amount: sdk.NewInt(-20)}}, false},
 		{"zerocoins", MsgPlaceBid{0, addr, sdk.NewInt64Coin("usdx", 0), sdk.NewInt64Coin("kava", 0)}, true},
 	}
 	for _, tc := range tests {
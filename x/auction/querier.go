@@ -11,6 +11,8 @@ import (
 const (
 	// QueryGetAuction command for getting the information about a particular auction
 	QueryGetAuction = "getauctions"
+	// QueryGetModuleAccount command for getting the auction module account holding the coins in escrow
+	QueryGetModuleAccount = "module-account"
 )
 
 // NewQuerier is the module level router for state queries
@@ -19,6 +21,8 @@ func NewQuerier(keeper Keeper) sdk.Querier {
 		switch path[0] {
 		case QueryGetAuction:
 			return queryAuctions(ctx, req, keeper)
+		case QueryGetModuleAccount:
+			return queryModuleAccount(ctx, req, keeper)
 		default:
 			return nil, sdk.ErrUnknownRequest("unknown auction query endpoint")
 		}
@@ -28,12 +32,7 @@ func NewQuerier(keeper Keeper) sdk.Querier {
 func queryAuctions(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) (res []byte, err sdk.Error) {
 	var AuctionsList QueryResAuctions
 
-	iterator := keeper.GetAuctionIterator(ctx)
-
-	for ; iterator.Valid(); iterator.Next() {
-
-		var auction Auction
-		keeper.cdc.MustUnmarshalBinaryBare(iterator.Value(), &auction)
+	for _, auction := range keeper.GetAuctions(ctx) {
 		AuctionsList = append(AuctionsList, auction.String())
 	}
 
@@ -45,6 +44,15 @@ func queryAuctions(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) (res [
 	return bz, nil
 }
 
+func queryModuleAccount(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) (res []byte, err sdk.Error) {
+	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, keeper.GetModuleAccount(ctx))
+	if err2 != nil {
+		panic("could not marshal result to JSON")
+	}
+
+	return bz, nil
+}
+
 // QueryResAuctions Result Payload for an auctions query
 type QueryResAuctions []string
 
@@ -15,33 +15,31 @@ func TestApp_CreateModifyDeleteCDP(t *testing.T) {
 	testAddr := addrs[0]
 	testPrivKey := privKeys[0]
 	mock.SetGenesis(mapp, genAccs)
-	// setup pricefeed, TODO can this be shortened a bit?
+	// setup pricefeed
 	header := abci.Header{Height: mapp.LastBlockHeight() + 1}
 	mapp.BeginBlock(abci.RequestBeginBlock{Header: header})
 	ctx := mapp.BaseApp.NewContext(false, header)
-	keeper.pricefeed.AddAsset(ctx, "xrp", "xrp test")
-	keeper.pricefeed.SetPrice(
-		ctx, sdk.AccAddress{}, "xrp",
-		sdk.MustNewDecFromStr("1.00"),
-		sdk.NewInt(10))
-	keeper.pricefeed.SetCurrentPrices(ctx)
+	setCurrentPrice(ctx, keeper, "xrp", 1)
 	mapp.EndBlock(abci.RequestEndBlock{})
 	mapp.Commit()
 
 	// Create CDP
-	msgs := []sdk.Msg{NewMsgCreateOrModifyCDP(testAddr, "xrp", i(10), i(5))}
+	msgs := []sdk.Msg{NewMsgCreateOrModifyCDP(testAddr, ftCollateral("xrp", 30), liq(stableDenom, 10))}
 	mock.SignCheckDeliver(t, mapp.Cdc, mapp.BaseApp, abci.Header{Height: mapp.LastBlockHeight() + 1}, msgs, []uint64{0}, []uint64{0}, true, true, testPrivKey)
 
-	mock.CheckBalance(t, mapp, testAddr, cs(c(StableDenom, 5), c("xrp", 90)))
+	mock.CheckBalance(t, mapp, testAddr, cs(c(stableDenom, 10), c("xrp", 70)))
 
 	// Modify CDP
-	msgs = []sdk.Msg{NewMsgCreateOrModifyCDP(testAddr, "xrp", i(40), i(5))}
+	msgs = []sdk.Msg{
+		NewMsgDepositCollateral(testAddr, ftCollateral("xrp", 20), stableDenom),
+		NewMsgDrawDebt(testAddr, BaseFT{TokenName: "xrp"}, c(stableDenom, 10)),
+	}
 	mock.SignCheckDeliver(t, mapp.Cdc, mapp.BaseApp, abci.Header{Height: mapp.LastBlockHeight() + 1}, msgs, []uint64{0}, []uint64{1}, true, true, testPrivKey)
 
-	mock.CheckBalance(t, mapp, testAddr, cs(c(StableDenom, 10), c("xrp", 50)))
+	mock.CheckBalance(t, mapp, testAddr, cs(c(stableDenom, 20), c("xrp", 50)))
 
 	// Delete CDP
-	msgs = []sdk.Msg{NewMsgCreateOrModifyCDP(testAddr, "xrp", i(-50), i(-10))}
+	msgs = []sdk.Msg{NewMsgCreateOrModifyCDP(testAddr, ftCollateral("xrp", -50), liq(stableDenom, -20))}
 	mock.SignCheckDeliver(t, mapp.Cdc, mapp.BaseApp, abci.Header{Height: mapp.LastBlockHeight() + 1}, msgs, []uint64{0}, []uint64{2}, true, true, testPrivKey)
 
 	mock.CheckBalance(t, mapp, testAddr, cs(c("xrp", 100)))
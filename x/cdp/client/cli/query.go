@@ -58,15 +58,22 @@ func GetCmd_GetCdp(queryRoute string, cdc *codec.Codec) *cobra.Command {
 
 func GetCmd_GetCdps(queryRoute string, cdc *codec.Codec) *cobra.Command {
 	return &cobra.Command{
-		Use:   "cdps [collateralName]",
+		Use:   "cdps [collateralName] [collateralID]",
 		Short: "get info about many cdps",
-		Long:  "Get all CDPs or specify a collateral type to get only CDPs with that collateral type.",
-		Args:  cobra.MaximumNArgs(1),
+		Long:  "Get all CDPs, specify a collateral type to get only CDPs with that collateral type, or also an NFT ID to get only CDPs backed by that token.",
+		Args:  cobra.MaximumNArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cliCtx := context.NewCLIContext().WithCodec(cdc)
 
 			// Prepare params for querier
-			bz, err := cdc.MarshalJSON(cdp.QueryCdpsParams{CollateralName: args[0]}) // denom="" returns all CDPs // TODO will this fail if there are no args?
+			params := cdp.QueryCdpsParams{UnderCollateralizedAt: sdk.NewInt(-1)} // denom="" returns all CDPs, negative price disables filtering
+			if len(args) > 0 {
+				params.CollateralName = args[0]
+			}
+			if len(args) > 1 {
+				params.NftID = args[1]
+			}
+			bz, err := cdc.MarshalJSON(params)
 			if err != nil {
 				return err
 			}
@@ -86,6 +93,41 @@ func GetCmd_GetCdps(queryRoute string, cdc *codec.Codec) *cobra.Command {
 	}
 }
 
+// GetCmd_GetOwnerCdps queries all the cdps of an address, whatever their collateral
+func GetCmd_GetOwnerCdps(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "owner-cdps [ownerAddress]",
+		Short: "get all the cdps of an owner",
+		Long:  "Get all the CDPs belonging to an address, both the ones backed by fungible tokens and the ones backed by NFTs.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			// Prepare params for querier
+			ownerAddress, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+			bz, err := cdc.MarshalJSON(cdp.QueryOwnerCdpsParams{Owner: ownerAddress})
+			if err != nil {
+				return err
+			}
+
+			// Query
+			route := fmt.Sprintf("custom/%s/%s", queryRoute, cdp.QueryGetOwnerCdps)
+			res, err := cliCtx.QueryWithData(route, bz)
+			if err != nil {
+				return err
+			}
+
+			// Decode and print results
+			var out types.CDPs
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
+
 func GetCmd_GetUnderCollateralizedCdps(queryRoute string, cdc *codec.Codec) *cobra.Command {
 	return &cobra.Command{
 		Use:   "bad-cdps [collateralName] [price]",
@@ -101,3 +101,169 @@ func GetCmdModifyNftCdp(cdc *codec.Codec) *cobra.Command {
 		},
 	}
 }
+
+// collateralToken builds the token identifying a cdp collateral type, an id makes it a NFT.
+func collateralToken(name string, optionalID []string) types.Token {
+	if len(optionalID) > 0 {
+		return cdp.NewCollateralToken(name, optionalID[0])
+	}
+	return cdp.NewCollateralToken(name, "")
+}
+
+// GetCmdDepositCollateral cli command for adding collateral to a cdp, creating it if needed.
+func GetCmdDepositCollateral(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "deposit [collateralName] [collateralQuantity] [liquidityName] [collateralId]",
+		Short: "deposit collateral into a cdp",
+		Long:  "Deposit collateral into a cdp, creating it if it doesn't exist. The collateral id is only needed for NFT collateral.",
+		Args:  cobra.RangeArgs(3, 4),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc).WithAccountDecoder(cdc)
+			txBldr := authtxb.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			collateralQuantity, ok := sdk.NewIntFromString(args[1])
+			if !ok {
+				return fmt.Errorf("invalid collateral amount - %s", args[1])
+			}
+			collateral := types.Collateral{Token: collateralToken(args[0], args[3:]), Amount: collateralQuantity}
+
+			msg := cdp.NewMsgDepositCollateral(cliCtx.GetFromAddress(), collateral, args[2])
+			err := msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+// GetCmdWithdrawCollateral cli command for removing collateral from a cdp.
+func GetCmdWithdrawCollateral(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "withdraw [collateralName] [collateralQuantity] [liquidityName] [collateralId]",
+		Short: "withdraw collateral from a cdp",
+		Long:  "Withdraw collateral from a cdp. The collateral id is only needed for NFT collateral.",
+		Args:  cobra.RangeArgs(3, 4),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc).WithAccountDecoder(cdc)
+			txBldr := authtxb.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			collateralQuantity, ok := sdk.NewIntFromString(args[1])
+			if !ok {
+				return fmt.Errorf("invalid collateral amount - %s", args[1])
+			}
+			collateral := types.Collateral{Token: collateralToken(args[0], args[3:]), Amount: collateralQuantity}
+
+			msg := cdp.NewMsgWithdrawCollateral(cliCtx.GetFromAddress(), collateral, args[2])
+			err := msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+// GetCmdDrawDebt cli command for drawing stable coin from a cdp.
+func GetCmdDrawDebt(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "draw [collateralName] [liquidity] [collateralId]",
+		Short: "draw stable coin from a cdp",
+		Long:  "Draw stable coin (eg 100uatom) from a cdp, increasing its debt. The collateral id is only needed for NFT collateral.",
+		Args:  cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc).WithAccountDecoder(cdc)
+			txBldr := authtxb.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			liquidity, err := sdk.ParseCoin(args[1])
+			if err != nil {
+				return err
+			}
+
+			msg := cdp.NewMsgDrawDebt(cliCtx.GetFromAddress(), collateralToken(args[0], args[2:]), liquidity)
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+// GetCmdRepayDebt cli command for paying stable coin back to a cdp.
+func GetCmdRepayDebt(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "repay [collateralName] [liquidity] [collateralId]",
+		Short: "repay stable coin to a cdp",
+		Long:  "Repay stable coin (eg 100uatom) to a cdp, reducing its debt. Stability fees are paid first. The collateral id is only needed for NFT collateral.",
+		Args:  cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc).WithAccountDecoder(cdc)
+			txBldr := authtxb.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			liquidity, err := sdk.ParseCoin(args[1])
+			if err != nil {
+				return err
+			}
+
+			msg := cdp.NewMsgRepayDebt(cliCtx.GetFromAddress(), collateralToken(args[0], args[2:]), liquidity)
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+// GetCmdTransferCdp cli command for transferring a cdp to another owner.
+func GetCmdTransferCdp(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "transfer [receiverAddress] [collateralName] [collateralId]",
+		Short: "transfer a cdp to another owner",
+		Long:  "Transfer a cdp, with its collateral and debt, to another owner. The collateral id is only needed for NFT collateral.",
+		Args:  cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc).WithAccountDecoder(cdc)
+			txBldr := authtxb.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			receiver, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+
+			msg := cdp.NewMsgTransferCDP(cliCtx.GetFromAddress(), receiver, collateralToken(args[1], args[2:]))
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+// GetCmdCloseCdp cli command for closing a cdp.
+func GetCmdCloseCdp(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "close [collateralName] [collateralId]",
+		Short: "close a cdp",
+		Long:  "Close a cdp, repaying all its debt including the stability fees and getting back all its collateral. The collateral id is only needed for NFT collateral.",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc).WithAccountDecoder(cdc)
+			txBldr := authtxb.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			nftID := ""
+			if len(args) > 1 {
+				nftID = args[1]
+			}
+
+			msg := cdp.NewMsgCloseCDP(cliCtx.GetFromAddress(), args[0], nftID)
+			err := msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
@@ -30,6 +30,7 @@ func (mc ModuleClient) GetQueryCmd() *cobra.Command {
 	cdpQueryCmd.AddCommand(client.GetCommands(
 		cdpcmd.GetCmd_GetCdp(mc.storeKey, mc.cdc),
 		cdpcmd.GetCmd_GetCdps(mc.storeKey, mc.cdc),
+		cdpcmd.GetCmd_GetOwnerCdps(mc.storeKey, mc.cdc),
 		cdpcmd.GetCmd_GetUnderCollateralizedCdps(mc.storeKey, mc.cdc),
 		cdpcmd.GetCmd_GetParams(mc.storeKey, mc.cdc),
 	)...)
@@ -47,6 +48,12 @@ func (mc ModuleClient) GetTxCmd() *cobra.Command {
 	cdpTxCmd.AddCommand(client.PostCommands(
 		cdpcmd.GetCmdModifyFtCdp(mc.cdc),
 		cdpcmd.GetCmdModifyNftCdp(mc.cdc),
+		cdpcmd.GetCmdDepositCollateral(mc.cdc),
+		cdpcmd.GetCmdWithdrawCollateral(mc.cdc),
+		cdpcmd.GetCmdDrawDebt(mc.cdc),
+		cdpcmd.GetCmdRepayDebt(mc.cdc),
+		cdpcmd.GetCmdTransferCdp(mc.cdc),
+		cdpcmd.GetCmdCloseCdp(mc.cdc),
 	)...)
 
 	return cdpTxCmd
@@ -1,21 +1,92 @@
 package rest
 
-//
-//import (
-//	"fmt"
-//	"net/http"
-//
-//	"github.com/cosmos/cosmos-sdk/client/context"
-//	"github.com/cosmos/cosmos-sdk/codec"
-//	"github.com/cosmos/cosmos-sdk/types/rest"
-//	"github.com/gorilla/mux"
-//
-//	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/cdp"
-//
-//	clientrest "github.com/cosmos/cosmos-sdk/client/rest"
-//	sdk "github.com/cosmos/cosmos-sdk/types"
-//)
-//
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/cdp"
+	"github.com/cosmos/cosmos-sdk/client/context"
+	clientrest "github.com/cosmos/cosmos-sdk/client/rest"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/types/rest"
+	"github.com/gorilla/mux"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	restOwnerAddress = "ownerAddress"
+)
+
+// RegisterRoutes - Central function to define routes that get registered by the main application
+func RegisterRoutes(cliCtx context.CLIContext, r *mux.Router, cdc *codec.Codec, storeName string) {
+	r.HandleFunc(fmt.Sprintf("/%s/owners/{%s}/cdps", storeName, restOwnerAddress), getOwnerCdpsHandlerFn(cdc, cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/owners/{%s}/cdps/transfer", storeName, restOwnerAddress), transferCdpHandlerFn(cdc, cliCtx)).Methods("POST")
+}
+
+func getOwnerCdpsHandlerFn(cdc *codec.Codec, cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		owner, err := sdk.AccAddressFromBech32(vars[restOwnerAddress])
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		querierParamsBz, err := cdc.MarshalJSON(cdp.QueryOwnerCdpsParams{Owner: owner})
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		res, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, cdp.QueryGetOwnerCdps), querierParamsBz)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+		rest.PostProcessResponse(w, cdc, res, cliCtx.Indent)
+	}
+}
+
+type TransferCdpRequest struct {
+	BaseReq        rest.BaseReq   `json:"base_req"`
+	Receiver       sdk.AccAddress `json:"receiver"`
+	CollateralName string         `json:"collateral_name"`
+	CollateralID   string         `json:"collateral_id"` // only set for NFT collateral
+}
+
+func transferCdpHandlerFn(cdc *codec.Codec, cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		owner, err := sdk.AccAddressFromBech32(vars[restOwnerAddress])
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// Get args from post body
+		var req TransferCdpRequest
+		if !rest.ReadRESTReq(w, r, cdc, &req) {
+			return
+		}
+		req.BaseReq = req.BaseReq.Sanitize()
+		if !req.BaseReq.ValidateBasic(w) {
+			return
+		}
+
+		// Create msg
+		msg := cdp.NewMsgTransferCDP(owner, req.Receiver, cdp.NewCollateralToken(req.CollateralName, req.CollateralID))
+		if err := msg.ValidateBasic(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// Generate tx and write response
+		clientrest.WriteGenerateStdTxResponse(w, cdc, cliCtx, req.BaseReq, []sdk.Msg{msg})
+	}
+}
+
+// TODO port the handlers below to the current CDP types and register them
 ///*
 //API Design:
 //
@@ -29,13 +100,6 @@ package rest
 //	GET /params
 //*/
 //
-//// RegisterRoutes - Central function to define routes that get registered by the main application
-//func RegisterRoutes(cliCtx context.CLIContext, r *mux.Router, cdc *codec.Codec) {
-//	r.HandleFunc("/cdps", getCdpsHandlerFn(cdc, cliCtx)).Methods("GET")
-//	r.HandleFunc("/cdps", modifyCdpHandlerFn(cdc, cliCtx)).Methods("PUT")
-//	r.HandleFunc("/cdps/params", getParamsHandlerFn(cdc, cliCtx)).Methods("GET")
-//}
-//
 //const (
 //	RestOwner                 = "owner"
 //	RestCollateralDenom       = "collateralDenom"
@@ -1,6 +1,9 @@
 package cdp
 
-import "github.com/cosmos/cosmos-sdk/codec"
+import (
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
+	"github.com/cosmos/cosmos-sdk/codec"
+)
 
 // generic sealed codec to be used throughout module
 var moduleCdc *codec.Codec
@@ -15,5 +18,15 @@ func init() {
 // RegisterCodec registers concrete types on the codec.
 func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgCreateOrModifyCDP{}, "cdp/MsgCreateOrModifyCDP", nil)
+	cdc.RegisterConcrete(MsgDepositCollateral{}, "cdp/MsgDepositCollateral", nil)
+	cdc.RegisterConcrete(MsgWithdrawCollateral{}, "cdp/MsgWithdrawCollateral", nil)
+	cdc.RegisterConcrete(MsgDrawDebt{}, "cdp/MsgDrawDebt", nil)
+	cdc.RegisterConcrete(MsgRepayDebt{}, "cdp/MsgRepayDebt", nil)
 	cdc.RegisterConcrete(MsgTransferCDP{}, "cdp/MsgTransferCDP", nil)
+	cdc.RegisterConcrete(MsgCloseCDP{}, "cdp/MsgCloseCDP", nil)
+
+	// collateral tokens
+	cdc.RegisterInterface((*types.Token)(nil), nil)
+	cdc.RegisterConcrete(BaseFT{}, "cdp/BaseFT", nil)
+	cdc.RegisterConcrete(BaseNFT{}, "cdp/BaseNFT", nil)
 }
@@ -4,7 +4,7 @@ Package CDP manages the storage of Collateralized Debt Positions. It handles the
 Notes
  - sdk.Int is used for all the number types to maintain compatibility with internal type of sdk.Coin - saves type conversion when doing maths.
    Also it allows for changes to a CDP to be expressed as a +ve or -ve number.
- - Only allowing one CDP per account-collateralDenom pair for now to keep things simple.
+ - Only allowing one CDP per account-collateralDenom pair for fungible collateral, and one per account-collateralDenom-nftID triple for NFT collateral.
  - Genesis forces the global debt to start at zero, ie no stable coins in existence. This could be changed.
  - The cdp module fulfills the bank keeper interface and keeps track of the liquidator module's coins. This won't be needed with module accounts.
  - GetCDPs does not return an iterator, but instead reads out (potentially) all CDPs from the store. This isn't a huge performance concern as it is never used during a block, only for querying.
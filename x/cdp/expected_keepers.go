@@ -10,3 +10,10 @@ type bankKeeper interface {
 	AddCoins(sdk.Context, sdk.AccAddress, sdk.Coins) (sdk.Coins, sdk.Error)
 	SubtractCoins(sdk.Context, sdk.AccAddress, sdk.Coins) (sdk.Coins, sdk.Error)
 }
+
+type poolKeeper interface {
+	GetDrawableLiquidity(sdk.Context, string) (sdk.Int, sdk.Error)
+	DrawLiquidity(sdk.Context, sdk.AccAddress, sdk.Coin) sdk.Error
+	RepayLiquidity(sdk.Context, sdk.AccAddress, sdk.Coin) sdk.Error
+	PayFees(sdk.Context, sdk.AccAddress, sdk.Coin) sdk.Error
+}
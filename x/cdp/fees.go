@@ -0,0 +1,79 @@
+package cdp
+
+import (
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// BlocksPerYear is used to turn the annual stability fee into a per block rate. It assumes 5 second blocks.
+const BlocksPerYear int64 = 6311520
+
+// calculateFees returns the fees accrued by some debt over a number of blocks, compounding the annual stability fee every block.
+func calculateFees(debt sdk.Int, stabilityFee sdk.Dec, blocks int64) sdk.Int {
+	if !debt.IsPositive() || !stabilityFee.IsPositive() || blocks <= 0 {
+		return sdk.ZeroInt()
+	}
+	blockRate := sdk.OneDec().Add(stabilityFee.QuoInt64(BlocksPerYear))
+	compounded := sdk.NewDecFromInt(debt).Mul(power(blockRate, blocks))
+	return compounded.TruncateInt().Sub(debt)
+}
+
+// power raises a decimal to an integer power by repeated squaring.
+func power(base sdk.Dec, exponent int64) sdk.Dec {
+	result := sdk.OneDec()
+	for ; exponent > 0; exponent >>= 1 {
+		if exponent&1 == 1 {
+			result = result.Mul(base)
+		}
+		base = base.Mul(base)
+	}
+	return result
+}
+
+// accumulateFees adds the stability fees accrued since the last update of a CDP to its debt.
+// It returns the updated CDP and the fees added, leaving it to the caller to store the CDP and update the debt totals.
+func (k Keeper) accumulateFees(ctx sdk.Context, cdp types.CDP) (types.CDP, sdk.Int) {
+	stabilityFee := k.GetParams(ctx).GetCollateralParams(cdp.Collateral.Token.GetName()).StabilityFee
+	fees := sdk.ZeroInt()
+	if !stabilityFee.IsNil() {
+		fees = calculateFees(cdp.Liquidity.Coin.Amount, stabilityFee, ctx.BlockHeight()-cdp.FeesUpdated)
+	}
+	cdp.Liquidity.Coin.Amount = cdp.Liquidity.Coin.Amount.Add(fees)
+	cdp.AccumulatedFees = cdp.AccumulatedFees.Add(fees)
+	cdp.FeesUpdated = ctx.BlockHeight()
+	return cdp, fees
+}
+
+// payFees takes the part of a debt repayment covering the accumulated fees of a CDP.
+// It returns the updated CDP and the fees paid, leaving it to the caller to store the CDP and collect the fees.
+func payFees(cdp types.CDP, repayment sdk.Int) (types.CDP, sdk.Int) {
+	feesPaid := sdk.MinInt(repayment, cdp.AccumulatedFees)
+	if feesPaid.IsNegative() {
+		return cdp, sdk.ZeroInt()
+	}
+	cdp.AccumulatedFees = cdp.AccumulatedFees.Sub(feesPaid)
+	return cdp, feesPaid
+}
+
+// DefaultPoolFeeShare is the part of the stability fees paid to the pool when the params don't set it, as in stores created before it was a param
+var DefaultPoolFeeShare = sdk.MustNewDecFromStr("0.5")
+
+// splitFees splits the stability fees paid by a CDP owner between the pool, where they raise the share price of the depositors lending
+// the stable coin, and the liquidator module account, where they count as surplus to be auctioned off. The pool part is rounded down.
+func (k Keeper) splitFees(ctx sdk.Context, fees sdk.Int) (sdk.Int, sdk.Int) {
+	share := k.GetParams(ctx).PoolFeeShare
+	if share.IsNil() {
+		share = DefaultPoolFeeShare
+	}
+	poolFees := sdk.NewDecFromInt(fees).Mul(share).TruncateInt()
+	return poolFees, fees.Sub(poolFees)
+}
+
+// collectFees sends the liquidator part of the fees paid by a CDP owner to the liquidator module account, where they count as surplus.
+func (k Keeper) collectFees(ctx sdk.Context, denom string, fees sdk.Int) sdk.Error {
+	if !fees.IsPositive() {
+		return nil
+	}
+	_, err := k.AddCoins(ctx, LiquidatorAccountAddress, sdk.NewCoins(sdk.NewCoin(denom, fees)))
+	return err
+}
@@ -0,0 +1,68 @@
+package cdp
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/mock"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+func TestCalculateFees(t *testing.T) {
+	tests := []struct {
+		name         string
+		debt         sdk.Int
+		stabilityFee sdk.Dec
+		blocks       int64
+		expectedFees sdk.Int
+	}{
+		{"noBlocks", i(1000000), d("0.05"), 0, i(0)},
+		{"noDebt", i(0), d("0.05"), 100, i(0)},
+		{"noFee", i(1000000), d("0"), 100, i(0)},
+		{"oneYear", i(1000000), d("0.05"), BlocksPerYear, i(51271)}, // continuous compounding gives e^0.05 - 1 ~ 5.127%
+		{"halfYear", i(1000000), d("0.05"), BlocksPerYear / 2, i(25315)},
+		{"oneBlock", i(1000000000000), d("0.05"), 1, i(7922)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedFees, calculateFees(tc.debt, tc.stabilityFee, tc.blocks))
+		})
+	}
+}
+
+func TestKeeper_AccumulatePayCollectFees(t *testing.T) {
+	// setup keeper
+	mapp, keeper := setUpMockAppWithoutGenesis()
+	mock.SetGenesis(mapp, []auth.Account(nil))
+	header := abci.Header{Height: mapp.LastBlockHeight() + 1}
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := mapp.BaseApp.NewContext(false, header).WithBlockHeight(BlocksPerYear + 1)
+	_, addrs := mock.GeneratePrivKeyAddressPairs(1)
+	cdp := ftCDP(addrs[0], "xrp", 4000, 1000000)
+	cdp.FeesUpdated = ctx.BlockHeight() - BlocksPerYear
+
+	// a year of fees is added to the debt
+	cdp, fees := keeper.accumulateFees(ctx, cdp)
+	require.Equal(t, i(51271), fees)
+	require.Equal(t, i(1051271), cdp.Liquidity.Coin.Amount)
+	require.Equal(t, i(51271), cdp.AccumulatedFees)
+	require.Equal(t, ctx.BlockHeight(), cdp.FeesUpdated)
+
+	// no more fees within the same block
+	cdp, fees = keeper.accumulateFees(ctx, cdp)
+	require.Equal(t, i(0), fees)
+
+	// repayments pay the fees first
+	cdp, feesPaid := payFees(cdp, i(50000))
+	require.Equal(t, i(50000), feesPaid)
+	require.Equal(t, i(1271), cdp.AccumulatedFees)
+	cdp, feesPaid = payFees(cdp, i(50000))
+	require.Equal(t, i(1271), feesPaid)
+	require.True(t, cdp.AccumulatedFees.IsZero())
+
+	// collected fees end up in the liquidator module account
+	require.NoError(t, keeper.collectFees(ctx, stableDenom, i(51271)))
+	require.Equal(t, cs(c(stableDenom, 51271)), keeper.GetCoins(ctx, LiquidatorAccountAddress))
+}
@@ -1,15 +1,19 @@
 package cdp
 
 import (
+	"fmt"
+
 	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
 // GenesisState is the state that must be provided at genesis.
 type GenesisState struct {
-	CdpModuleParams types.CdpModuleParams `json:"params"`
-	GlobalDebt      sdk.Int               `json:"global_debt"`
-	// don't need to setup CollateralStates as they are created as needed
+	CdpModuleParams         types.CdpModuleParams   `json:"params"`
+	GlobalDebt              sdk.Int                 `json:"global_debt"`
+	CDPs                    types.CDPs              `json:"cdps"`
+	CollateralStates        []types.CollateralState `json:"collateral_states"` // only needed for collateral types that have been used
+	LiquidatorModuleAccount LiquidatorModuleAccount `json:"liquidator_module_account"`
 }
 
 // DefaultGenesisState returns a default genesis state
@@ -17,45 +21,206 @@ func DefaultGenesisState() GenesisState {
 	return GenesisState{
 		types.CdpModuleParams{
 			GlobalDebtLimit: sdk.NewInt(1000000),
+			StableDenom:     DefaultStableDenom,
+			PoolFeeShare:    DefaultPoolFeeShare,
 			CollateralParams: []types.CollateralParams{
 				{
 					Denom:            "btc",
 					LiquidationRatio: sdk.MustNewDecFromStr("1.5"),
 					DebtLimit:        sdk.NewInt(500000),
+					StabilityFee:     sdk.MustNewDecFromStr("0.05"),
+					DebtFloor:        sdk.NewInt(10),
 				},
 				{
 					Denom:            "xrp",
 					LiquidationRatio: sdk.MustNewDecFromStr("2.0"),
 					DebtLimit:        sdk.NewInt(500000),
+					StabilityFee:     sdk.MustNewDecFromStr("0.05"),
+					DebtFloor:        sdk.NewInt(10),
 				},
 			},
 		},
 		sdk.ZeroInt(),
+		types.CDPs{},
+		[]types.CollateralState{},
+		LiquidatorModuleAccount{Coins: sdk.NewCoins()},
 	}
 }
 
 // InitGenesis sets the genesis state in the keeper.
 func InitGenesis(ctx sdk.Context, keeper Keeper, data GenesisState) {
+	if data.CdpModuleParams.StableDenom == "" {
+		data.CdpModuleParams.StableDenom = DefaultStableDenom // genesis files from before the stable denom was a param
+	}
+	if data.CdpModuleParams.PoolFeeShare.IsNil() {
+		data.CdpModuleParams.PoolFeeShare = DefaultPoolFeeShare
+	}
 	keeper.setParams(ctx, data.CdpModuleParams)
 	keeper.setGlobalDebt(ctx, data.GlobalDebt)
+	for _, cdp := range data.CDPs {
+		keeper.setCDP(ctx, cdp)
+	}
+	for _, collateralState := range data.CollateralStates {
+		keeper.setCollateralState(ctx, collateralState)
+	}
+	keeper.setLiquidatorModuleAccount(ctx, data.LiquidatorModuleAccount)
+	keeper.setCDPKeysMigrated(ctx) // a new store has no legacy CDP keys
 }
 
 // ValidateGenesis performs basic validation of genesis data returning an
 // error for any failed validation criteria.
 func ValidateGenesis(data GenesisState) error {
-	// TODO implement this
-	// validate denoms
-	// check collateral debt limits sum to global limit?
-	// check limits are > 0
-	// check ratios are > 1
-	// check no repeated denoms
-
-	// check global debt is zero - force the chain to always start with zero stable coin, otherwise collateralStatus's will need to be set up as well.
+	params := data.CdpModuleParams
+	if params.GlobalDebtLimit == (sdk.Int{}) || !params.GlobalDebtLimit.IsPositive() {
+		return fmt.Errorf("global debt limit must be positive")
+	}
+	stableDenom := params.StableDenom
+	if stableDenom == "" {
+		stableDenom = DefaultStableDenom
+	}
+	if !(sdk.Coins{{Denom: stableDenom, Amount: sdk.OneInt()}}).IsValid() || stableDenom == GovDenom {
+		return fmt.Errorf("invalid stable denom %q", stableDenom)
+	}
+	if !params.PoolFeeShare.IsNil() && (params.PoolFeeShare.IsNegative() || params.PoolFeeShare.GT(sdk.OneDec())) {
+		return fmt.Errorf("pool fee share must be between 0 and 1")
+	}
+	denoms := make(map[string]bool)
+	for _, cp := range params.CollateralParams {
+		if err := ValidateCollateralParams(cp); err != nil {
+			return err
+		}
+		if cp.Denom == stableDenom {
+			return fmt.Errorf("stable denom %s can't be a collateral", stableDenom)
+		}
+		if denoms[cp.Denom] {
+			return fmt.Errorf("collateral %s has repeated params", cp.Denom)
+		}
+		denoms[cp.Denom] = true
+		if cp.DebtLimit.GT(params.GlobalDebtLimit) {
+			return fmt.Errorf("debt limit for collateral %s is above the global debt limit", cp.Denom)
+		}
+	}
+
+	if data.GlobalDebt == (sdk.Int{}) || data.GlobalDebt.IsNegative() {
+		return fmt.Errorf("global debt must be set and not negative")
+	}
+
+	// Sum up the CDP debts for each collateral type, checking every CDP is unique and uses an authorized collateral
+	cdpDebts := make(map[string]sdk.Int)
+	cdpFees := make(map[string]sdk.Int)
+	cdpKeys := make(map[string]bool)
+	for _, cdp := range data.CDPs {
+		if cdp.Collateral.Token == nil || cdp.Owner.Empty() {
+			return fmt.Errorf("cdp must have an owner and a collateral token")
+		}
+		denom := cdp.Collateral.Token.GetName()
+		if !params.IsCollateralPresent(denom) {
+			return fmt.Errorf("cdp of %s uses unauthorized collateral %s", cdp.Owner, denom)
+		}
+		var nftID string
+		if nft, ok := cdp.Collateral.Token.(NFT); ok {
+			nftID = nft.GetID()
+		}
+		key := fmt.Sprintf("%s:%s:%s", denom, nftID, cdp.Owner)
+		if cdpKeys[key] {
+			return fmt.Errorf("duplicate cdp of %s for collateral %s", cdp.Owner, denom)
+		}
+		cdpKeys[key] = true
+		if cdp.Liquidity.Coin.Denom != stableDenom {
+			return fmt.Errorf("cdp of %s for collateral %s has debt in %s, not the stable denom", cdp.Owner, denom, cdp.Liquidity.Coin.Denom)
+		}
+		if cdp.Collateral.Amount.IsNegative() || cdp.Liquidity.Coin.Amount.IsNegative() || cdp.AccumulatedFees.IsNegative() {
+			return fmt.Errorf("cdp of %s for collateral %s has negative amounts", cdp.Owner, denom)
+		}
+
+		if _, ok := cdpDebts[denom]; !ok {
+			cdpDebts[denom] = sdk.ZeroInt()
+			cdpFees[denom] = sdk.ZeroInt()
+		}
+		cdpDebts[denom] = cdpDebts[denom].Add(cdp.Liquidity.Coin.Amount)
+		cdpFees[denom] = cdpFees[denom].Add(cdp.AccumulatedFees)
+	}
+
+	// Check the collateral states match the CDPs and add up to no more than the global debt
+	// The global debt can be higher as it also includes the debt seized by the liquidator
+	totalDebt := sdk.ZeroInt()
+	states := make(map[string]bool)
+	for _, cs := range data.CollateralStates {
+		if states[cs.Denom] {
+			return fmt.Errorf("duplicate collateral state for %s", cs.Denom)
+		}
+		states[cs.Denom] = true
+		debt, fees := sdk.ZeroInt(), sdk.ZeroInt()
+		if _, ok := cdpDebts[cs.Denom]; ok {
+			debt, fees = cdpDebts[cs.Denom], cdpFees[cs.Denom]
+		}
+		if cs.TotalDebt == (sdk.Int{}) || !cs.TotalDebt.Equal(debt) {
+			return fmt.Errorf("total debt of collateral %s is %s but its cdps owe %s", cs.Denom, cs.TotalDebt, debt)
+		}
+		if cs.AccumulatedFees == (sdk.Int{}) || !cs.AccumulatedFees.Equal(fees) {
+			return fmt.Errorf("accumulated fees of collateral %s are %s but its cdps owe %s", cs.Denom, cs.AccumulatedFees, fees)
+		}
+		totalDebt = totalDebt.Add(cs.TotalDebt)
+	}
+	for denom := range cdpDebts {
+		if !states[denom] {
+			return fmt.Errorf("missing collateral state for %s", denom)
+		}
+	}
+	if totalDebt.GT(data.GlobalDebt) {
+		return fmt.Errorf("global debt %s is less than the collateral debts %s", data.GlobalDebt, totalDebt)
+	}
+
+	if !data.LiquidatorModuleAccount.Coins.IsValid() {
+		return fmt.Errorf("invalid liquidator module account coins %s", data.LiquidatorModuleAccount.Coins)
+	}
+	return nil
+}
+
+// ValidateCollateralParams checks the params of a collateral type on their own,
+// whether its debt limit fits in the global one depends on the other params.
+func ValidateCollateralParams(cp types.CollateralParams) error {
+	if !(sdk.Coins{{Denom: cp.Denom, Amount: sdk.OneInt()}}).IsValid() {
+		return fmt.Errorf("invalid collateral denom %q", cp.Denom)
+	}
+	if cp.LiquidationRatio.IsNil() || !cp.LiquidationRatio.GT(sdk.OneDec()) {
+		return fmt.Errorf("liquidation ratio for collateral %s must be above 1", cp.Denom)
+	}
+	if cp.StabilityFee.IsNil() || cp.StabilityFee.IsNegative() {
+		return fmt.Errorf("stability fee for collateral %s must be set and not negative", cp.Denom)
+	}
+	if cp.DebtLimit == (sdk.Int{}) || !cp.DebtLimit.IsPositive() {
+		return fmt.Errorf("debt limit for collateral %s must be positive", cp.Denom)
+	}
+	if cp.DebtFloor == (sdk.Int{}) || cp.DebtFloor.IsNegative() {
+		return fmt.Errorf("debt floor for collateral %s must be set and not negative", cp.Denom)
+	}
+	if cp.DebtFloor.GT(cp.DebtLimit) {
+		return fmt.Errorf("debt floor for collateral %s is above its debt limit", cp.Denom)
+	}
 	return nil
 }
 
 // ExportGenesis returns a GenesisState for a given context and keeper.
 func ExportGenesis(ctx sdk.Context, keeper Keeper) GenesisState {
-	// TODO implement this
-	return DefaultGenesisState()
+	params := keeper.GetParams(ctx)
+	cdps, err := keeper.GetCDPs(ctx, "", "", sdk.NewInt(-1))
+	if err != nil {
+		panic(err)
+	}
+	// Collateral states are stored under their denom, so look them up for each authorized collateral type
+	collateralStates := []types.CollateralState{}
+	for _, cp := range params.CollateralParams {
+		collateralState, found := keeper.GetCollateralState(ctx, cp.Denom)
+		if found {
+			collateralStates = append(collateralStates, collateralState)
+		}
+	}
+	return GenesisState{
+		CdpModuleParams:         params,
+		GlobalDebt:              keeper.GetGlobalDebt(ctx),
+		CDPs:                    cdps,
+		CollateralStates:        collateralStates,
+		LiquidatorModuleAccount: keeper.getLiquidatorModuleAccount(ctx),
+	}
 }
@@ -0,0 +1,154 @@
+package cdp
+
+import (
+	"testing"
+
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/mock"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+func TestValidateGenesis(t *testing.T) {
+	tests := []struct {
+		name       string
+		debtFloor  sdk.Int
+		expectPass bool
+	}{
+		{"default", i(10), true},
+		{"zero", i(0), true},
+		{"unset", sdk.Int{}, false},
+		{"negative", i(-1), false},
+		{"aboveDebtLimit", i(500001), false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			genesis := DefaultGenesisState()
+			genesis.CdpModuleParams.CollateralParams[0].DebtFloor = tc.debtFloor
+			err := ValidateGenesis(genesis)
+			if tc.expectPass {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateGenesis_Params(t *testing.T) {
+	tests := []struct {
+		name       string
+		modify     func(*types.CdpModuleParams)
+		expectPass bool
+	}{
+		{"default", func(*types.CdpModuleParams) {}, true},
+		{"zeroGlobalDebtLimit", func(p *types.CdpModuleParams) { p.GlobalDebtLimit = i(0) }, false},
+		{"invalidDenom", func(p *types.CdpModuleParams) { p.CollateralParams[0].Denom = "" }, false},
+		{"repeatedDenom", func(p *types.CdpModuleParams) { p.CollateralParams[1].Denom = "btc" }, false},
+		{"liquidationRatioOfOne", func(p *types.CdpModuleParams) { p.CollateralParams[0].LiquidationRatio = d("1.0") }, false},
+		{"negativeStabilityFee", func(p *types.CdpModuleParams) { p.CollateralParams[0].StabilityFee = d("-0.01") }, false},
+		{"zeroDebtLimit", func(p *types.CdpModuleParams) { p.CollateralParams[0].DebtLimit = i(0) }, false},
+		{"debtLimitAboveGlobal", func(p *types.CdpModuleParams) { p.CollateralParams[0].DebtLimit = i(1000001) }, false},
+		{"noStableDenom", func(p *types.CdpModuleParams) { p.StableDenom = "" }, true},
+		{"noPoolFeeShare", func(p *types.CdpModuleParams) { p.PoolFeeShare = sdk.Dec{} }, true},
+		{"negativePoolFeeShare", func(p *types.CdpModuleParams) { p.PoolFeeShare = d("-0.1") }, false},
+		{"poolFeeShareAboveOne", func(p *types.CdpModuleParams) { p.PoolFeeShare = d("1.1") }, false},
+		{"govStableDenom", func(p *types.CdpModuleParams) { p.StableDenom = GovDenom }, false},
+		{"collateralStableDenom", func(p *types.CdpModuleParams) { p.StableDenom = "btc" }, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			genesis := DefaultGenesisState()
+			tc.modify(&genesis.CdpModuleParams)
+			err := ValidateGenesis(genesis)
+			if tc.expectPass {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateGenesis_CDPs(t *testing.T) {
+	_, addrs := mock.GeneratePrivKeyAddressPairs(2)
+	validGenesis := func() GenesisState {
+		genesis := DefaultGenesisState()
+		genesis.GlobalDebt = i(500)
+		genesis.CDPs = types.CDPs{ftCDP(addrs[0], "btc", 10, 300), ftCDP(addrs[1], "btc", 10, 100)}
+		genesis.CDPs[0].AccumulatedFees = i(20)
+		genesis.CollateralStates = []types.CollateralState{{Denom: "btc", TotalDebt: i(400), AccumulatedFees: i(20), CollectedFees: i(5)}}
+		return genesis
+	}
+	tests := []struct {
+		name       string
+		modify     func(*GenesisState)
+		expectPass bool
+	}{
+		{"valid", func(*GenesisState) {}, true},
+		{"seizedDebtInGlobalDebt", func(g *GenesisState) { g.GlobalDebt = i(1000) }, true},
+		{"globalDebtTooLow", func(g *GenesisState) { g.GlobalDebt = i(399) }, false},
+		{"totalDebtMismatch", func(g *GenesisState) { g.CollateralStates[0].TotalDebt = i(401) }, false},
+		{"feesMismatch", func(g *GenesisState) { g.CollateralStates[0].AccumulatedFees = i(0) }, false},
+		{"missingCollateralState", func(g *GenesisState) { g.CollateralStates = nil }, false},
+		{"duplicateCDP", func(g *GenesisState) { g.CDPs[1].Owner = addrs[0] }, false},
+		{"unauthorizedCollateral", func(g *GenesisState) { g.CDPs[1] = ftCDP(addrs[1], "eth", 10, 100) }, false},
+		{"foreignLiquidityDenom", func(g *GenesisState) { g.CDPs[1].Liquidity.Coin.Denom = "xrp" }, false},
+		{"invalidModuleAccount", func(g *GenesisState) { g.LiquidatorModuleAccount.Coins = sdk.Coins{c(stableDenom, 1), c("btc", 1)} }, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			genesis := validGenesis()
+			tc.modify(&genesis)
+			err := ValidateGenesis(genesis)
+			if tc.expectPass {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestExportImportGenesis(t *testing.T) {
+	// Setup
+	mapp, keeper := setUpMockAppWithoutGenesis()
+	genAccs, addrs, _, _ := mock.CreateGenAccounts(2, cs(c("xrp", 1000), c("btc", 10)))
+	mock.SetGenesis(mapp, genAccs)
+	header := abci.Header{Height: mapp.LastBlockHeight() + 1}
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := mapp.BaseApp.NewContext(false, header)
+	setCurrentPrice(ctx, keeper, "xrp", 1)
+	setCurrentPrice(ctx, keeper, "btc", 100)
+	require.NoError(t, keeper.ModifyCDP(ctx, addrs[0], ftCollateral("xrp", 500), liq(stableDenom, 200)))
+	require.NoError(t, keeper.ModifyCDP(ctx, addrs[1], ftCollateral("btc", 5), liq(stableDenom, 100)))
+	keeper.AddCoins(ctx, keeper.GetLiquidatorAccountAddress(), cs(c(stableDenom, 42)))
+
+	// Run test function
+	exported := ExportGenesis(ctx, keeper)
+	require.NoError(t, ValidateGenesis(exported))
+	bz := moduleCdc.MustMarshalJSON(exported)
+	var imported GenesisState
+	moduleCdc.MustUnmarshalJSON(bz, &imported)
+
+	mapp2, keeper2 := setUpMockAppWithoutGenesis()
+	mock.SetGenesis(mapp2, genAccs)
+	header2 := abci.Header{Height: mapp2.LastBlockHeight() + 1}
+	mapp2.BeginBlock(abci.RequestBeginBlock{Header: header2})
+	ctx2 := mapp2.BaseApp.NewContext(false, header2)
+	InitGenesis(ctx2, keeper2, imported)
+
+	// Check
+	require.Len(t, exported.CDPs, 2)
+	require.Len(t, exported.CollateralStates, 2)
+	require.Equal(t, i(300), exported.GlobalDebt)
+	require.Equal(t, cs(c(stableDenom, 42)), exported.LiquidatorModuleAccount.Coins)
+	require.Equal(t, exported, ExportGenesis(ctx2, keeper2))
+	cdp, found := keeper2.GetCDP(ctx2, addrs[0], "xrp", "")
+	require.True(t, found)
+	expectedCDP := ftCDP(addrs[0], "xrp", 500, 200)
+	expectedCDP.FeesUpdated = header.Height
+	require.Equal(t, expectedCDP, cdp)
+	require.Len(t, keeper2.GetOwnerCDPs(ctx2, addrs[1]), 1)
+}
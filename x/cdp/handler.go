@@ -3,6 +3,7 @@ package cdp
 import (
 	"fmt"
 
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
@@ -12,6 +13,18 @@ func NewHandler(keeper Keeper) sdk.Handler {
 		switch msg := msg.(type) {
 		case MsgCreateOrModifyCDP:
 			return handleMsgCreateOrModifyCDP(ctx, keeper, msg)
+		case MsgDepositCollateral:
+			return handleMsgDepositCollateral(ctx, keeper, msg)
+		case MsgWithdrawCollateral:
+			return handleMsgWithdrawCollateral(ctx, keeper, msg)
+		case MsgDrawDebt:
+			return handleMsgDrawDebt(ctx, keeper, msg)
+		case MsgRepayDebt:
+			return handleMsgRepayDebt(ctx, keeper, msg)
+		case MsgTransferCDP:
+			return handleMsgTransferCDP(ctx, keeper, msg)
+		case MsgCloseCDP:
+			return handleMsgCloseCDP(ctx, keeper, msg)
 		default:
 			errMsg := fmt.Sprintf("Unrecognized cdp msg type: %T", msg)
 			return sdk.ErrUnknownRequest(errMsg).Result()
@@ -28,3 +41,75 @@ func handleMsgCreateOrModifyCDP(ctx sdk.Context, keeper Keeper, msg MsgCreateOrM
 
 	return sdk.Result{}
 }
+
+// The handlers below turn the positive amounts of the explicit messages into the signed changes ModifyCDP works with.
+
+func handleMsgDepositCollateral(ctx sdk.Context, keeper Keeper, msg MsgDepositCollateral) sdk.Result {
+	collateral := types.Collateral{Token: msg.Collateral.Token, Amount: msg.Collateral.Amount, InitialPrice: sdk.ZeroInt()}
+	liquidity := types.Liquidity{Coin: sdk.NewCoin(msg.LiquidityDenom, sdk.ZeroInt()), InitialPrice: sdk.ZeroInt()}
+
+	err := keeper.ModifyCDP(ctx, msg.Sender, collateral, liquidity)
+	if err != nil {
+		return err.Result()
+	}
+
+	return sdk.Result{}
+}
+
+func handleMsgWithdrawCollateral(ctx sdk.Context, keeper Keeper, msg MsgWithdrawCollateral) sdk.Result {
+	collateral := types.Collateral{Token: msg.Collateral.Token, Amount: msg.Collateral.Amount.Neg(), InitialPrice: sdk.ZeroInt()}
+	liquidity := types.Liquidity{Coin: sdk.NewCoin(msg.LiquidityDenom, sdk.ZeroInt()), InitialPrice: sdk.ZeroInt()}
+
+	err := keeper.ModifyCDP(ctx, msg.Sender, collateral, liquidity)
+	if err != nil {
+		return err.Result()
+	}
+
+	return sdk.Result{}
+}
+
+func handleMsgDrawDebt(ctx sdk.Context, keeper Keeper, msg MsgDrawDebt) sdk.Result {
+	collateral := types.Collateral{Token: msg.CollateralToken, Amount: sdk.ZeroInt(), InitialPrice: sdk.ZeroInt()}
+	liquidity := types.Liquidity{Coin: msg.Liquidity, InitialPrice: sdk.ZeroInt()}
+
+	err := keeper.ModifyCDP(ctx, msg.Sender, collateral, liquidity)
+	if err != nil {
+		return err.Result()
+	}
+
+	return sdk.Result{}
+}
+
+func handleMsgRepayDebt(ctx sdk.Context, keeper Keeper, msg MsgRepayDebt) sdk.Result {
+	collateral := types.Collateral{Token: msg.CollateralToken, Amount: sdk.ZeroInt(), InitialPrice: sdk.ZeroInt()}
+	// sdk.Coin can't be negative, so the amount is negated on its own
+	liquidity := types.Liquidity{Coin: sdk.Coin{Denom: msg.Liquidity.Denom, Amount: msg.Liquidity.Amount.Neg()}, InitialPrice: sdk.ZeroInt()}
+
+	err := keeper.ModifyCDP(ctx, msg.Sender, collateral, liquidity)
+	if err != nil {
+		return err.Result()
+	}
+
+	return sdk.Result{}
+}
+
+func handleMsgTransferCDP(ctx sdk.Context, keeper Keeper, msg MsgTransferCDP) sdk.Result {
+	nftID, _ := keeper.getAssetCodeAndName(msg.CollateralToken)
+
+	err := keeper.TransferCDP(ctx, msg.Sender, msg.Receiver, msg.CollateralToken.GetName(), nftID)
+	if err != nil {
+		return err.Result()
+	}
+
+	return sdk.Result{}
+}
+
+func handleMsgCloseCDP(ctx sdk.Context, keeper Keeper, msg MsgCloseCDP) sdk.Result {
+
+	err := keeper.CloseCDP(ctx, msg.Owner, msg.CollateralName, msg.NftID)
+	if err != nil {
+		return err.Result()
+	}
+
+	return sdk.Result{}
+}
@@ -0,0 +1,38 @@
+package cdp
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// RegisterInvariants registers the cdp module invariants.
+// The global debt includes the debt seized by the liquidator, so the liquidator module checks it.
+func RegisterInvariants(ir sdk.InvariantRouter, k Keeper) {
+	ir.RegisterRoute(ModuleName, "collateral-debt",
+		CollateralDebtInvariant(k))
+}
+
+// CollateralDebtInvariant checks that the total debt of each collateral type equals the sum of the debts of its CDPs
+func CollateralDebtInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) error {
+		for _, cp := range k.GetParams(ctx).CollateralParams {
+			cdps, err := k.GetCDPs(ctx, cp.Denom, "", sdk.NewInt(-1))
+			if err != nil {
+				return err
+			}
+			cdpsDebt := sdk.ZeroInt()
+			for _, cdp := range cdps {
+				cdpsDebt = cdpsDebt.Add(cdp.Liquidity.Coin.Amount)
+			}
+			totalDebt := sdk.ZeroInt()
+			if collateralState, found := k.GetCollateralState(ctx, cp.Denom); found {
+				totalDebt = collateralState.TotalDebt
+			}
+			if !totalDebt.Equal(cdpsDebt) {
+				return fmt.Errorf("total debt of collateral %s is %s, but its CDPs owe %s", cp.Denom, totalDebt, cdpsDebt)
+			}
+		}
+		return nil
+	}
+}
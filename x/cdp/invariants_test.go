@@ -0,0 +1,36 @@
+package cdp
+
+import (
+	"testing"
+
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
+	"github.com/cosmos/cosmos-sdk/x/mock"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+func TestInvariants(t *testing.T) {
+	// setup keeper and some CDPs through ModifyCDP
+	mapp, keeper := setUpMockAppWithoutGenesis()
+	genAccs, addrs, _, _ := mock.CreateGenAccounts(2, cs(c("xrp", 1000), c("btc", 10)))
+	mock.SetGenesis(mapp, genAccs)
+	header := abci.Header{Height: mapp.LastBlockHeight() + 1}
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := mapp.BaseApp.NewContext(false, header)
+	setCurrentPrice(ctx, keeper, "xrp", 1)
+	setCurrentPrice(ctx, keeper, "btc", 1000)
+	require.NoError(t, keeper.ModifyCDP(ctx, addrs[0], ftCollateral("xrp", 400), liq(stableDenom, 100)))
+	require.NoError(t, keeper.ModifyCDP(ctx, addrs[1], ftCollateral("xrp", 400), liq(stableDenom, 150)))
+	require.NoError(t, keeper.ModifyCDP(ctx, addrs[0], ftCollateral("btc", 1), liq(stableDenom, 500)))
+
+	// check the invariants hold
+	require.NoError(t, CollateralDebtInvariant(keeper)(ctx))
+
+	// break them, through the CDPs or the total debt
+	keeper.setCDP(ctx, ftCDP(addrs[1], "xrp", 400, 151))
+	require.Error(t, CollateralDebtInvariant(keeper)(ctx))
+	keeper.setCDP(ctx, ftCDP(addrs[1], "xrp", 400, 150))
+	require.NoError(t, CollateralDebtInvariant(keeper)(ctx))
+	keeper.setCollateralState(ctx, types.CollateralState{Denom: "xrp", TotalDebt: i(1000), AccumulatedFees: i(0), CollectedFees: i(0)})
+	require.Error(t, CollateralDebtInvariant(keeper)(ctx))
+}
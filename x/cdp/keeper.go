@@ -14,22 +14,27 @@ import (
 // GovDenom asset code of the governance coin
 const GovDenom = "tmnt"
 
+// DefaultStableDenom is the stable coin drawn from CDPs when the params don't set one, as in stores created before it was a param
+const DefaultStableDenom = "uatom"
+
 // Keeper cdp Keeper
 type Keeper struct {
 	storeKey       sdk.StoreKey
 	pricefeed      types.PricefeedKeeper
 	bank           bankKeeper
+	pool           poolKeeper
 	paramsSubspace params.Subspace
 	cdc            *codec.Codec
 }
 
 // NewKeeper creates a new keeper
-func NewKeeper(cdc *codec.Codec, storeKey sdk.StoreKey, subspace params.Subspace, pricefeed types.PricefeedKeeper, bank bankKeeper) Keeper {
+func NewKeeper(cdc *codec.Codec, storeKey sdk.StoreKey, subspace params.Subspace, pricefeed types.PricefeedKeeper, bank bankKeeper, pool poolKeeper) Keeper {
 	subspace = subspace.WithKeyTable(createParamsKeyTable())
 	return Keeper{
 		storeKey:       storeKey,
 		pricefeed:      pricefeed,
 		bank:           bank,
+		pool:           pool,
 		paramsSubspace: subspace,
 		cdc:            cdc,
 	}
@@ -53,6 +58,9 @@ func (k Keeper) getAssetCodeAndName(token types.Token) (string, string) {
 }
 
 // ModifyCDP creates, changes, or deletes a CDP
+// The collateral and liquidity amounts are signed changes added to the CDP: positive amounts deposit collateral and draw stable coin,
+// negative ones withdraw collateral and repay stable coin. Repayments pay the accumulated stability fees first.
+// The stable coin is drawn from the liquidity pool, and repayments beyond the stability fees go back to it.
 // TODO can/should this function be split up?
 func (k Keeper) ModifyCDP(ctx sdk.Context, owner sdk.AccAddress, collateral types.Collateral, liquidity types.Liquidity) sdk.Error {
 
@@ -65,6 +73,10 @@ func (k Keeper) ModifyCDP(ctx sdk.Context, owner sdk.AccAddress, collateral type
 	if !p.IsCollateralPresent(collateralName) { // maybe abstract this logic into GetCDP
 		return sdk.ErrInternal("collateral type not enabled to create CDPs")
 	}
+	// Check the debt is in the stable coin, the only coin the pool lends against collateral
+	if stableDenom := k.GetStableDenom(ctx); liquidity.Coin.Denom != stableDenom {
+		return sdk.ErrInvalidCoins(fmt.Sprintf("CDP liquidity must be in the stable coin %s, not %s", stableDenom, liquidity.Coin.Denom))
+	}
 
 	// Check the owner has enough collateral and stable coins
 
@@ -77,75 +89,111 @@ func (k Keeper) ModifyCDP(ctx sdk.Context, owner sdk.AccAddress, collateral type
 	}
 	// reducing liquidity, by adding stable coin to CDP
 	if liquidity.Coin.Amount.IsNegative() {
-		ok := k.bank.HasCoins(ctx, owner, sdk.NewCoins(sdk.NewCoin(collateral.Token.GetName(), liquidity.Coin.Amount.Neg())))
+		ok := k.bank.HasCoins(ctx, owner, sdk.NewCoins(sdk.NewCoin(liquidity.Coin.Denom, liquidity.Coin.Amount.Neg())))
 		if !ok {
 			return sdk.ErrInsufficientCoins("not enough stable coin in sender's account")
 		}
 	}
+	// increasing liquidity, by drawing stable coin from the pool
+	if liquidity.Coin.Amount.IsPositive() {
+		available, err := k.pool.GetDrawableLiquidity(ctx, liquidity.Coin.Denom)
+		if err != nil {
+			return err
+		}
+		if available.LT(liquidity.Coin.Amount) {
+			return sdk.ErrInsufficientCoins("not enough liquidity in the pool")
+		}
+	}
 
 	// Change collateral and debt recorded in CDP
 
 	// Get CDP (or create if not exists)
-	var cdp types.CDP
-	var found bool
-
-	switch collToken := (collateral.Token).(type) {
-	case BaseFT:
-		cdp, found = k.GetCDP(ctx, owner, collateralName, "")
-	case BaseNFT:
-		cdp, found = k.GetCDP(ctx, owner, collateralName, collToken.ID)
-	}
-
+	nftID, _ := k.getAssetCodeAndName(collateral.Token)
+	cdp, found := k.GetCDP(ctx, owner, collateralName, nftID)
 	if !found {
-		cdp = types.CDP{Owner: owner, Collateral: collateral, Liquidity: liquidity}
+		cdp = types.CDP{
+			Owner:           owner,
+			Collateral:      types.Collateral{Token: collateral.Token, Amount: sdk.ZeroInt(), InitialPrice: collateral.InitialPrice},
+			Liquidity:       types.Liquidity{Coin: sdk.NewCoin(liquidity.Coin.Denom, sdk.ZeroInt()), InitialPrice: liquidity.InitialPrice},
+			AccumulatedFees: sdk.ZeroInt(),
+			FeesUpdated:     ctx.BlockHeight(),
+		}
+	}
+	if cdp.Liquidity.Coin.Denom != liquidity.Coin.Denom {
+		return sdk.ErrInvalidCoins(fmt.Sprintf("CDP liquidity is in %s, not %s", cdp.Liquidity.Coin.Denom, liquidity.Coin.Denom))
+	}
+	// Compound the stability fees accrued since the last change onto the debt, repayments pay them first
+	cdp, fees := k.accumulateFees(ctx, cdp)
+	feesPaid := sdk.ZeroInt()
+	if liquidity.Coin.Amount.IsNegative() {
+		cdp, feesPaid = payFees(cdp, liquidity.Coin.Amount.Neg())
 	}
 	// Add/Subtract collateral and debt
+	cdp.Collateral.Amount = cdp.Collateral.Amount.Add(collateral.Amount)
 	if cdp.Collateral.Amount.IsNegative() {
 		return sdk.ErrInternal(" can't withdraw more collateral than exists in CDP")
 	}
+	cdp.Liquidity.Coin.Amount = cdp.Liquidity.Coin.Amount.Add(liquidity.Coin.Amount)
 	if cdp.Liquidity.Coin.Amount.IsNegative() {
 		return sdk.ErrInternal("can't pay back more debt than exists in CDP")
 	}
 
-	assetCode, assetName := k.getAssetCodeAndName(cdp.Collateral.Token)
+	// Check the CDP is still safe when collateral is withdrawn or debt drawn. Other changes can only make it safer.
+	if collateral.Amount.IsNegative() || liquidity.Coin.Amount.IsPositive() {
+		assetCode, assetName := k.getAssetCodeAndName(cdp.Collateral.Token)
+		collateralCurrentPrice := k.pricefeed.GetCurrentPrice(ctx, assetCode, assetName)
 
-	collateralCurrentPrice := k.pricefeed.GetCurrentPrice(ctx, assetCode, assetName)
+		// if the price is zero, then ask for the price of the token
+		if collateralCurrentPrice.Price.IsZero() {
+			k.pricefeed.AskForPrice(ctx, assetCode, assetName)
+		}
+		// a stale price may be out of date, so it can't back new debt or collateral withdrawals
+		if collateralCurrentPrice.Stale {
+			return sdk.ErrInternal("collateral price is stale, oracles haven't reached quorum")
+		}
+		// the debt is valued at the price of the stable coin, not at one unit of collateral price per coin
+		liquidityPrice, err := k.getLiquidityPrice(ctx)
+		if err != nil {
+			return err
+		}
 
-	// if the price is zero, then ask for the price of the token
-	if collateralCurrentPrice.Price.IsZero() {
-		k.pricefeed.AskForPrice(ctx, assetCode, assetName)
+		isUnderCollateralized := cdp.IsUnderCollateralized(
+			collateralCurrentPrice.Price,
+			liquidityPrice,
+			p.GetCollateralParams(cdp.Collateral.Token.GetName()).LiquidationRatio,
+		)
+		if isUnderCollateralized {
+			return sdk.ErrInternal("Change to CDP would put it below liquidation ratio")
+		}
 	}
-
-	isUnderCollateralized := cdp.IsUnderCollateralized(
-		collateralCurrentPrice.Price,
-		p.GetCollateralParams(cdp.Collateral.Token.GetName()).LiquidationRatio,
-	)
-
-	if isUnderCollateralized {
-		return sdk.ErrInternal("Change to CDP would put it below liquidation ratio")
+	// Check the CDP isn't left with a dust amount of debt, as it would cost more to liquidate than it's worth
+	debtFloor := p.GetCollateralParams(cdp.Collateral.Token.GetName()).DebtFloor
+	if cdp.Liquidity.Coin.Amount.IsPositive() && cdp.Liquidity.Coin.Amount.LT(debtFloor) {
+		return sdk.ErrInternal("change to CDP would leave its debt below the debt floor for this collateral type")
 	}
-	// TODO check for dust
 
 	// Add/Subtract from global debt limit
 	gDebt := k.GetGlobalDebt(ctx)
-	gDebt = gDebt.Add(liquidity.Coin.Amount)
+	gDebt = gDebt.Add(fees).Add(liquidity.Coin.Amount)
 	if gDebt.IsNegative() {
 		return sdk.ErrInternal("global debt can't be negative") // This should never happen if debt per CDP can't be negative
 	}
-	if gDebt.GT(p.GlobalDebtLimit) {
+	if liquidity.Coin.Amount.IsPositive() && gDebt.GT(p.GlobalDebtLimit) {
 		return sdk.ErrInternal("change to CDP would put the system over the global debt limit")
 	}
 
 	// Add/Subtract from collateral debt limit
 	collateralState, found := k.GetCollateralState(ctx, cdp.Collateral.Token.GetName())
 	if !found {
-		collateralState = types.CollateralState{Denom: cdp.Collateral.Token.GetName(), TotalDebt: sdk.ZeroInt()} // Already checked that this denom is authorized, so ok to create new CollateralState
+		collateralState = types.CollateralState{Denom: cdp.Collateral.Token.GetName(), TotalDebt: sdk.ZeroInt(), AccumulatedFees: sdk.ZeroInt(), CollectedFees: sdk.ZeroInt()} // Already checked that this denom is authorized, so ok to create new CollateralState
 	}
-	collateralState.TotalDebt = collateralState.TotalDebt.Add(liquidity.Coin.Amount)
+	collateralState.TotalDebt = collateralState.TotalDebt.Add(fees).Add(liquidity.Coin.Amount)
+	collateralState.AccumulatedFees = collateralState.AccumulatedFees.Add(fees).Sub(feesPaid)
+	collateralState.CollectedFees = collateralState.CollectedFees.Add(feesPaid)
 	if collateralState.TotalDebt.IsNegative() {
 		return sdk.ErrInternal("total debt for this collateral type can't be negative") // This should never happen if debt per CDP can't be negative
 	}
-	if collateralState.TotalDebt.GT(p.GetCollateralParams(cdp.Collateral.Token.GetName()).DebtLimit) {
+	if liquidity.Coin.Amount.IsPositive() && collateralState.TotalDebt.GT(p.GetCollateralParams(cdp.Collateral.Token.GetName()).DebtLimit) {
 		return sdk.ErrInternal("change to CDP would put the system over the debt limit for this collateral type")
 	}
 
@@ -154,35 +202,23 @@ func (k Keeper) ModifyCDP(ctx sdk.Context, owner sdk.AccAddress, collateral type
 	// change owner's coins (increase or decrease)
 	var err sdk.Error
 	if collateral.Amount.IsNegative() {
-		_, err = k.bank.AddCoins(ctx, owner, sdk.NewCoins(sdk.NewCoin(collateral.Token.GetName(), collateral.Amount.Neg())))
+		_, err = k.bank.AddCoins(ctx, owner, sdk.NewCoins(sdk.NewCoin(collateralName, collateral.Amount.Neg())))
 	} else {
-		_, err = k.bank.SubtractCoins(ctx, owner, sdk.NewCoins(sdk.NewCoin(collateral.Token.GetName(), collateral.Amount)))
+		_, err = k.bank.SubtractCoins(ctx, owner, sdk.NewCoins(sdk.NewCoin(collateralName, collateral.Amount)))
 	}
 	if err != nil {
 		panic(err) // this shouldn't happen because coin balance was checked earlier
 	}
 	if liquidity.Coin.Amount.IsNegative() {
-		_, err = k.bank.SubtractCoins(ctx, owner, sdk.NewCoins(sdk.NewCoin(collateral.Token.GetName(), liquidity.Coin.Amount.Neg())))
+		err = k.repayLiquidity(ctx, owner, sdk.NewCoin(liquidity.Coin.Denom, liquidity.Coin.Amount.Neg()), feesPaid)
 	} else {
-		_, err = k.bank.AddCoins(ctx, owner, sdk.NewCoins(sdk.NewCoin(collateral.Token.GetName(), liquidity.Coin.Amount)))
+		err = k.pool.DrawLiquidity(ctx, owner, liquidity.Coin)
 	}
 	if err != nil {
-		panic(err) // this shouldn't happen because coin balance was checked earlier
+		panic(err) // this shouldn't happen because coin balance and pool liquidity were checked earlier
 	}
 
-	//TODO Here calculate liquidityValue
 	// Set CDP
-	liquidityCurrentPrice := k.pricefeed.GetCurrentPrice(ctx, "", liquidity.Coin.Denom)
-	if liquidityCurrentPrice.Price.IsZero() {
-		return sdk.ErrInvalidCoins("Liquidity price cant be equal to zero")
-	}
-
-	// get the collateral value = price * quantity
-	collateralValue := collateralCurrentPrice.Price.Mul(collateral.Amount)
-
-	// get the liquidity amount = collateral-value / liquidity price
-	cdp.Liquidity.Coin.Amount = collateralValue.Quo(liquidityCurrentPrice.Price)
-
 	if cdp.Collateral.Amount.IsZero() && cdp.Liquidity.Coin.Amount.IsZero() { // TODO maybe abstract this logic into setCDP
 		k.deleteCDP(ctx, cdp)
 	} else {
@@ -195,73 +231,169 @@ func (k Keeper) ModifyCDP(ctx sdk.Context, owner sdk.AccAddress, collateral type
 	return nil
 }
 
-func (k Keeper) ModifyCDPType(ctx sdk.Context, assetName string, assetCode string) sdk.Error {
+// ModifyCDPType brings the CDPs of an asset up to date after a change of its price, compounding their stability fees.
+// It's run when an oracle posts a price, so it only updates the fees and debt totals, without the checks of ModifyCDP:
+// a CDP failing to update is logged and skipped, and left to be updated by its next change.
+func (k Keeper) ModifyCDPType(ctx sdk.Context, assetName string, assetCode string) {
 
-	// Get all cdps with assetName
-	cdps, _ := k.GetCDPs(ctx, assetName, sdk.NewInt(0))
+	// Assets that aren't collateral, like the stable coin, have no CDPs
+	if !k.GetParams(ctx).IsCollateralPresent(assetName) {
+		return
+	}
+	// Get all cdps with assetName, restricted to the token identified by assetCode
+	cdps, err := k.GetCDPs(ctx, assetName, assetCode, sdk.NewInt(-1))
+	if err != nil {
+		ctx.Logger().Error(fmt.Sprintf("could not update the CDPs of %s %s: %s", assetName, assetCode, err))
+		return
+	}
 	for _, cdp := range cdps {
+		if err := k.updateFees(ctx, cdp); err != nil {
+			ctx.Logger().Error(fmt.Sprintf("could not update the fees of the %s CDP of %s: %s", assetName, cdp.Owner, err))
+		}
+	}
+}
 
-		switch token := cdp.Collateral.Token.(type) {
-		case BaseFT:
-			{
-				// this shouldn't verify but we've included it for completeness
-				// ideally no CDP can exist with a collateral being a FT with price zero
-				// this would throw an error inside the ModifyCDP method
-				if token.GetName() == assetName {
-					err := k.ModifyCDP(ctx, cdp.Owner, cdp.Collateral, cdp.Liquidity)
-					if err != nil {
-						return err
-					}
-				}
-				break
-			}
+// updateFees compounds the stability fees accrued by a CDP onto its debt and the debt totals.
+func (k Keeper) updateFees(ctx sdk.Context, cdp types.CDP) sdk.Error {
+	cdp, fees := k.accumulateFees(ctx, cdp)
 
-		case BaseNFT:
-			{
-				// get the token based on the asset name and the asset code and update the CDP
-				// this will trigger the funds being moved into the user wallet from the pool
-				if token.Name == assetName && token.ID == assetCode {
-					err := k.ModifyCDP(ctx, cdp.Owner, cdp.Collateral, cdp.Liquidity)
-					if err != nil {
-						return err
-					}
-				}
-			}
-		}
+	collateralState, found := k.GetCollateralState(ctx, cdp.Collateral.Token.GetName())
+	if !found {
+		return sdk.ErrInternal("could not find collateral state")
 	}
+	collateralState.TotalDebt = collateralState.TotalDebt.Add(fees)
+	collateralState.AccumulatedFees = collateralState.AccumulatedFees.Add(fees)
+
+	k.setCDP(ctx, cdp)
+	k.setCollateralState(ctx, collateralState)
+	k.setGlobalDebt(ctx, k.GetGlobalDebt(ctx).Add(fees))
 	return nil
 }
 
-// TODO
-// // TransferCDP allows people to transfer ownership of their CDPs to others
-// func (k Keeper) TransferCDP(ctx sdk.Context, from sdk.AccAddress, to sdk.AccAddress, collateralDenom string) sdk.Error {
-// 	return nil
-// }
+// TransferCDP allows people to transfer ownership of their CDPs to others.
+// The CDP is moved to the keys of the new owner, and the owner of NFT collateral is updated too.
+func (k Keeper) TransferCDP(ctx sdk.Context, from sdk.AccAddress, to sdk.AccAddress, collateralDenom string, nftID string) sdk.Error {
+	if from.Equals(to) {
+		return sdk.ErrInternal("can't transfer a CDP to its owner")
+	}
+	cdp, found := k.GetCDP(ctx, from, collateralDenom, nftID)
+	if !found {
+		return sdk.ErrInternal("could not find CDP")
+	}
+	// CDPs can't be merged, as the owner can only hold one CDP per collateral denom and NFT id
+	if _, found := k.GetCDP(ctx, to, collateralDenom, nftID); found {
+		return sdk.ErrInternal("receiver already has a CDP with this collateral")
+	}
+
+	k.deleteCDP(ctx, cdp)
+	cdp.Owner = to
+	if nft, ok := cdp.Collateral.Token.(BaseNFT); ok {
+		cdp.Collateral.Token = nft.SetOwner(to)
+	}
+	k.setCDP(ctx, cdp)
+	return nil
+}
+
+// CloseCDP repays all the debt of a CDP, including its stability fees, returns all its collateral to the owner and deletes it.
+// Unlike ModifyCDP there's no collateralization check, as the CDP is left with nothing.
+func (k Keeper) CloseCDP(ctx sdk.Context, owner sdk.AccAddress, collateralDenom string, nftID string) sdk.Error {
+	cdp, found := k.GetCDP(ctx, owner, collateralDenom, nftID)
+	if !found {
+		return sdk.ErrInternal("could not find CDP")
+	}
+
+	// Compound the stability fees accrued since the last change onto the debt, the whole debt is repaid
+	cdp, fees := k.accumulateFees(ctx, cdp)
+	debt := cdp.Liquidity.Coin.Amount
+	cdp, feesPaid := payFees(cdp, debt)
+
+	// Check the owner can repay the debt
+	debtCoins := sdk.NewCoins(sdk.NewCoin(cdp.Liquidity.Coin.Denom, debt))
+	if !k.bank.HasCoins(ctx, owner, debtCoins) {
+		return sdk.ErrInsufficientCoins("not enough stable coin in sender's account to repay the CDP debt")
+	}
+
+	// Update the debt totals
+	gDebt := k.GetGlobalDebt(ctx).Add(fees).Sub(debt)
+	if gDebt.IsNegative() {
+		return sdk.ErrInternal("global debt can't be negative") // This should never happen if debt per CDP can't be negative
+	}
+	collateralState, found := k.GetCollateralState(ctx, collateralDenom)
+	if !found {
+		return sdk.ErrInternal("could not find collateral state")
+	}
+	collateralState.TotalDebt = collateralState.TotalDebt.Add(fees).Sub(debt)
+	if collateralState.TotalDebt.IsNegative() {
+		return sdk.ErrInternal("total debt for this collateral type can't be negative") // This should never happen if debt per CDP can't be negative
+	}
+	collateralState.AccumulatedFees = collateralState.AccumulatedFees.Add(fees).Sub(feesPaid)
+	collateralState.CollectedFees = collateralState.CollectedFees.Add(feesPaid)
+
+	// Move the coins: debt from the owner, fees split between the pool and the liquidator module account and the rest to the pool, collateral back to the owner
+	err := k.repayLiquidity(ctx, owner, sdk.NewCoin(cdp.Liquidity.Coin.Denom, debt), feesPaid)
+	if err != nil {
+		panic(err) // this shouldn't happen because coin balance was checked earlier
+	}
+	_, err = k.bank.AddCoins(ctx, owner, sdk.NewCoins(sdk.NewCoin(collateralDenom, cdp.Collateral.Amount)))
+	if err != nil {
+		panic(err)
+	}
+
+	k.deleteCDP(ctx, cdp)
+	k.setGlobalDebt(ctx, gDebt)
+	k.setCollateralState(ctx, collateralState)
+	return nil
+}
+
+// repayLiquidity takes a repayment of CDP debt from its owner. The stability fees paid are split between the pool and the
+// liquidator module account, see splitFees, and the rest goes back to the pool the liquidity was drawn from.
+func (k Keeper) repayLiquidity(ctx sdk.Context, owner sdk.AccAddress, repayment sdk.Coin, feesPaid sdk.Int) sdk.Error {
+	poolFees, liquidatorFees := k.splitFees(ctx, feesPaid)
+	_, err := k.bank.SubtractCoins(ctx, owner, sdk.NewCoins(sdk.NewCoin(repayment.Denom, liquidatorFees)))
+	if err != nil {
+		return err
+	}
+	err = k.collectFees(ctx, repayment.Denom, liquidatorFees)
+	if err != nil {
+		return err
+	}
+	err = k.pool.PayFees(ctx, owner, sdk.NewCoin(repayment.Denom, poolFees))
+	if err != nil {
+		return err
+	}
+	return k.pool.RepayLiquidity(ctx, owner, sdk.NewCoin(repayment.Denom, repayment.Amount.Sub(feesPaid)))
+}
 
 // PartialSeizeCDP removes collateral and debt from a CDP and decrements global debt counters. It does not move collateral to another account so is unsafe.
 // TODO should this be made safer by moving collateral to liquidatorModuleAccount ? If so how should debt be moved?
 func (k Keeper) PartialSeizeCDP(ctx sdk.Context, owner sdk.AccAddress, collateral types.Collateral, collateralToSeize sdk.Int, debtToSeize sdk.Int) sdk.Error {
 	// Get CDP
 
-	var cdp types.CDP
-	var found bool
-
-	switch collToken := (collateral.Token).(type) {
-	case BaseFT:
-		cdp, found = k.GetCDP(ctx, owner, collateral.Token.GetName(), "")
-	case BaseNFT:
-		cdp, found = k.GetCDP(ctx, owner, collateral.Token.GetName(), collToken.ID)
-	}
+	nftID, _ := k.getAssetCodeAndName(collateral.Token)
+	cdp, found := k.GetCDP(ctx, owner, collateral.Token.GetName(), nftID)
 	if !found {
 		return sdk.ErrInternal("could not find CDP")
 	}
 
 	assetCode, assetName := k.getAssetCodeAndName(cdp.Collateral.Token)
 
+	// Compound the stability fees accrued since the last change onto the debt, they are seized first
+	cdp, fees := k.accumulateFees(ctx, cdp)
+	cdp, feesSeized := payFees(cdp, debtToSeize)
+
 	// Check if CDP is undercollateralized
 	p := k.GetParams(ctx)
+	liquidityPrice, err := k.getLiquidityPrice(ctx)
+	if err != nil {
+		return err
+	}
+	collateralCurrentPrice := k.pricefeed.GetCurrentPrice(ctx, assetCode, assetName)
+	if collateralCurrentPrice.Stale {
+		return sdk.ErrInternal("collateral price is stale, oracles haven't reached quorum")
+	}
 	isUnderCollateralized := cdp.IsUnderCollateralized(
-		k.pricefeed.GetCurrentPrice(ctx, assetCode, assetName).Price,
+		collateralCurrentPrice.Price,
+		liquidityPrice,
 		p.GetCollateralParams(cdp.Collateral.Token.GetName()).LiquidationRatio,
 	)
 	if !isUnderCollateralized {
@@ -272,7 +404,7 @@ func (k Keeper) PartialSeizeCDP(ctx sdk.Context, owner sdk.AccAddress, collatera
 	if collateralToSeize.IsNegative() {
 		return sdk.ErrInternal("cannot seize negative collateral")
 	}
-	//cdp.CollateralAmount = cdp.CollateralAmount.Sub(collateralToSeize)
+	cdp.Collateral.Amount = cdp.Collateral.Amount.Sub(collateralToSeize)
 	if cdp.Collateral.Amount.IsNegative() {
 		return sdk.ErrInternal("can't seize more collateral than exists in CDP")
 	}
@@ -281,7 +413,7 @@ func (k Keeper) PartialSeizeCDP(ctx sdk.Context, owner sdk.AccAddress, collatera
 	if debtToSeize.IsNegative() {
 		return sdk.ErrInternal("cannot seize negative debt")
 	}
-	//cdp.Debt = cdp.Debt.Sub(debtToSeize)
+	cdp.Liquidity.Coin.Amount = cdp.Liquidity.Coin.Amount.Sub(debtToSeize)
 	if cdp.Liquidity.Coin.Amount.IsNegative() {
 		return sdk.ErrInternal("can't seize more debt than exists in CDP")
 	}
@@ -291,12 +423,13 @@ func (k Keeper) PartialSeizeCDP(ctx sdk.Context, owner sdk.AccAddress, collatera
 	if !found {
 		return sdk.ErrInternal("could not find collateral state")
 	}
-	collateralState.TotalDebt = collateralState.TotalDebt.Sub(debtToSeize)
+	collateralState.TotalDebt = collateralState.TotalDebt.Add(fees).Sub(debtToSeize)
 	if collateralState.TotalDebt.IsNegative() {
 		return sdk.ErrInternal("Total debt per collateral type is negative.") // This should not happen given the checks on the CDP.
 	}
+	collateralState.AccumulatedFees = collateralState.AccumulatedFees.Add(fees).Sub(feesSeized)
 
-	// Note: Global debt is not decremented here. It's only decremented when debt and stable coin are annihilated (aka heal)
+	// Note: Global debt is not decremented here, only increased by the fees. It's only decremented when debt and stable coin are annihilated (aka heal)
 	// TODO update global seized debt? this is what maker does (named vice in Vat.grab) but it's not used anywhere
 
 	// Store updated state
@@ -307,6 +440,7 @@ func (k Keeper) PartialSeizeCDP(ctx sdk.Context, owner sdk.AccAddress, collatera
 		k.setCDP(ctx, cdp)
 	}
 	k.setCollateralState(ctx, collateralState)
+	k.setGlobalDebt(ctx, k.GetGlobalDebt(ctx).Add(fees))
 	return nil
 }
 
@@ -324,9 +458,25 @@ func (k Keeper) ReduceGlobalDebt(ctx sdk.Context, amount sdk.Int) sdk.Error {
 	return nil
 }
 
-// deprecated - use collateral.Token.GetName() instead
-func (k Keeper) GetStableDenom() string {
-	return ""
+// GetStableDenom returns the denom of the stable coin drawn from CDPs, set in the module params.
+func (k Keeper) GetStableDenom(ctx sdk.Context) string {
+	if denom := k.GetParams(ctx).StableDenom; denom != "" {
+		return denom
+	}
+	return DefaultStableDenom
+}
+
+// getLiquidityPrice returns the current price of the stable coin, which the debt of CDPs is valued at.
+// It errors when the price is stale, as CDPs can't be safely valued against it.
+func (k Keeper) getLiquidityPrice(ctx sdk.Context) (sdk.Int, sdk.Error) {
+	currentPrice := k.pricefeed.GetCurrentPrice(ctx, "", k.GetStableDenom(ctx))
+	if !currentPrice.Price.IsPositive() {
+		return sdk.Int{}, sdk.ErrInvalidCoins("Liquidity price cant be equal to zero")
+	}
+	if currentPrice.Stale {
+		return sdk.Int{}, sdk.ErrInternal("liquidity price is stale, oracles haven't reached quorum")
+	}
+	return currentPrice.Price, nil
 }
 func (k Keeper) GetGovDenom() string {
 	return GovDenom
@@ -340,6 +490,26 @@ func (k Keeper) GetParams(ctx sdk.Context) types.CdpModuleParams {
 	return p
 }
 
+// AddCollateralParams authorizes a new collateral type with the given params, as listed through governance
+func (k Keeper) AddCollateralParams(ctx sdk.Context, collateralParams types.CollateralParams) sdk.Error {
+	p := k.GetParams(ctx)
+	if p.IsCollateralPresent(collateralParams.Denom) {
+		return sdk.ErrInternal(fmt.Sprintf("collateral %s already has params", collateralParams.Denom))
+	}
+	if err := ValidateCollateralParams(collateralParams); err != nil {
+		return sdk.ErrInternal(err.Error())
+	}
+	if collateralParams.Denom == k.GetStableDenom(ctx) {
+		return sdk.ErrInternal("the stable coin can't be a collateral")
+	}
+	if collateralParams.DebtLimit.GT(p.GlobalDebtLimit) {
+		return sdk.ErrInternal("debt limit of collateral is above the global debt limit")
+	}
+	p.CollateralParams = append(p.CollateralParams, collateralParams)
+	k.setParams(ctx, p)
+	return nil
+}
+
 // This is only needed to be able to setup the store from the genesis file. The keeper should not change any of the params itself.
 func (k Keeper) setParams(ctx sdk.Context, cdpModuleParams types.CdpModuleParams) {
 	k.paramsSubspace.Set(ctx, moduleParamsKey, &cdpModuleParams)
@@ -347,20 +517,67 @@ func (k Keeper) setParams(ctx sdk.Context, cdpModuleParams types.CdpModuleParams
 
 // ---------- Store Wrappers ----------
 
+// CDPs are stored under "cdp:<collateral denom>:<nft id>:<owner>", fungible collateral using an empty nft id.
+// The separators prevent a denom or nft id from being a prefix of another one (eg "btc" and "btcx").
+
+var cdpKeyPrefix = []byte("cdp:")
+
 func (k Keeper) getCDPKeyPrefix(collateralDenom string) []byte {
+	if len(collateralDenom) == 0 {
+		return cdpKeyPrefix // all CDPs
+	}
 	return bytes.Join(
 		[][]byte{
-			[]byte("cdp"),
+			cdpKeyPrefix,
 			[]byte(collateralDenom),
+			[]byte(":"),
 		},
 		nil, // no separator
 	)
 }
-func (k Keeper) getCDPKey(owner sdk.AccAddress, collateralDenom string) []byte {
+func (k Keeper) getCDPTokenKeyPrefix(collateralDenom string, nftID string) []byte {
 	return bytes.Join(
 		[][]byte{
 			k.getCDPKeyPrefix(collateralDenom),
+			[]byte(nftID),
+			[]byte(":"),
+		},
+		nil, // no separator
+	)
+}
+func (k Keeper) getCDPKey(owner sdk.AccAddress, collateralDenom string, nftID string) []byte {
+	return bytes.Join(
+		[][]byte{
+			k.getCDPTokenKeyPrefix(collateralDenom, nftID),
+			[]byte(owner.String()),
+		},
+		nil, // no separator
+	)
+}
+func (k Keeper) getCDPKeyFromCDP(cdp types.CDP) []byte {
+	nftID, collateralDenom := k.getAssetCodeAndName(cdp.Collateral.Token)
+	return k.getCDPKey(cdp.Owner, collateralDenom, nftID)
+}
+
+// The owner index maps "cdpOwner:<owner>:<cdp key>" to nothing, so that the CDPs of an owner can be found without knowing their collateral.
+
+var cdpOwnerIndexKeyPrefix = []byte("cdpOwner:")
+
+func (k Keeper) getCDPOwnerIndexKeyPrefix(owner sdk.AccAddress) []byte {
+	return bytes.Join(
+		[][]byte{
+			cdpOwnerIndexKeyPrefix,
 			[]byte(owner.String()),
+			[]byte(":"),
+		},
+		nil, // no separator
+	)
+}
+func (k Keeper) getCDPOwnerIndexKey(owner sdk.AccAddress, cdpKey []byte) []byte {
+	return bytes.Join(
+		[][]byte{
+			k.getCDPOwnerIndexKeyPrefix(owner),
+			cdpKey,
 		},
 		nil, // no separator
 	)
@@ -369,7 +586,7 @@ func (k Keeper) GetCDP(ctx sdk.Context, owner sdk.AccAddress, collateralDenom st
 	// get store
 	store := ctx.KVStore(k.storeKey)
 	// get CDP
-	bz := store.Get(k.getCDPKey(owner, collateralDenom))
+	bz := store.Get(k.getCDPKey(owner, collateralDenom, nftID))
 	// unmarshal
 	if bz == nil {
 		return types.CDP{}, false
@@ -383,18 +600,43 @@ func (k Keeper) setCDP(ctx sdk.Context, cdp types.CDP) {
 	store := ctx.KVStore(k.storeKey)
 	// marshal and set
 	bz := k.cdc.MustMarshalBinaryLengthPrefixed(cdp)
-	store.Set(k.getCDPKey(cdp.Owner, cdp.Collateral.Token.GetName()), bz)
+	cdpKey := k.getCDPKeyFromCDP(cdp)
+	store.Set(cdpKey, bz)
+	store.Set(k.getCDPOwnerIndexKey(cdp.Owner, cdpKey), []byte{})
 }
-func (k Keeper) deleteCDP(ctx sdk.Context, cdp types.CDP) { // TODO should this id the cdp by passing in owner,collateralDenom pair?
+func (k Keeper) deleteCDP(ctx sdk.Context, cdp types.CDP) {
 	// get store
 	store := ctx.KVStore(k.storeKey)
-	// delete key
-	store.Delete(k.getCDPKey(cdp.Owner, cdp.Collateral.Token.GetName()))
+	// delete keys
+	cdpKey := k.getCDPKeyFromCDP(cdp)
+	store.Delete(cdpKey)
+	store.Delete(k.getCDPOwnerIndexKey(cdp.Owner, cdpKey))
+}
+
+// GetOwnerCDPs returns all the CDPs of an owner, both FT and NFT backed ones, ordered by collateral denom and nft id.
+func (k Keeper) GetOwnerCDPs(ctx sdk.Context, owner sdk.AccAddress) types.CDPs {
+	store := ctx.KVStore(k.storeKey)
+	prefix := k.getCDPOwnerIndexKeyPrefix(owner)
+	iter := sdk.KVStorePrefixIterator(store, prefix)
+	defer iter.Close()
+
+	var cdps types.CDPs
+	for ; iter.Valid(); iter.Next() {
+		bz := store.Get(iter.Key()[len(prefix):])
+		if bz == nil {
+			panic("owner index points to a missing CDP")
+		}
+		var cdp types.CDP
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &cdp)
+		cdps = append(cdps, cdp)
+	}
+	return cdps
 }
 
-// GetCDPs returns all CDPs, optionally filtered by collateral type and liquidation price.
+// GetCDPs returns all CDPs, optionally filtered by collateral type, nft id and liquidation price.
+// `nftID` is only used together with a collateral type, an empty one returns the CDPs of every token of that type.
 // `price` filters for CDPs that will be below the liquidation ratio when the collateral is at that specified price.
-func (k Keeper) GetCDPs(ctx sdk.Context, collateralDenom string, price sdk.Int) (types.CDPs, sdk.Error) {
+func (k Keeper) GetCDPs(ctx sdk.Context, collateralDenom string, nftID string, price sdk.Int) (types.CDPs, sdk.Error) {
 	// Validate inputs
 	parameters := k.GetParams(ctx)
 	if len(collateralDenom) != 0 && !parameters.IsCollateralPresent(collateralDenom) {
@@ -403,10 +645,18 @@ func (k Keeper) GetCDPs(ctx sdk.Context, collateralDenom string, price sdk.Int)
 	if len(collateralDenom) == 0 && !price.IsNegative() {
 		return nil, sdk.ErrInternal("cannot specify price without collateral denom")
 	}
+	if len(collateralDenom) == 0 && len(nftID) != 0 {
+		return nil, sdk.ErrInternal("cannot specify nft id without collateral denom")
+	}
 
 	// Get an iterator over CDPs
+	prefix := k.getCDPKeyPrefix(collateralDenom) // could be all CDPs is collateralDenom is ""
+	if len(nftID) != 0 {
+		prefix = k.getCDPTokenKeyPrefix(collateralDenom, nftID)
+	}
 	store := ctx.KVStore(k.storeKey)
-	iter := sdk.KVStorePrefixIterator(store, k.getCDPKeyPrefix(collateralDenom)) // could be all CDPs is collateralDenom is ""
+	iter := sdk.KVStorePrefixIterator(store, prefix)
+	defer iter.Close()
 
 	// Decode CDPs into slice
 	var cdps types.CDPs
@@ -422,9 +672,13 @@ func (k Keeper) GetCDPs(ctx sdk.Context, collateralDenom string, price sdk.Int)
 	// Filter for CDPs that would be under-collateralized at the specified price
 	// If price is nil or -ve, skip the filtering as it would return all CDPs anyway
 	if !price.IsNegative() {
+		liquidityPrice, err := k.getLiquidityPrice(ctx)
+		if err != nil {
+			return nil, err
+		}
 		var filteredCDPs types.CDPs
 		for _, cdp := range cdps {
-			if cdp.IsUnderCollateralized(price, parameters.GetCollateralParams(collateralDenom).LiquidationRatio) {
+			if cdp.IsUnderCollateralized(price, liquidityPrice, parameters.GetCollateralParams(collateralDenom).LiquidationRatio) {
 				filteredCDPs = append(filteredCDPs, cdp)
 			} else {
 				break // break early because list is sorted
@@ -432,10 +686,84 @@ func (k Keeper) GetCDPs(ctx sdk.Context, collateralDenom string, price sdk.Int)
 		}
 		cdps = filteredCDPs
 	}
-
 	return cdps, nil
 }
 
+// cdpKeysVersionKey stores the version of the CDP keys used by the store, see MigrateCDPKeys.
+//  - 1: CDP keys include the nft id
+//  - 2: CDPs are indexed by owner
+var cdpKeysVersionKey = []byte("cdpKeysVersion")
+
+const cdpKeysVersion byte = 2
+
+func (k Keeper) getLegacyCDPKey(cdp types.CDP) []byte {
+	return bytes.Join(
+		[][]byte{
+			[]byte("cdp"),
+			[]byte(cdp.Collateral.Token.GetName()),
+			[]byte(cdp.Owner.String()),
+		},
+		nil, // no separator
+	)
+}
+func (k Keeper) getCDPKeysVersion(ctx sdk.Context) byte {
+	bz := ctx.KVStore(k.storeKey).Get(cdpKeysVersionKey)
+	if len(bz) != 1 {
+		return 0
+	}
+	return bz[0]
+}
+func (k Keeper) setCDPKeysMigrated(ctx sdk.Context) {
+	ctx.KVStore(k.storeKey).Set(cdpKeysVersionKey, []byte{cdpKeysVersion})
+}
+
+// MigrateCDPKeys brings the CDPs stored under older versions of the keys up to date:
+// CDPs stored under the legacy "cdp<denom><owner>" keys, which did not include the nft id, are moved to the current keys,
+// and CDPs missing from the owner index are added to it.
+// It does nothing once the store is up to date, stores initialized from genesis already are.
+func (k Keeper) MigrateCDPKeys(ctx sdk.Context) {
+	version := k.getCDPKeysVersion(ctx)
+	if version >= cdpKeysVersion {
+		return
+	}
+	store := ctx.KVStore(k.storeKey)
+
+	// Collect the CDPs first, the store can't be written while iterating
+	var legacyKeys [][]byte
+	var cdps types.CDPs
+	iter := sdk.KVStorePrefixIterator(store, []byte("cdp"))
+	for ; iter.Valid(); iter.Next() {
+		var cdp types.CDP
+		if bytes.HasPrefix(iter.Key(), cdpKeyPrefix) {
+			// already using the current keys, only the owner index might be missing
+			k.cdc.MustUnmarshalBinaryLengthPrefixed(iter.Value(), &cdp)
+			legacyKeys = append(legacyKeys, nil)
+			cdps = append(cdps, cdp)
+			continue
+		}
+		if version >= 1 {
+			continue // no legacy keys left
+		}
+		if err := k.cdc.UnmarshalBinaryLengthPrefixed(iter.Value(), &cdp); err != nil {
+			continue // not a CDP, eg the collateral state of a denom starting with "cdp"
+		}
+		if cdp.Collateral.Token == nil || !bytes.Equal(k.getLegacyCDPKey(cdp), iter.Key()) {
+			continue
+		}
+		legacyKeys = append(legacyKeys, iter.Key())
+		cdps = append(cdps, cdp)
+	}
+	iter.Close()
+
+	for i, cdp := range cdps {
+		if legacyKeys[i] != nil {
+			store.Delete(legacyKeys[i])
+		}
+		k.setCDP(ctx, cdp) // also writes the owner index
+	}
+	k.setCDPKeysMigrated(ctx)
+}
+
 var globalDebtKey = []byte("globalDebt")
 
 func (k Keeper) GetGlobalDebt(ctx sdk.Context) sdk.Int {
@@ -517,13 +845,44 @@ func (k Keeper) setCollateralState(ctx sdk.Context, collateralstate types.Collat
 // 	DelegateCoins(ctx sdk.Context, addr sdk.AccAddress, amt sdk.Coins) (sdk.Tags, sdk.Error)
 // 	UndelegateCoins(ctx sdk.Context, addr sdk.AccAddress, amt sdk.Coins) (sdk.Tags, sdk.Error)
 
-var LiquidatorAccountAddress = sdk.AccAddress([]byte("whatever"))
+// LiquidatorAccountAddress is the address of the liquidator module account, derived from the liquidator module name
+var LiquidatorAccountAddress = types.NewModuleAddress("liquidator")
 var liquidatorAccountKey = []byte("liquidatorAccount")
 
 func (k Keeper) GetLiquidatorAccountAddress() sdk.AccAddress {
 	return LiquidatorAccountAddress
 }
 
+// ReturnSeizedLiquidity moves stable coin raised by the liquidator back to the pool, repaying the liquidity lent to the seized CDPs.
+func (k Keeper) ReturnSeizedLiquidity(ctx sdk.Context, amount sdk.Coin) sdk.Error {
+	if err := k.releaseLiquidatorCoins(ctx, amount); err != nil {
+		return err
+	}
+	return k.pool.RepayLiquidity(ctx, LiquidatorAccountAddress, amount)
+}
+
+// PayLiquidationPenalty moves liquidation penalties raised by the liquidator to the pool, where they raise the share price of the depositors.
+func (k Keeper) PayLiquidationPenalty(ctx sdk.Context, penalty sdk.Coin) sdk.Error {
+	if err := k.releaseLiquidatorCoins(ctx, penalty); err != nil {
+		return err
+	}
+	return k.pool.PayFees(ctx, LiquidatorAccountAddress, penalty)
+}
+
+// releaseLiquidatorCoins moves coins out of the liquidator module account kept in the cdp store, into the bank balance of its address,
+// so the pool can take them from there. Sends to the address are blocked, so the balance is otherwise empty.
+func (k Keeper) releaseLiquidatorCoins(ctx sdk.Context, amount sdk.Coin) sdk.Error {
+	if !amount.IsPositive() {
+		return nil
+	}
+	_, err := k.SubtractCoins(ctx, LiquidatorAccountAddress, sdk.NewCoins(amount))
+	if err != nil {
+		return err
+	}
+	_, err = k.bank.AddCoins(ctx, LiquidatorAccountAddress, sdk.NewCoins(amount))
+	return err
+}
+
 type LiquidatorModuleAccount struct {
 	Coins sdk.Coins // keeps track of seized collateral, surplus usdx, and mints/burns gov coins
 }
@@ -1,9 +1,10 @@
 package cdp
 
 import (
-	"fmt"
 	"testing"
 
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/pool"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/mock"
@@ -11,180 +12,208 @@ import (
 	abci "github.com/tendermint/tendermint/abci/types"
 )
 
-// How could one reduce the number of params in the test cases. Create a table driven test for each of the 4 add/withdraw collateral/debt?
-
 func TestKeeper_ModifyCDP(t *testing.T) {
 	_, addrs := mock.GeneratePrivKeyAddressPairs(1)
 	ownerAddr := addrs[0]
 
-	type state struct { // TODO this allows invalid state to be set up, should it?
-		CDP             CDP
-		OwnerCoins      sdk.Coins
-		GlobalDebt      sdk.Int
-		CollateralState CollateralState
+	type change struct {
+		collateral types.Collateral
+		liquidity  types.Liquidity
+		expectPass bool
 	}
-	type args struct {
-		owner              sdk.AccAddress
-		collateralDenom    string
-		changeInCollateral sdk.Int
-		changeInDebt       sdk.Int
+	type state struct {
+		cdpFound     bool
+		cdp          types.CDP
+		ownerCoins   sdk.Coins
+		globalDebt   sdk.Int
+		collateralTD sdk.Int // total debt of the collateral type
 	}
-
 	tests := []struct {
-		name       string
-		priorState state
-		price      string
-		// also missing CDPModuleParams
-		args          args
-		expectPass    bool
-		expectedState state
+		name    string
+		changes []change
+		final   state
 	}{
 		{
-			"addCollateralAndDecreaseDebt",
-			state{CDP{ownerAddr, "xrp", i(100), i(2)}, cs(c("xrp", 10), c(StableDenom, 2)), i(2), CollateralState{"xrp", i(2)}},
-			"10.345",
-			args{ownerAddr, "xrp", i(10), i(-1)},
-			true,
-			state{CDP{ownerAddr, "xrp", i(110), i(1)}, cs( /*  0xrp  */ c(StableDenom, 1)), i(1), CollateralState{"xrp", i(1)}},
+			"deposit",
+			[]change{{ftCollateral("xrp", 400), liq(stableDenom, 0), true}},
+			state{true, ftCDP(ownerAddr, "xrp", 400, 0), cs(c("xrp", 600), c(stableDenom, 100)), i(0), i(0)},
+		},
+		{
+			"depositAndDraw",
+			[]change{{ftCollateral("xrp", 400), liq(stableDenom, 100), true}},
+			state{true, ftCDP(ownerAddr, "xrp", 400, 100), cs(c("xrp", 600), c(stableDenom, 200)), i(100), i(100)},
 		},
 		{
-			"removeTooMuchCollateral",
-			state{CDP{ownerAddr, "xrp", i(1000), i(200)}, cs(c("xrp", 10), c(StableDenom, 10)), i(200), CollateralState{"xrp", i(200)}},
-			"1.00",
-			args{ownerAddr, "xrp", i(-601), i(0)},
-			false,
-			state{CDP{ownerAddr, "xrp", i(1000), i(200)}, cs(c("xrp", 10), c(StableDenom, 10)), i(200), CollateralState{"xrp", i(200)}},
+			"drawInSteps",
+			[]change{
+				{ftCollateral("xrp", 400), liq(stableDenom, 0), true},
+				{ftCollateral("xrp", 0), liq(stableDenom, 100), true},
+				{ftCollateral("xrp", 0), liq(stableDenom, 50), true},
+			},
+			state{true, ftCDP(ownerAddr, "xrp", 400, 150), cs(c("xrp", 600), c(stableDenom, 250)), i(150), i(150)},
 		},
 		{
-			"withdrawTooMuchStableCoin",
-			state{CDP{ownerAddr, "xrp", i(1000), i(200)}, cs(c("xrp", 10), c(StableDenom, 10)), i(200), CollateralState{"xrp", i(200)}},
-			"1.00",
-			args{ownerAddr, "xrp", i(0), i(301)},
-			false,
-			state{CDP{ownerAddr, "xrp", i(1000), i(200)}, cs(c("xrp", 10), c(StableDenom, 10)), i(200), CollateralState{"xrp", i(200)}},
+			"repay",
+			[]change{
+				{ftCollateral("xrp", 400), liq(stableDenom, 150), true},
+				{ftCollateral("xrp", 0), liq(stableDenom, -50), true},
+			},
+			state{true, ftCDP(ownerAddr, "xrp", 400, 100), cs(c("xrp", 600), c(stableDenom, 200)), i(100), i(100)},
 		},
 		{
-			"createCDPAndWithdrawStable",
-			state{CDP{}, cs(c("xrp", 10), c(StableDenom, 10)), i(0), CollateralState{"xrp", i(0)}},
-			"1.00",
-			args{ownerAddr, "xrp", i(5), i(2)},
-			true,
-			state{CDP{ownerAddr, "xrp", i(5), i(2)}, cs(c("xrp", 5), c(StableDenom, 12)), i(2), CollateralState{"xrp", i(2)}},
+			"withdraw",
+			[]change{
+				{ftCollateral("xrp", 400), liq(stableDenom, 100), true},
+				{ftCollateral("xrp", -200), liq(stableDenom, 0), true},
+			},
+			state{true, ftCDP(ownerAddr, "xrp", 200, 100), cs(c("xrp", 800), c(stableDenom, 200)), i(100), i(100)},
 		},
 		{
-			"emptyCDP",
-			state{CDP{ownerAddr, "xrp", i(1000), i(200)}, cs(c("xrp", 10), c(StableDenom, 201)), i(200), CollateralState{"xrp", i(200)}},
-			"1.00",
-			args{ownerAddr, "xrp", i(-1000), i(-200)},
-			true,
-			state{CDP{}, cs(c("xrp", 1010), c(StableDenom, 1)), i(0), CollateralState{"xrp", i(0)}},
+			"repayAndWithdrawAll",
+			[]change{
+				{ftCollateral("xrp", 400), liq(stableDenom, 100), true},
+				{ftCollateral("xrp", 0), liq(stableDenom, -100), true},
+				{ftCollateral("xrp", -400), liq(stableDenom, 0), true},
+			},
+			state{false, types.CDP{}, cs(c("xrp", 1000), c(stableDenom, 100)), i(0), i(0)},
 		},
 		{
-			"invalidCollateralType",
-			state{CDP{}, cs(c("shitcoin", 5000000)), i(0), CollateralState{}},
-			"0.000001",
-			args{ownerAddr, "shitcoin", i(5000000), i(1)}, // ratio of 5:1
-			false,
-			state{CDP{}, cs(c("shitcoin", 5000000)), i(0), CollateralState{}},
+			"drawUnderCollateralized",
+			[]change{
+				{ftCollateral("xrp", 400), liq(stableDenom, 100), true},
+				{ftCollateral("xrp", 0), liq(stableDenom, 101), false},
+			},
+			state{true, ftCDP(ownerAddr, "xrp", 400, 100), cs(c("xrp", 600), c(stableDenom, 200)), i(100), i(100)},
+		},
+		{
+			"withdrawUnderCollateralized",
+			[]change{
+				{ftCollateral("xrp", 400), liq(stableDenom, 100), true},
+				{ftCollateral("xrp", -201), liq(stableDenom, 0), false},
+			},
+			state{true, ftCDP(ownerAddr, "xrp", 400, 100), cs(c("xrp", 600), c(stableDenom, 200)), i(100), i(100)},
+		},
+		{
+			"dust",
+			[]change{{ftCollateral("xrp", 400), liq(stableDenom, 5), false}},
+			state{false, types.CDP{}, cs(c("xrp", 1000), c(stableDenom, 100)), i(0), i(0)},
+		},
+		{
+			"repayMoreThanDebt",
+			[]change{
+				{ftCollateral("xrp", 400), liq(stableDenom, 100), true},
+				{ftCollateral("xrp", 0), liq(stableDenom, -101), false},
+			},
+			state{true, ftCDP(ownerAddr, "xrp", 400, 100), cs(c("xrp", 600), c(stableDenom, 200)), i(100), i(100)},
+		},
+		{
+			"notEnoughCollateral",
+			[]change{{ftCollateral("xrp", 1001), liq(stableDenom, 0), false}},
+			state{false, types.CDP{}, cs(c("xrp", 1000), c(stableDenom, 100)), i(0), i(0)},
+		},
+		{
+			"otherLiquidityDenom",
+			[]change{
+				{ftCollateral("xrp", 400), liq(stableDenom, 100), true},
+				{ftCollateral("xrp", 0), liq("usdx", 10), false},
+			},
+			state{true, ftCDP(ownerAddr, "xrp", 400, 100), cs(c("xrp", 600), c(stableDenom, 200)), i(100), i(100)},
+		},
+		{
+			"drawForeignDenom",
+			[]change{{ftCollateral("xrp", 400), liq("btc", 100), false}},
+			state{false, types.CDP{}, cs(c("xrp", 1000), c(stableDenom, 100)), i(0), i(0)},
+		},
+		{
+			"drawGovDenom",
+			[]change{{ftCollateral("xrp", 400), liq(GovDenom, 100), false}},
+			state{false, types.CDP{}, cs(c("xrp", 1000), c(stableDenom, 100)), i(0), i(0)},
 		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			// setup keeper
+			// setup keeper and an owner with some collateral and stable coin
 			mapp, keeper := setUpMockAppWithoutGenesis()
-			// initialize cdp owner account with coins
-			genAcc := auth.BaseAccount{
-				Address: ownerAddr,
-				Coins:   tc.priorState.OwnerCoins,
-			}
+			genAcc := auth.BaseAccount{Address: ownerAddr, Coins: cs(c("xrp", 1000), c(stableDenom, 100))}
 			mock.SetGenesis(mapp, []auth.Account{&genAcc})
-			// create a new context
 			header := abci.Header{Height: mapp.LastBlockHeight() + 1}
 			mapp.BeginBlock(abci.RequestBeginBlock{Header: header})
 			ctx := mapp.BaseApp.NewContext(false, header)
-			// setup store state
-			keeper.pricefeed.AddAsset(ctx, "xrp", "xrp test")
-			keeper.pricefeed.SetPrice(
-				ctx, sdk.AccAddress{}, "xrp",
-				sdk.MustNewDecFromStr(tc.price),
-				i(10))
-			keeper.pricefeed.SetCurrentPrices(ctx)
-			if tc.priorState.CDP.CollateralDenom != "" { // check if the prior CDP should be created or not (see if an empty one was specified)
-				keeper.setCDP(ctx, tc.priorState.CDP)
-			}
-			keeper.setGlobalDebt(ctx, tc.priorState.GlobalDebt)
-			if tc.priorState.CollateralState.Denom != "" {
-				keeper.setCollateralState(ctx, tc.priorState.CollateralState)
-			}
+			setCurrentPrice(ctx, keeper, "xrp", 1)
+			// the pool also holds other coins, which can't be drawn from CDPs
+			pool := keeper.pool.(mockPool)
+			pool.available["btc"] = i(1000)
+			pool.available[GovDenom] = i(1000)
 
-			// call func under test
-			err := keeper.ModifyCDP(ctx, tc.args.owner, tc.args.collateralDenom, tc.args.changeInCollateral, tc.args.changeInDebt)
-			mapp.EndBlock(abci.RequestEndBlock{})
-			mapp.Commit()
+			// apply the changes
+			for _, ch := range tc.changes {
+				err := keeper.ModifyCDP(ctx, ownerAddr, ch.collateral, ch.liquidity)
+				if ch.expectPass {
+					require.NoError(t, err)
+				} else {
+					require.Error(t, err)
+				}
+			}
 
-			// check for err
-			if tc.expectPass {
-				require.NoError(t, err, fmt.Sprint(err))
-			} else {
-				require.Error(t, err)
+			// check the final state
+			cdp, found := keeper.GetCDP(ctx, ownerAddr, "xrp", "")
+			require.Equal(t, tc.final.cdpFound, found)
+			if found {
+				tc.final.cdp.FeesUpdated = ctx.BlockHeight()
+				require.Equal(t, tc.final.cdp, cdp)
 			}
-			// get new state for verification
-			actualCDP, found := keeper.GetCDP(ctx, tc.args.owner, tc.args.collateralDenom)
-			actualGDebt := keeper.GetGlobalDebt(ctx)
-			actualCstate, _ := keeper.GetCollateralState(ctx, tc.args.collateralDenom)
-			// check state
-			require.Equal(t, tc.expectedState.CDP, actualCDP)
-			if tc.expectedState.CDP.CollateralDenom == "" { // if the expected CDP is blank, then expect the CDP to have been deleted (hence not found)
-				require.False(t, found)
+			require.Equal(t, tc.final.ownerCoins, keeper.bank.GetCoins(ctx, ownerAddr))
+			require.Equal(t, tc.final.globalDebt.Int64(), keeper.GetGlobalDebt(ctx).Int64())
+			collateralState, found := keeper.GetCollateralState(ctx, "xrp")
+			if found {
+				require.Equal(t, tc.final.collateralTD.Int64(), collateralState.TotalDebt.Int64())
 			} else {
-				require.True(t, found)
+				require.True(t, tc.final.collateralTD.IsZero())
 			}
-			require.Equal(t, tc.expectedState.GlobalDebt, actualGDebt)
-			require.Equal(t, tc.expectedState.CollateralState, actualCstate)
-			// check owner balance
-			mock.CheckBalance(t, mapp, ownerAddr, tc.expectedState.OwnerCoins)
 		})
 	}
 }
 
-// TODO change to table driven test to test more test cases
 func TestKeeper_PartialSeizeCDP(t *testing.T) {
-	// Setup
-	const collateral = "xrp"
+	// setup keeper and a safe CDP
 	mapp, keeper := setUpMockAppWithoutGenesis()
-	genAccs, addrs, _, _ := mock.CreateGenAccounts(1, cs(c(collateral, 100)))
-	testAddr := addrs[0]
-	mock.SetGenesis(mapp, genAccs)
-	// setup pricefeed
+	_, addrs := mock.GeneratePrivKeyAddressPairs(1)
+	genAcc := auth.BaseAccount{Address: addrs[0], Coins: cs(c("xrp", 100))}
+	mock.SetGenesis(mapp, []auth.Account{&genAcc})
 	header := abci.Header{Height: mapp.LastBlockHeight() + 1}
 	mapp.BeginBlock(abci.RequestBeginBlock{Header: header})
 	ctx := mapp.BaseApp.NewContext(false, header)
-	keeper.pricefeed.AddAsset(ctx, collateral, "test description")
-	keeper.pricefeed.SetPrice(
-		ctx, sdk.AccAddress{}, collateral,
-		sdk.MustNewDecFromStr("1.00"),
-		i(10))
-	keeper.pricefeed.SetCurrentPrices(ctx)
-	// Create CDP
-	err := keeper.ModifyCDP(ctx, testAddr, collateral, i(10), i(5))
-	require.NoError(t, err)
-	// Reduce price
-	keeper.pricefeed.SetPrice(
-		ctx, sdk.AccAddress{}, collateral,
-		sdk.MustNewDecFromStr("0.90"),
-		i(10))
-	keeper.pricefeed.SetCurrentPrices(ctx)
+	setCurrentPrice(ctx, keeper, "xrp", 10)
+	require.NoError(t, keeper.ModifyCDP(ctx, addrs[0], ftCollateral("xrp", 40), liq(stableDenom, 190)))
 
-	// Seize entire CDP
-	err = keeper.PartialSeizeCDP(ctx, testAddr, collateral, i(10), i(5))
+	// check a safe CDP can't be seized
+	require.Error(t, keeper.PartialSeizeCDP(ctx, addrs[0], ftCollateral("xrp", 0), i(10), i(50)))
 
-	// Check
-	require.NoError(t, err)
-	_, found := keeper.GetCDP(ctx, testAddr, collateral)
-	require.False(t, found)
-	collateralState, found := keeper.GetCollateralState(ctx, collateral)
+	// drop the price and check the CDP can't be seized while either price is stale
+	setCurrentPrice(ctx, keeper, "xrp", 9)
+	stale := keeper.pricefeed.(mockPricefeed).stale
+	stale["xrp"] = true
+	require.Error(t, keeper.PartialSeizeCDP(ctx, addrs[0], ftCollateral("xrp", 0), i(10), i(50)))
+	delete(stale, "xrp")
+	stale[stableDenom] = true
+	require.Error(t, keeper.PartialSeizeCDP(ctx, addrs[0], ftCollateral("xrp", 0), i(10), i(50)))
+	delete(stale, stableDenom)
+
+	// seize part of the CDP
+	require.NoError(t, keeper.PartialSeizeCDP(ctx, addrs[0], ftCollateral("xrp", 0), i(10), i(50)))
+
+	// check the collateral and debt are removed from the CDP and the collateral type, but not from the global debt
+	cdp, found := keeper.GetCDP(ctx, addrs[0], "xrp", "")
 	require.True(t, found)
-	require.Equal(t, sdk.ZeroInt(), collateralState.TotalDebt)
+	require.Equal(t, i(30), cdp.Collateral.Amount)
+	require.Equal(t, i(140), cdp.Liquidity.Coin.Amount)
+	collateralState, _ := keeper.GetCollateralState(ctx, "xrp")
+	require.Equal(t, i(140).Int64(), collateralState.TotalDebt.Int64())
+	require.Equal(t, i(190), keeper.GetGlobalDebt(ctx))
+
+	// check more than the CDP holds can't be seized
+	require.Error(t, keeper.PartialSeizeCDP(ctx, addrs[0], ftCollateral("xrp", 0), i(31), i(50)))
+	require.Error(t, keeper.PartialSeizeCDP(ctx, addrs[0], ftCollateral("xrp", 0), i(10), i(141)))
 }
 
 func TestKeeper_GetCDPs(t *testing.T) {
@@ -196,73 +225,119 @@ func TestKeeper_GetCDPs(t *testing.T) {
 	ctx := mapp.BaseApp.NewContext(false, header)
 	// setup CDPs
 	_, addrs := mock.GeneratePrivKeyAddressPairs(2)
-	cdps := CDPs{
-		{addrs[0], "xrp", i(4000), i(5)},
-		{addrs[1], "xrp", i(4000), i(2000)},
-		{addrs[0], "btc", i(10), i(20)},
+	cdps := types.CDPs{
+		ftCDP(addrs[0], "xrp", 4000, 5),
+		ftCDP(addrs[1], "xrp", 4000, 2500),
+		ftCDP(addrs[0], "btc", 10, 20),
 	}
 	for _, cdp := range cdps {
 		keeper.setCDP(ctx, cdp)
 	}
 
-	// Check nil params returns all CDPs
-	returnedCdps, err := keeper.GetCDPs(ctx, "", sdk.Dec{})
+	// Check negative price returns all CDPs
+	returnedCdps, err := keeper.GetCDPs(ctx, "", "", i(-1))
 	require.NoError(t, err)
 	require.Equal(t,
-		CDPs{
-			{addrs[0], "btc", i(10), i(20)},
-			{addrs[1], "xrp", i(4000), i(2000)},
-			{addrs[0], "xrp", i(4000), i(5)}},
+		types.CDPs{
+			ftCDP(addrs[0], "btc", 10, 20),
+			ftCDP(addrs[1], "xrp", 4000, 2500),
+			ftCDP(addrs[0], "xrp", 4000, 5)},
 		returnedCdps,
 	)
 	// Check correct CDPs filtered by collateral and sorted
-	returnedCdps, err = keeper.GetCDPs(ctx, "xrp", d("0.00000001"))
+	returnedCdps, err = keeper.GetCDPs(ctx, "xrp", "", i(-1))
 	require.NoError(t, err)
 	require.Equal(t,
-		CDPs{
-			{addrs[1], "xrp", i(4000), i(2000)},
-			{addrs[0], "xrp", i(4000), i(5)}},
+		types.CDPs{
+			ftCDP(addrs[1], "xrp", 4000, 2500),
+			ftCDP(addrs[0], "xrp", 4000, 5)},
 		returnedCdps,
 	)
-	returnedCdps, err = keeper.GetCDPs(ctx, "xrp", sdk.Dec{})
+	returnedCdps, err = keeper.GetCDPs(ctx, "xrp", "", i(0))
 	require.NoError(t, err)
 	require.Equal(t,
-		CDPs{
-			{addrs[1], "xrp", i(4000), i(2000)},
-			{addrs[0], "xrp", i(4000), i(5)}},
+		types.CDPs{
+			ftCDP(addrs[1], "xrp", 4000, 2500),
+			ftCDP(addrs[0], "xrp", 4000, 5)},
 		returnedCdps,
 	)
-	returnedCdps, err = keeper.GetCDPs(ctx, "xrp", d("0.9"))
+	returnedCdps, err = keeper.GetCDPs(ctx, "xrp", "", i(1))
 	require.NoError(t, err)
 	require.Equal(t,
-		CDPs{
-			{addrs[1], "xrp", i(4000), i(2000)}},
+		types.CDPs{
+			ftCDP(addrs[1], "xrp", 4000, 2500)},
 		returnedCdps,
 	)
 	// Check high price returns no CDPs
-	returnedCdps, err = keeper.GetCDPs(ctx, "xrp", d("999999999.99"))
+	returnedCdps, err = keeper.GetCDPs(ctx, "xrp", "", i(999999999))
 	require.NoError(t, err)
 	require.Equal(t,
-		CDPs(nil),
+		types.CDPs(nil),
 		returnedCdps,
 	)
+	// Check filtering by price returns error while the stable coin price is stale
+	keeper.pricefeed.(mockPricefeed).stale[stableDenom] = true
+	_, err = keeper.GetCDPs(ctx, "xrp", "", i(1))
+	require.Error(t, err)
+	delete(keeper.pricefeed.(mockPricefeed).stale, stableDenom)
 	// Check unauthorized collateral denom returns error
-	_, err = keeper.GetCDPs(ctx, "a non existent coin", d("0.34023"))
+	_, err = keeper.GetCDPs(ctx, "a non existent coin", "", i(34023))
 	require.Error(t, err)
 	// Check price without collateral returns error
-	_, err = keeper.GetCDPs(ctx, "", d("0.34023"))
+	_, err = keeper.GetCDPs(ctx, "", "", i(34023))
+	require.Error(t, err)
+	// Check nft id without collateral returns error
+	_, err = keeper.GetCDPs(ctx, "", "1", i(-1))
 	require.Error(t, err)
 	// Check deleting a CDP removes it
 	keeper.deleteCDP(ctx, cdps[0])
-	returnedCdps, err = keeper.GetCDPs(ctx, "", sdk.Dec{})
+	returnedCdps, err = keeper.GetCDPs(ctx, "", "", i(-1))
 	require.NoError(t, err)
 	require.Equal(t,
-		CDPs{
-			{addrs[0], "btc", i(10), i(20)},
-			{addrs[1], "xrp", i(4000), i(2000)}},
+		types.CDPs{
+			ftCDP(addrs[0], "btc", 10, 20),
+			ftCDP(addrs[1], "xrp", 4000, 2500)},
 		returnedCdps,
 	)
 }
+
+func TestKeeper_GetCDPs_NFT(t *testing.T) {
+	// setup keeper
+	mapp, keeper := setUpMockAppWithoutGenesis()
+	mock.SetGenesis(mapp, []auth.Account(nil))
+	header := abci.Header{Height: mapp.LastBlockHeight() + 1}
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := mapp.BaseApp.NewContext(false, header)
+	params := keeper.GetParams(ctx)
+	params.CollateralParams = append(params.CollateralParams, types.CollateralParams{Denom: "art", LiquidationRatio: d("1.5"), DebtLimit: i(500000), DebtFloor: i(10)})
+	keeper.setParams(ctx, params)
+	// setup CDPs, "1" being a prefix of "10" must not matter
+	_, addrs := mock.GeneratePrivKeyAddressPairs(2)
+	cdps := types.CDPs{
+		nftCDP(addrs[0], "art", "1", 5),
+		nftCDP(addrs[1], "art", "1", 10),
+		nftCDP(addrs[0], "art", "10", 20),
+	}
+	for _, cdp := range cdps {
+		keeper.setCDP(ctx, cdp)
+	}
+
+	// Check all the CDPs of the collateral type are returned
+	returnedCdps, err := keeper.GetCDPs(ctx, "art", "", i(-1))
+	require.NoError(t, err)
+	require.Len(t, returnedCdps, 3)
+	// Check filtering by nft id
+	returnedCdps, err = keeper.GetCDPs(ctx, "art", "1", i(-1))
+	require.NoError(t, err)
+	require.Equal(t, types.CDPs{cdps[1], cdps[0]}, returnedCdps)
+	returnedCdps, err = keeper.GetCDPs(ctx, "art", "10", i(-1))
+	require.NoError(t, err)
+	require.Equal(t, types.CDPs{cdps[2]}, returnedCdps)
+	returnedCdps, err = keeper.GetCDPs(ctx, "art", "2", i(-1))
+	require.NoError(t, err)
+	require.Equal(t, types.CDPs(nil), returnedCdps)
+}
+
 func TestKeeper_GetSetDeleteCDP(t *testing.T) {
 	// setup keeper, create CDP
 	mapp, keeper := setUpMockAppWithoutGenesis()
@@ -270,23 +345,408 @@ func TestKeeper_GetSetDeleteCDP(t *testing.T) {
 	mapp.BeginBlock(abci.RequestBeginBlock{Header: header})
 	ctx := mapp.BaseApp.NewContext(false, header)
 	_, addrs := mock.GeneratePrivKeyAddressPairs(1)
-	cdp := CDP{addrs[0], "xrp", i(412), i(56)}
+	cdp := ftCDP(addrs[0], "xrp", 412, 56)
 
 	// write and read from store
 	keeper.setCDP(ctx, cdp)
-	readCDP, found := keeper.GetCDP(ctx, cdp.Owner, cdp.CollateralDenom)
+	readCDP, found := keeper.GetCDP(ctx, cdp.Owner, "xrp", "")
 
 	// check before and after match
 	require.True(t, found)
 	require.Equal(t, cdp, readCDP)
 
-	// delete auction
+	// delete cdp
 	keeper.deleteCDP(ctx, cdp)
 
-	// check auction does not exist
-	_, found = keeper.GetCDP(ctx, cdp.Owner, cdp.CollateralDenom)
+	// check cdp does not exist
+	_, found = keeper.GetCDP(ctx, cdp.Owner, "xrp", "")
+	require.False(t, found)
+}
+
+func TestKeeper_GetSetDeleteCDP_NFT(t *testing.T) {
+	// setup keeper
+	mapp, keeper := setUpMockAppWithoutGenesis()
+	header := abci.Header{Height: mapp.LastBlockHeight() + 1}
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := mapp.BaseApp.NewContext(false, header)
+	_, addrs := mock.GeneratePrivKeyAddressPairs(1)
+
+	// two tokens of the same collection owned by the same address
+	first := nftCDP(addrs[0], "art", "1", 10)
+	second := nftCDP(addrs[0], "art", "2", 20)
+	keeper.setCDP(ctx, first)
+	keeper.setCDP(ctx, second)
+
+	// check the second one did not overwrite the first
+	readCDP, found := keeper.GetCDP(ctx, addrs[0], "art", "1")
+	require.True(t, found)
+	require.Equal(t, first, readCDP)
+	readCDP, found = keeper.GetCDP(ctx, addrs[0], "art", "2")
+	require.True(t, found)
+	require.Equal(t, second, readCDP)
+
+	// delete only the first one
+	keeper.deleteCDP(ctx, first)
+	_, found = keeper.GetCDP(ctx, addrs[0], "art", "1")
+	require.False(t, found)
+	_, found = keeper.GetCDP(ctx, addrs[0], "art", "2")
+	require.True(t, found)
+}
+
+func TestKeeper_GetOwnerCDPs(t *testing.T) {
+	// setup keeper
+	mapp, keeper := setUpMockAppWithoutGenesis()
+	header := abci.Header{Height: mapp.LastBlockHeight() + 1}
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := mapp.BaseApp.NewContext(false, header)
+	_, addrs := mock.GeneratePrivKeyAddressPairs(2)
+	cdps := types.CDPs{
+		ftCDP(addrs[0], "xrp", 4000, 5),
+		nftCDP(addrs[0], "art", "1", 10),
+		nftCDP(addrs[0], "art", "2", 20),
+		ftCDP(addrs[1], "btc", 10, 20),
+	}
+	for _, cdp := range cdps {
+		keeper.setCDP(ctx, cdp)
+	}
+
+	// check all the CDPs of the owner are returned, whatever their collateral
+	require.Equal(t, types.CDPs{cdps[1], cdps[2], cdps[0]}, keeper.GetOwnerCDPs(ctx, addrs[0]))
+	require.Equal(t, types.CDPs{cdps[3]}, keeper.GetOwnerCDPs(ctx, addrs[1]))
+
+	// check deleted CDPs are removed from the index
+	keeper.deleteCDP(ctx, cdps[1])
+	require.Equal(t, types.CDPs{cdps[2], cdps[0]}, keeper.GetOwnerCDPs(ctx, addrs[0]))
+	keeper.deleteCDP(ctx, cdps[3])
+	require.Equal(t, types.CDPs(nil), keeper.GetOwnerCDPs(ctx, addrs[1]))
+}
+
+func TestKeeper_TransferCDP(t *testing.T) {
+	// setup keeper
+	mapp, keeper := setUpMockAppWithoutGenesis()
+	header := abci.Header{Height: mapp.LastBlockHeight() + 1}
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := mapp.BaseApp.NewContext(false, header)
+	_, addrs := mock.GeneratePrivKeyAddressPairs(2)
+	keeper.setCDP(ctx, ftCDP(addrs[0], "xrp", 4000, 5))
+	keeper.setCDP(ctx, nftCDP(addrs[0], "art", "1", 10))
+	keeper.setCDP(ctx, ftCDP(addrs[1], "btc", 10, 20))
+
+	// transfer a FT backed CDP
+	require.NoError(t, keeper.TransferCDP(ctx, addrs[0], addrs[1], "xrp", ""))
+	_, found := keeper.GetCDP(ctx, addrs[0], "xrp", "")
+	require.False(t, found)
+	cdp, found := keeper.GetCDP(ctx, addrs[1], "xrp", "")
+	require.True(t, found)
+	require.Equal(t, ftCDP(addrs[1], "xrp", 4000, 5), cdp)
+
+	// transfer a NFT backed CDP, the NFT changes owner too
+	require.NoError(t, keeper.TransferCDP(ctx, addrs[0], addrs[1], "art", "1"))
+	cdp, found = keeper.GetCDP(ctx, addrs[1], "art", "1")
+	require.True(t, found)
+	require.Equal(t, nftCDP(addrs[1], "art", "1", 10), cdp)
+	require.Equal(t, addrs[1], cdp.Collateral.Token.(BaseNFT).GetOwner())
+	require.Equal(t, types.CDPs(nil), keeper.GetOwnerCDPs(ctx, addrs[0]))
+	require.Len(t, keeper.GetOwnerCDPs(ctx, addrs[1]), 3)
+
+	// the receiver can hold several CDPs backed by NFTs of the same denom
+	keeper.setCDP(ctx, nftCDP(addrs[0], "art", "2", 15))
+	require.NoError(t, keeper.TransferCDP(ctx, addrs[0], addrs[1], "art", "2"))
+	cdp, found = keeper.GetCDP(ctx, addrs[1], "art", "2")
+	require.True(t, found)
+	require.Equal(t, nftCDP(addrs[1], "art", "2", 15), cdp)
+	_, found = keeper.GetCDP(ctx, addrs[1], "art", "1")
+	require.True(t, found)
+	require.Len(t, keeper.GetOwnerCDPs(ctx, addrs[1]), 4)
+
+	// check invalid transfers
+	require.Error(t, keeper.TransferCDP(ctx, addrs[0], addrs[1], "xrp", "")) // no CDP
+	require.Error(t, keeper.TransferCDP(ctx, addrs[1], addrs[1], "xrp", "")) // same owner
+	keeper.setCDP(ctx, ftCDP(addrs[0], "btc", 1, 1))
+	require.Error(t, keeper.TransferCDP(ctx, addrs[0], addrs[1], "btc", "")) // receiver already has one
+	keeper.setCDP(ctx, nftCDP(addrs[0], "art", "1", 1))
+	require.Error(t, keeper.TransferCDP(ctx, addrs[0], addrs[1], "art", "1")) // receiver already has one with the same NFT id
+}
+
+func TestKeeper_CloseCDP(t *testing.T) {
+	// setup keeper and an owner holding enough stable coin to repay the debt and fees
+	mapp, keeper := setUpMockAppWithoutGenesis()
+	_, addrs := mock.GeneratePrivKeyAddressPairs(1)
+	genAcc := auth.BaseAccount{Address: addrs[0], Coins: cs(c(stableDenom, 1100000))}
+	mock.SetGenesis(mapp, []auth.Account{&genAcc})
+	header := abci.Header{Height: mapp.LastBlockHeight() + 1}
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := mapp.BaseApp.NewContext(false, header).WithBlockHeight(BlocksPerYear + 1)
+
+	// a CDP that has been accruing fees for a year
+	cdp := ftCDP(addrs[0], "xrp", 4000, 1000000)
+	cdp.FeesUpdated = ctx.BlockHeight() - BlocksPerYear
+	keeper.setCDP(ctx, cdp)
+	keeper.setGlobalDebt(ctx, i(1000000))
+	keeper.setCollateralState(ctx, types.CollateralState{Denom: "xrp", TotalDebt: i(1000000), AccumulatedFees: i(0), CollectedFees: i(0)})
+
+	// close it
+	require.NoError(t, keeper.CloseCDP(ctx, addrs[0], "xrp", ""))
+
+	// check the CDP is deleted, the debt and fees repaid and the collateral returned
+	_, found := keeper.GetCDP(ctx, addrs[0], "xrp", "")
 	require.False(t, found)
+	require.Equal(t, cs(c(stableDenom, 1100000-1051271), c("xrp", 4000)), keeper.bank.GetCoins(ctx, addrs[0]))
+	// the fees are split between the liquidator and the pool, which gets the rounded down half
+	require.Equal(t, cs(c(stableDenom, 25636)), keeper.GetCoins(ctx, LiquidatorAccountAddress))
+	require.Equal(t, i(1000000000+1000000+25635), keeper.pool.(mockPool).available[stableDenom])
+	require.True(t, keeper.GetGlobalDebt(ctx).IsZero())
+	collateralState, found := keeper.GetCollateralState(ctx, "xrp")
+	require.True(t, found)
+	require.True(t, collateralState.TotalDebt.IsZero())
+	require.True(t, collateralState.AccumulatedFees.IsZero())
+	require.Equal(t, i(51271), collateralState.CollectedFees)
+
+	// check a missing CDP can't be closed
+	require.Error(t, keeper.CloseCDP(ctx, addrs[0], "xrp", ""))
+}
+
+func TestKeeper_CloseCDP_NotEnoughCoins(t *testing.T) {
+	// setup keeper and an owner who spent the stable coin drawn
+	mapp, keeper := setUpMockAppWithoutGenesis()
+	_, addrs := mock.GeneratePrivKeyAddressPairs(1)
+	genAcc := auth.BaseAccount{Address: addrs[0], Coins: cs(c(stableDenom, 10))}
+	mock.SetGenesis(mapp, []auth.Account{&genAcc})
+	header := abci.Header{Height: mapp.LastBlockHeight() + 1}
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := mapp.BaseApp.NewContext(false, header)
+	keeper.setCDP(ctx, nftCDP(addrs[0], "btc", "1", 20))
+	keeper.setGlobalDebt(ctx, i(20))
+	keeper.setCollateralState(ctx, types.CollateralState{Denom: "btc", TotalDebt: i(20), AccumulatedFees: i(0), CollectedFees: i(0)})
+
+	// check nothing changes
+	require.Error(t, keeper.CloseCDP(ctx, addrs[0], "btc", "1"))
+	_, found := keeper.GetCDP(ctx, addrs[0], "btc", "1")
+	require.True(t, found)
+	require.Equal(t, cs(c(stableDenom, 10)), keeper.bank.GetCoins(ctx, addrs[0]))
+	require.Equal(t, i(20), keeper.GetGlobalDebt(ctx))
+}
+
+func TestKeeper_ModifyCDP_PoolLiquidity(t *testing.T) {
+	// setup keeper and a pool holding little liquidity
+	mapp, keeper := setUpMockAppWithoutGenesis()
+	_, addrs := mock.GeneratePrivKeyAddressPairs(1)
+	genAcc := auth.BaseAccount{Address: addrs[0], Coins: cs(c("xrp", 1000))}
+	mock.SetGenesis(mapp, []auth.Account{&genAcc})
+	header := abci.Header{Height: mapp.LastBlockHeight() + 1}
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := mapp.BaseApp.NewContext(false, header)
+	setCurrentPrice(ctx, keeper, "xrp", 1)
+	pool := keeper.pool.(mockPool)
+	pool.available[stableDenom] = i(100)
+
+	// draw from the pool
+	require.NoError(t, keeper.ModifyCDP(ctx, addrs[0], ftCollateral("xrp", 400), liq(stableDenom, 80)))
+	require.Equal(t, i(20), pool.available[stableDenom])
+	require.Equal(t, cs(c("xrp", 600), c(stableDenom, 80)), keeper.bank.GetCoins(ctx, addrs[0]))
+
+	// check drawing more than the pool holds fails, leaving everything unchanged
+	require.Error(t, keeper.ModifyCDP(ctx, addrs[0], ftCollateral("xrp", 0), liq(stableDenom, 21)))
+	cdp, found := keeper.GetCDP(ctx, addrs[0], "xrp", "")
+	require.True(t, found)
+	require.Equal(t, ftCDP(addrs[0], "xrp", 400, 80).Liquidity, cdp.Liquidity)
+	require.Equal(t, i(20), pool.available[stableDenom])
+	require.Equal(t, i(80), keeper.GetGlobalDebt(ctx))
+
+	// repay to the pool
+	require.NoError(t, keeper.ModifyCDP(ctx, addrs[0], ftCollateral("xrp", 0), liq(stableDenom, -50)))
+	require.Equal(t, i(70), pool.available[stableDenom])
+	require.Equal(t, cs(c("xrp", 600), c(stableDenom, 30)), keeper.bank.GetCoins(ctx, addrs[0]))
 }
+
+func TestKeeper_ModifyCDP_RealPool(t *testing.T) {
+	// setup keepers, a depositor funding the pool, a borrower and the owner of a CDP opened before the pool funded CDPs
+	mapp, keeper, poolKeeper, pricefeedKeeper := setUpMockAppWithPool()
+	_, addrs := mock.GeneratePrivKeyAddressPairs(3)
+	depositor, borrower, legacyOwner := addrs[0], addrs[1], addrs[2]
+	genAccs := []auth.Account{
+		&auth.BaseAccount{Address: depositor, Coins: cs(c(stableDenom, 1000))},
+		&auth.BaseAccount{Address: borrower, Coins: cs(c("xrp", 2000))},
+		&auth.BaseAccount{Address: legacyOwner, Coins: cs(c(stableDenom, 50))},
+	}
+	mock.SetGenesis(mapp, genAccs)
+	header := abci.Header{Height: mapp.LastBlockHeight() + 1}
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := mapp.BaseApp.NewContext(false, header)
+	pricefeedKeeper.AddOracle(ctx, depositor.String(), nil)
+	pricefeedKeeper.SetPrice(ctx, depositor, "", stableDenom, i(1), i(9999999))
+	pricefeedKeeper.SetPrice(ctx, depositor, "", "xrp", i(1), i(9999999))
+	_, err := pricefeedKeeper.SetCurrentPrices(ctx)
+	require.NoError(t, err)
+	require.NoError(t, poolKeeper.DepositFundFromAddress(ctx, depositor, c(stableDenom, 500)))
+
+	// draw from the pool
+	require.NoError(t, keeper.ModifyCDP(ctx, borrower, ftCollateral("xrp", 2000), liq(stableDenom, 100)))
+	require.Equal(t, cs(c(stableDenom, 400)), keeper.bank.GetCoins(ctx, pool.ModuleAddress))
+	require.Equal(t, i(100), poolKeeper.GetLentLiquidity(ctx, stableDenom))
+	require.Equal(t, cs(c(stableDenom, 100)), keeper.bank.GetCoins(ctx, borrower))
+
+	// check drawing more than the pool holds fails, leaving everything unchanged
+	require.Error(t, keeper.ModifyCDP(ctx, borrower, ftCollateral("xrp", 0), liq(stableDenom, 401)))
+	cdp, found := keeper.GetCDP(ctx, borrower, "xrp", "")
+	require.True(t, found)
+	require.Equal(t, ftCDP(borrower, "xrp", 2000, 100).Liquidity, cdp.Liquidity)
+	require.Equal(t, cs(c(stableDenom, 400)), keeper.bank.GetCoins(ctx, pool.ModuleAddress))
+	require.Equal(t, i(100), poolKeeper.GetLentLiquidity(ctx, stableDenom))
+
+	// repay to the pool
+	require.NoError(t, keeper.ModifyCDP(ctx, borrower, ftCollateral("xrp", 0), liq(stableDenom, -60)))
+	require.Equal(t, cs(c(stableDenom, 460)), keeper.bank.GetCoins(ctx, pool.ModuleAddress))
+	require.Equal(t, i(40), poolKeeper.GetLentLiquidity(ctx, stableDenom))
+	require.Equal(t, cs(c(stableDenom, 40)), keeper.bank.GetCoins(ctx, borrower))
+	funds, err := poolKeeper.GetAccountDenomFunds(ctx, depositor, stableDenom)
+	require.NoError(t, err)
+	require.Equal(t, c(stableDenom, 500), funds)
+
+	// check repaying debt the pool didn't lend doesn't take the lent liquidity below zero, it adds to the pool value instead
+	keeper.setCDP(ctx, ftCDP(legacyOwner, "xrp", 400, 50))
+	keeper.setGlobalDebt(ctx, keeper.GetGlobalDebt(ctx).Add(i(50)))
+	collateralState, found := keeper.GetCollateralState(ctx, "xrp")
+	require.True(t, found)
+	collateralState.TotalDebt = collateralState.TotalDebt.Add(i(50))
+	keeper.setCollateralState(ctx, collateralState)
+	require.NoError(t, keeper.ModifyCDP(ctx, legacyOwner, ftCollateral("xrp", 0), liq(stableDenom, -50)))
+	require.Equal(t, cs(c(stableDenom, 510)), keeper.bank.GetCoins(ctx, pool.ModuleAddress))
+	require.True(t, poolKeeper.GetLentLiquidity(ctx, stableDenom).IsZero())
+	funds, err = poolKeeper.GetAccountDenomFunds(ctx, depositor, stableDenom)
+	require.NoError(t, err)
+	require.Equal(t, c(stableDenom, 510), funds)
+}
+
+func TestKeeper_ModifyCDP_LiquidityPrice(t *testing.T) {
+	// setup keeper and an owner with some collateral
+	mapp, keeper := setUpMockAppWithoutGenesis()
+	_, addrs := mock.GeneratePrivKeyAddressPairs(1)
+	genAcc := auth.BaseAccount{Address: addrs[0], Coins: cs(c("xrp", 1000))}
+	mock.SetGenesis(mapp, []auth.Account{&genAcc})
+	header := abci.Header{Height: mapp.LastBlockHeight() + 1}
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := mapp.BaseApp.NewContext(false, header)
+	setCurrentPrice(ctx, keeper, "xrp", 1)
+	setCurrentPrice(ctx, keeper, stableDenom, 2)
+
+	// check the debt is valued at the price of the stable coin: 400 xrp at a liquidation ratio of 2 back 200 of value, or 100 stable coins
+	require.Error(t, keeper.ModifyCDP(ctx, addrs[0], ftCollateral("xrp", 400), liq(stableDenom, 101)))
+	require.NoError(t, keeper.ModifyCDP(ctx, addrs[0], ftCollateral("xrp", 400), liq(stableDenom, 100)))
+
+	// check the collateral can't be withdrawn or debt drawn without a price for the stable coin, but the CDP can be made safer
+	delete(keeper.pricefeed.(mockPricefeed).current, stableDenom)
+	require.Error(t, keeper.ModifyCDP(ctx, addrs[0], ftCollateral("xrp", -10), liq(stableDenom, 0)))
+	require.Error(t, keeper.ModifyCDP(ctx, addrs[0], ftCollateral("xrp", 0), liq(stableDenom, 10)))
+	require.NoError(t, keeper.ModifyCDP(ctx, addrs[0], ftCollateral("xrp", 10), liq(stableDenom, -10)))
+}
+
+func TestKeeper_ModifyCDP_StalePrice(t *testing.T) {
+	// setup keeper and an owner with a CDP
+	mapp, keeper := setUpMockAppWithoutGenesis()
+	_, addrs := mock.GeneratePrivKeyAddressPairs(1)
+	genAcc := auth.BaseAccount{Address: addrs[0], Coins: cs(c("xrp", 1000))}
+	mock.SetGenesis(mapp, []auth.Account{&genAcc})
+	header := abci.Header{Height: mapp.LastBlockHeight() + 1}
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := mapp.BaseApp.NewContext(false, header)
+	setCurrentPrice(ctx, keeper, "xrp", 1)
+	require.NoError(t, keeper.ModifyCDP(ctx, addrs[0], ftCollateral("xrp", 400), liq(stableDenom, 100)))
+
+	// check the collateral can't be withdrawn or debt drawn on a stale collateral price, but the CDP can be made safer
+	stale := keeper.pricefeed.(mockPricefeed).stale
+	stale["xrp"] = true
+	require.Error(t, keeper.ModifyCDP(ctx, addrs[0], ftCollateral("xrp", -10), liq(stableDenom, 0)))
+	require.Error(t, keeper.ModifyCDP(ctx, addrs[0], ftCollateral("xrp", 0), liq(stableDenom, 10)))
+	require.NoError(t, keeper.ModifyCDP(ctx, addrs[0], ftCollateral("xrp", 10), liq(stableDenom, -10)))
+
+	// check the same on a stale stable coin price
+	delete(stale, "xrp")
+	stale[stableDenom] = true
+	require.Error(t, keeper.ModifyCDP(ctx, addrs[0], ftCollateral("xrp", -10), liq(stableDenom, 0)))
+	require.Error(t, keeper.ModifyCDP(ctx, addrs[0], ftCollateral("xrp", 0), liq(stableDenom, 10)))
+	require.NoError(t, keeper.ModifyCDP(ctx, addrs[0], ftCollateral("xrp", 10), liq(stableDenom, -10)))
+
+	// check the CDP can be changed again once the price is fresh
+	delete(stale, stableDenom)
+	require.NoError(t, keeper.ModifyCDP(ctx, addrs[0], ftCollateral("xrp", 0), liq(stableDenom, 10)))
+}
+
+func TestKeeper_ModifyCDPType(t *testing.T) {
+	// setup keeper and a CDP
+	mapp, keeper := setUpMockAppWithoutGenesis()
+	_, addrs := mock.GeneratePrivKeyAddressPairs(1)
+	genAcc := auth.BaseAccount{Address: addrs[0], Coins: cs(c("xrp", 4000000))}
+	mock.SetGenesis(mapp, []auth.Account{&genAcc})
+	header := abci.Header{Height: mapp.LastBlockHeight() + 1}
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := mapp.BaseApp.NewContext(false, header)
+	setCurrentPrice(ctx, keeper, "xrp", 1)
+	require.NoError(t, keeper.ModifyCDP(ctx, addrs[0], ftCollateral("xrp", 4000000), liq(stableDenom, 100000)))
+
+	// a year later the price drops so much the CDP is under the liquidation ratio, updating it only adds its fees
+	ctx = ctx.WithBlockHeight(ctx.BlockHeight() + BlocksPerYear)
+	setCurrentPrice(ctx, keeper, "xrp", 0)
+	keeper.ModifyCDPType(ctx, "xrp", "")
+	cdp, found := keeper.GetCDP(ctx, addrs[0], "xrp", "")
+	require.True(t, found)
+	require.Equal(t, i(105127), cdp.Liquidity.Coin.Amount)
+	require.Equal(t, i(5127), cdp.AccumulatedFees)
+	require.Equal(t, ctx.BlockHeight(), cdp.FeesUpdated)
+	collateralState, _ := keeper.GetCollateralState(ctx, "xrp")
+	require.Equal(t, i(105127), collateralState.TotalDebt)
+	require.Equal(t, i(105127), keeper.GetGlobalDebt(ctx))
+
+	// assets that aren't collateral are skipped
+	keeper.ModifyCDPType(ctx, stableDenom, "")
+	require.Equal(t, i(105127), keeper.GetGlobalDebt(ctx))
+}
+
+func TestKeeper_MigrateCDPKeys(t *testing.T) {
+	// setup keeper
+	mapp, keeper := setUpMockAppWithoutGenesis()
+	header := abci.Header{Height: mapp.LastBlockHeight() + 1}
+	mapp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := mapp.BaseApp.NewContext(false, header)
+	_, addrs := mock.GeneratePrivKeyAddressPairs(1)
+	store := ctx.KVStore(keeper.storeKey)
+
+	// write CDPs under the legacy keys, next to a collateral state that shares the legacy prefix
+	legacyCDPs := types.CDPs{
+		ftCDP(addrs[0], "xrp", 412, 56),
+		nftCDP(addrs[0], "art", "1", 10),
+	}
+	for _, cdp := range legacyCDPs {
+		store.Set(keeper.getLegacyCDPKey(cdp), keeper.cdc.MustMarshalBinaryLengthPrefixed(cdp))
+	}
+	collateralState := types.CollateralState{Denom: "cdpcoin", TotalDebt: i(7), AccumulatedFees: i(0), CollectedFees: i(0)}
+	keeper.setCollateralState(ctx, collateralState)
+
+	// migrate
+	keeper.MigrateCDPKeys(ctx)
+
+	// check the CDPs moved to the new keys
+	for _, cdp := range legacyCDPs {
+		require.Nil(t, store.Get(keeper.getLegacyCDPKey(cdp)))
+	}
+	readCDP, found := keeper.GetCDP(ctx, addrs[0], "xrp", "")
+	require.True(t, found)
+	require.Equal(t, legacyCDPs[0], readCDP)
+	readCDP, found = keeper.GetCDP(ctx, addrs[0], "art", "1")
+	require.True(t, found)
+	require.Equal(t, legacyCDPs[1], readCDP)
+	require.Equal(t, types.CDPs{legacyCDPs[1], legacyCDPs[0]}, keeper.GetOwnerCDPs(ctx, addrs[0]))
+	// check other entries are untouched
+	readCState, found := keeper.GetCollateralState(ctx, "cdpcoin")
+	require.True(t, found)
+	require.Equal(t, collateralState, readCState)
+
+	// check the migration only runs once
+	store.Set(keeper.getLegacyCDPKey(legacyCDPs[0]), keeper.cdc.MustMarshalBinaryLengthPrefixed(legacyCDPs[0]))
+	keeper.MigrateCDPKeys(ctx)
+	require.NotNil(t, store.Get(keeper.getLegacyCDPKey(legacyCDPs[0])))
+}
+
 func TestKeeper_GetSetGDebt(t *testing.T) {
 	// setup keeper, create GDebt
 	mapp, keeper := setUpMockAppWithoutGenesis()
@@ -309,7 +769,7 @@ func TestKeeper_GetSetCollateralState(t *testing.T) {
 	header := abci.Header{Height: mapp.LastBlockHeight() + 1}
 	mapp.BeginBlock(abci.RequestBeginBlock{Header: header})
 	ctx := mapp.BaseApp.NewContext(false, header)
-	collateralState := CollateralState{"xrp", i(15400)}
+	collateralState := types.CollateralState{Denom: "xrp", TotalDebt: i(15400), AccumulatedFees: i(20), CollectedFees: i(3)}
 
 	// write and read from store
 	keeper.setCollateralState(ctx, collateralState)
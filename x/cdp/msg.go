@@ -1,12 +1,16 @@
 package cdp
 
 import (
+	"fmt"
+
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/auction"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/pool"
 	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
-// MsgCreateOrModifyCDP creates, adds/removes collateral/stable coin from a cdp
-// TODO Make this more user friendly - maybe split into four functions.
+// MsgCreateOrModifyCDP creates, adds/removes collateral/stable coin from a cdp using signed amounts.
+// MsgDepositCollateral, MsgWithdrawCollateral, MsgDrawDebt and MsgRepayDebt do the same with positive amounts only.
 type MsgCreateOrModifyCDP struct {
 	Sender     sdk.AccAddress
 	Collateral types.Collateral
@@ -33,7 +37,13 @@ func (msg MsgCreateOrModifyCDP) ValidateBasic() sdk.Error {
 	if msg.Sender.Empty() {
 		return sdk.ErrInternal("invalid (empty) sender address")
 	}
-	return nil
+	if err := validateCollateralToken(msg.Collateral.Token); err != nil {
+		return err
+	}
+	if msg.Collateral.Amount == (sdk.Int{}) || msg.Liquidity.Coin.Amount == (sdk.Int{}) {
+		return sdk.ErrInternal("collateral and liquidity amounts must be set")
+	}
+	return validateLiquidityDenom(msg.Liquidity.Coin.Denom, msg.Collateral.Token)
 }
 
 // GetSignBytes gets the canonical byte representation of the Msg.
@@ -47,7 +57,317 @@ func (msg MsgCreateOrModifyCDP) GetSigners() []sdk.AccAddress {
 	return []sdk.AccAddress{msg.Sender}
 }
 
+// validateCollateralToken checks the token identifying the collateral type of a cdp.
+func validateCollateralToken(token types.Token) sdk.Error {
+	if token == nil || token.GetName() == "" {
+		return sdk.ErrInternal("invalid (empty) collateral token")
+	}
+	if nft, ok := token.(BaseNFT); ok && nft.GetID() == "" {
+		return sdk.ErrInternal("invalid (empty) collateral token id")
+	}
+	return nil
+}
+
+// validateLiquidityDenom checks the denom of the stable coin drawn against a collateral.
+// Which coin is the stable one is a module param, checked by the keeper, but it's never the gov coin or the collateral itself.
+func validateLiquidityDenom(denom string, collateralToken types.Token) sdk.Error {
+	if !(sdk.Coins{{Denom: denom, Amount: sdk.OneInt()}}).IsValid() {
+		return sdk.ErrInvalidCoins("invalid liquidity denom")
+	}
+	if denom == GovDenom || denom == collateralToken.GetName() {
+		return sdk.ErrInvalidCoins("liquidity must be in the stable coin, not " + denom)
+	}
+	return nil
+}
+
+// MsgDepositCollateral adds collateral to a cdp, creating it if it doesn't exist.
+type MsgDepositCollateral struct {
+	Sender         sdk.AccAddress
+	Collateral     types.Collateral
+	LiquidityDenom string // stable coin drawn against the collateral
+}
+
+// NewMsgDepositCollateral returns a new MsgDepositCollateral.
+func NewMsgDepositCollateral(sender sdk.AccAddress, collateral types.Collateral, liquidityDenom string) MsgDepositCollateral {
+	return MsgDepositCollateral{
+		Sender:         sender,
+		Collateral:     collateral,
+		LiquidityDenom: liquidityDenom,
+	}
+}
+
+// Route return the message type used for routing the message.
+func (msg MsgDepositCollateral) Route() string { return "cdp" }
+
+// Type returns a human-readable string for the message, intended for utilization within tags.
+func (msg MsgDepositCollateral) Type() string { return "deposit_collateral" }
+
+// ValidateBasic does a simple validation check that doesn't require access to any other information.
+func (msg MsgDepositCollateral) ValidateBasic() sdk.Error {
+	if msg.Sender.Empty() {
+		return sdk.ErrInternal("invalid (empty) sender address")
+	}
+	if err := validateCollateralToken(msg.Collateral.Token); err != nil {
+		return err
+	}
+	if msg.Collateral.Amount == (sdk.Int{}) || !msg.Collateral.Amount.IsPositive() {
+		return sdk.ErrInternal("collateral amount must be positive")
+	}
+	return validateLiquidityDenom(msg.LiquidityDenom, msg.Collateral.Token)
+}
+
+// GetSignBytes gets the canonical byte representation of the Msg.
+func (msg MsgDepositCollateral) GetSignBytes() []byte {
+	bz := moduleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners returns the addresses of signers that must sign.
+func (msg MsgDepositCollateral) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// MsgWithdrawCollateral removes collateral from a cdp.
+type MsgWithdrawCollateral struct {
+	Sender         sdk.AccAddress
+	Collateral     types.Collateral
+	LiquidityDenom string // stable coin drawn against the collateral
+}
+
+// NewMsgWithdrawCollateral returns a new MsgWithdrawCollateral.
+func NewMsgWithdrawCollateral(sender sdk.AccAddress, collateral types.Collateral, liquidityDenom string) MsgWithdrawCollateral {
+	return MsgWithdrawCollateral{
+		Sender:         sender,
+		Collateral:     collateral,
+		LiquidityDenom: liquidityDenom,
+	}
+}
+
+// Route return the message type used for routing the message.
+func (msg MsgWithdrawCollateral) Route() string { return "cdp" }
+
+// Type returns a human-readable string for the message, intended for utilization within tags.
+func (msg MsgWithdrawCollateral) Type() string { return "withdraw_collateral" }
+
+// ValidateBasic does a simple validation check that doesn't require access to any other information.
+func (msg MsgWithdrawCollateral) ValidateBasic() sdk.Error {
+	if msg.Sender.Empty() {
+		return sdk.ErrInternal("invalid (empty) sender address")
+	}
+	if err := validateCollateralToken(msg.Collateral.Token); err != nil {
+		return err
+	}
+	if msg.Collateral.Amount == (sdk.Int{}) || !msg.Collateral.Amount.IsPositive() {
+		return sdk.ErrInternal("collateral amount must be positive")
+	}
+	return validateLiquidityDenom(msg.LiquidityDenom, msg.Collateral.Token)
+}
+
+// GetSignBytes gets the canonical byte representation of the Msg.
+func (msg MsgWithdrawCollateral) GetSignBytes() []byte {
+	bz := moduleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners returns the addresses of signers that must sign.
+func (msg MsgWithdrawCollateral) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// MsgDrawDebt draws stable coin from a cdp, increasing its debt.
+type MsgDrawDebt struct {
+	Sender          sdk.AccAddress
+	CollateralToken types.Token // collateral type of the cdp
+	Liquidity       sdk.Coin
+}
+
+// NewMsgDrawDebt returns a new MsgDrawDebt.
+func NewMsgDrawDebt(sender sdk.AccAddress, collateralToken types.Token, liquidity sdk.Coin) MsgDrawDebt {
+	return MsgDrawDebt{
+		Sender:          sender,
+		CollateralToken: collateralToken,
+		Liquidity:       liquidity,
+	}
+}
+
+// Route return the message type used for routing the message.
+func (msg MsgDrawDebt) Route() string { return "cdp" }
+
+// Type returns a human-readable string for the message, intended for utilization within tags.
+func (msg MsgDrawDebt) Type() string { return "draw_debt" }
+
+// ValidateBasic does a simple validation check that doesn't require access to any other information.
+func (msg MsgDrawDebt) ValidateBasic() sdk.Error {
+	if msg.Sender.Empty() {
+		return sdk.ErrInternal("invalid (empty) sender address")
+	}
+	if err := validateCollateralToken(msg.CollateralToken); err != nil {
+		return err
+	}
+	if msg.Liquidity.Amount == (sdk.Int{}) || !(sdk.Coins{msg.Liquidity}).IsValid() {
+		return sdk.ErrInvalidCoins("liquidity amount must be positive")
+	}
+	return validateLiquidityDenom(msg.Liquidity.Denom, msg.CollateralToken)
+}
+
+// GetSignBytes gets the canonical byte representation of the Msg.
+func (msg MsgDrawDebt) GetSignBytes() []byte {
+	bz := moduleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners returns the addresses of signers that must sign.
+func (msg MsgDrawDebt) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// MsgRepayDebt pays stable coin back to a cdp, reducing its debt.
+type MsgRepayDebt struct {
+	Sender          sdk.AccAddress
+	CollateralToken types.Token // collateral type of the cdp
+	Liquidity       sdk.Coin
+}
+
+// NewMsgRepayDebt returns a new MsgRepayDebt.
+func NewMsgRepayDebt(sender sdk.AccAddress, collateralToken types.Token, liquidity sdk.Coin) MsgRepayDebt {
+	return MsgRepayDebt{
+		Sender:          sender,
+		CollateralToken: collateralToken,
+		Liquidity:       liquidity,
+	}
+}
+
+// Route return the message type used for routing the message.
+func (msg MsgRepayDebt) Route() string { return "cdp" }
+
+// Type returns a human-readable string for the message, intended for utilization within tags.
+func (msg MsgRepayDebt) Type() string { return "repay_debt" }
+
+// ValidateBasic does a simple validation check that doesn't require access to any other information.
+func (msg MsgRepayDebt) ValidateBasic() sdk.Error {
+	if msg.Sender.Empty() {
+		return sdk.ErrInternal("invalid (empty) sender address")
+	}
+	if err := validateCollateralToken(msg.CollateralToken); err != nil {
+		return err
+	}
+	if msg.Liquidity.Amount == (sdk.Int{}) || !(sdk.Coins{msg.Liquidity}).IsValid() {
+		return sdk.ErrInvalidCoins("liquidity amount must be positive")
+	}
+	return validateLiquidityDenom(msg.Liquidity.Denom, msg.CollateralToken)
+}
+
+// GetSignBytes gets the canonical byte representation of the Msg.
+func (msg MsgRepayDebt) GetSignBytes() []byte {
+	bz := moduleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners returns the addresses of signers that must sign.
+func (msg MsgRepayDebt) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// NewCollateralToken returns the token identifying a collateral type, a non empty id makes it a NFT.
+func NewCollateralToken(name string, nftID string) types.Token {
+	if nftID != "" {
+		return BaseNFT{Name: name, ID: nftID}
+	}
+	return BaseFT{TokenName: name}
+}
+
 // MsgTransferCDP changes the ownership of a cdp
 type MsgTransferCDP struct {
-	// TODO
+	Sender          sdk.AccAddress
+	Receiver        sdk.AccAddress
+	CollateralToken types.Token // collateral type of the cdp
+}
+
+// NewMsgTransferCDP returns a new MsgTransferCDP.
+func NewMsgTransferCDP(sender sdk.AccAddress, receiver sdk.AccAddress, collateralToken types.Token) MsgTransferCDP {
+	return MsgTransferCDP{
+		Sender:          sender,
+		Receiver:        receiver,
+		CollateralToken: collateralToken,
+	}
+}
+
+// Route return the message type used for routing the message.
+func (msg MsgTransferCDP) Route() string { return "cdp" }
+
+// Type returns a human-readable string for the message, intended for utilization within tags.
+func (msg MsgTransferCDP) Type() string { return "transfer_cdp" }
+
+// ValidateBasic does a simple validation check that doesn't require access to any other information.
+func (msg MsgTransferCDP) ValidateBasic() sdk.Error {
+	if msg.Sender.Empty() {
+		return sdk.ErrInternal("invalid (empty) sender address")
+	}
+	if msg.Receiver.Empty() {
+		return sdk.ErrInternal("invalid (empty) receiver address")
+	}
+	if msg.Sender.Equals(msg.Receiver) {
+		return sdk.ErrInternal("sender and receiver must differ")
+	}
+	for _, addr := range moduleAddresses {
+		if msg.Receiver.Equals(addr) {
+			return sdk.ErrUnauthorized(fmt.Sprintf("%s is a module account and can't receive CDPs", msg.Receiver))
+		}
+	}
+	return validateCollateralToken(msg.CollateralToken)
+}
+
+// moduleAddresses are the addresses of the module accounts, no one could manage a CDP transferred to them
+var moduleAddresses = []sdk.AccAddress{pool.ModuleAddress, LiquidatorAccountAddress, auction.ModuleAddress}
+
+// GetSignBytes gets the canonical byte representation of the Msg.
+func (msg MsgTransferCDP) GetSignBytes() []byte {
+	bz := moduleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners returns the addresses of signers that must sign.
+func (msg MsgTransferCDP) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+
+// MsgCloseCDP repays all the debt of a cdp and returns all its collateral, deleting it.
+type MsgCloseCDP struct {
+	Owner          sdk.AccAddress
+	CollateralName string
+	NftID          string // only set for cdps backed by NFT collateral
+}
+
+// NewMsgCloseCDP returns a new MsgCloseCDP.
+func NewMsgCloseCDP(owner sdk.AccAddress, collateralName string, nftID string) MsgCloseCDP {
+	return MsgCloseCDP{
+		Owner:          owner,
+		CollateralName: collateralName,
+		NftID:          nftID,
+	}
+}
+
+// Route return the message type used for routing the message.
+func (msg MsgCloseCDP) Route() string { return "cdp" }
+
+// Type returns a human-readable string for the message, intended for utilization within tags.
+func (msg MsgCloseCDP) Type() string { return "close_cdp" }
+
+// ValidateBasic does a simple validation check that doesn't require access to any other information.
+func (msg MsgCloseCDP) ValidateBasic() sdk.Error {
+	if msg.Owner.Empty() {
+		return sdk.ErrInternal("invalid (empty) owner address")
+	}
+	return validateCollateralToken(NewCollateralToken(msg.CollateralName, msg.NftID))
+}
+
+// GetSignBytes gets the canonical byte representation of the Msg.
+func (msg MsgCloseCDP) GetSignBytes() []byte {
+	bz := moduleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners returns the addresses of signers that must sign.
+func (msg MsgCloseCDP) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Owner}
 }
@@ -0,0 +1,166 @@
+package cdp
+
+import (
+	"testing"
+
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/auction"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/pool"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMsgDepositWithdrawCollateral_ValidateBasic(t *testing.T) {
+	addr := sdk.AccAddress([]byte("someName"))
+	ft := BaseFT{TokenName: "xrp"}
+	nft := BaseNFT{Name: "art", ID: "1"}
+	tests := []struct {
+		name           string
+		sender         sdk.AccAddress
+		collateral     types.Collateral
+		liquidityDenom string
+		expectPass     bool
+	}{
+		{"normal", addr, types.Collateral{Token: ft, Amount: i(10)}, "uatom", true},
+		{"nft", addr, types.Collateral{Token: nft, Amount: i(1)}, "uatom", true},
+		{"emptyAddr", sdk.AccAddress{}, types.Collateral{Token: ft, Amount: i(10)}, "uatom", false},
+		{"noToken", addr, types.Collateral{Amount: i(10)}, "uatom", false},
+		{"nftWithoutID", addr, types.Collateral{Token: BaseNFT{Name: "art"}, Amount: i(1)}, "uatom", false},
+		{"zeroAmount", addr, types.Collateral{Token: ft, Amount: i(0)}, "uatom", false},
+		{"negativeAmount", addr, types.Collateral{Token: ft, Amount: i(-10)}, "uatom", false},
+		{"noAmount", addr, types.Collateral{Token: ft}, "uatom", false},
+		{"invalidDenom", addr, types.Collateral{Token: ft, Amount: i(10)}, "", false},
+		{"govDenom", addr, types.Collateral{Token: ft, Amount: i(10)}, GovDenom, false},
+		{"collateralDenom", addr, types.Collateral{Token: ft, Amount: i(10)}, "xrp", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			deposit := NewMsgDepositCollateral(tc.sender, tc.collateral, tc.liquidityDenom)
+			withdraw := NewMsgWithdrawCollateral(tc.sender, tc.collateral, tc.liquidityDenom)
+			if tc.expectPass {
+				require.Nil(t, deposit.ValidateBasic())
+				require.Nil(t, withdraw.ValidateBasic())
+			} else {
+				require.NotNil(t, deposit.ValidateBasic())
+				require.NotNil(t, withdraw.ValidateBasic())
+			}
+		})
+	}
+}
+
+func TestMsgDrawRepayDebt_ValidateBasic(t *testing.T) {
+	addr := sdk.AccAddress([]byte("someName"))
+	ft := BaseFT{TokenName: "xrp"}
+	tests := []struct {
+		name       string
+		sender     sdk.AccAddress
+		token      types.Token
+		liquidity  sdk.Coin
+		expectPass bool
+	}{
+		{"normal", addr, ft, c("uatom", 10), true},
+		{"emptyAddr", sdk.AccAddress{}, ft, c("uatom", 10), false},
+		{"noToken", addr, nil, c("uatom", 10), false},
+		{"zeroAmount", addr, ft, c("uatom", 0), false},
+		{"negativeAmount", addr, ft, sdk.Coin{Denom: "uatom", Amount: i(-10)}, false},
+		{"invalidDenom", addr, ft, sdk.Coin{Denom: "", Amount: i(10)}, false},
+		{"govDenom", addr, ft, c(GovDenom, 10), false},
+		{"collateralDenom", addr, ft, c("xrp", 10), false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			draw := NewMsgDrawDebt(tc.sender, tc.token, tc.liquidity)
+			repay := NewMsgRepayDebt(tc.sender, tc.token, tc.liquidity)
+			if tc.expectPass {
+				require.Nil(t, draw.ValidateBasic())
+				require.Nil(t, repay.ValidateBasic())
+			} else {
+				require.NotNil(t, draw.ValidateBasic())
+				require.NotNil(t, repay.ValidateBasic())
+			}
+		})
+	}
+}
+
+func TestMsgCreateOrModifyCDP_ValidateBasic(t *testing.T) {
+	addr := sdk.AccAddress([]byte("someName"))
+	ft := BaseFT{TokenName: "xrp"}
+	tests := []struct {
+		name       string
+		sender     sdk.AccAddress
+		collateral types.Collateral
+		liquidity  types.Liquidity
+		expectPass bool
+	}{
+		{"normal", addr, types.Collateral{Token: ft, Amount: i(10)}, liq("uatom", 5), true},
+		{"negativeAmounts", addr, types.Collateral{Token: ft, Amount: i(-10)}, liq("uatom", -5), true},
+		{"emptyAddr", sdk.AccAddress{}, types.Collateral{Token: ft, Amount: i(10)}, liq("uatom", 5), false},
+		{"noToken", addr, types.Collateral{Amount: i(10)}, liq("uatom", 5), false},
+		{"noAmount", addr, types.Collateral{Token: ft}, liq("uatom", 5), false},
+		{"invalidDenom", addr, types.Collateral{Token: ft, Amount: i(10)}, liq("", 5), false},
+		{"govDenom", addr, types.Collateral{Token: ft, Amount: i(10)}, liq(GovDenom, 5), false},
+		{"collateralDenom", addr, types.Collateral{Token: ft, Amount: i(10)}, liq("xrp", 5), false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := NewMsgCreateOrModifyCDP(tc.sender, tc.collateral, tc.liquidity)
+			if tc.expectPass {
+				require.Nil(t, msg.ValidateBasic())
+			} else {
+				require.NotNil(t, msg.ValidateBasic())
+			}
+		})
+	}
+}
+
+func TestMsgTransferCDP_ValidateBasic(t *testing.T) {
+	from := sdk.AccAddress([]byte("someName"))
+	to := sdk.AccAddress([]byte("otherName"))
+	tests := []struct {
+		name       string
+		msg        MsgTransferCDP
+		expectPass bool
+	}{
+		{"ft", NewMsgTransferCDP(from, to, NewCollateralToken("xrp", "")), true},
+		{"nft", NewMsgTransferCDP(from, to, NewCollateralToken("art", "1")), true},
+		{"emptySender", NewMsgTransferCDP(sdk.AccAddress{}, to, NewCollateralToken("xrp", "")), false},
+		{"emptyReceiver", NewMsgTransferCDP(from, sdk.AccAddress{}, NewCollateralToken("xrp", "")), false},
+		{"sameOwner", NewMsgTransferCDP(from, from, NewCollateralToken("xrp", "")), false},
+		{"noToken", NewMsgTransferCDP(from, to, nil), false},
+		{"toPool", NewMsgTransferCDP(from, pool.ModuleAddress, NewCollateralToken("xrp", "")), false},
+		{"toLiquidator", NewMsgTransferCDP(from, LiquidatorAccountAddress, NewCollateralToken("xrp", "")), false},
+		{"toAuction", NewMsgTransferCDP(from, auction.ModuleAddress, NewCollateralToken("xrp", "")), false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.expectPass {
+				require.Nil(t, tc.msg.ValidateBasic())
+			} else {
+				require.NotNil(t, tc.msg.ValidateBasic())
+			}
+		})
+	}
+}
+
+func TestMsgCloseCDP_ValidateBasic(t *testing.T) {
+	addr := sdk.AccAddress([]byte("someName"))
+	tests := []struct {
+		name       string
+		msg        MsgCloseCDP
+		expectPass bool
+	}{
+		{"ft", NewMsgCloseCDP(addr, "xrp", ""), true},
+		{"nft", NewMsgCloseCDP(addr, "art", "1"), true},
+		{"emptyOwner", NewMsgCloseCDP(sdk.AccAddress{}, "xrp", ""), false},
+		{"emptyCollateral", NewMsgCloseCDP(addr, "", ""), false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.expectPass {
+				require.Nil(t, tc.msg.ValidateBasic())
+			} else {
+				require.NotNil(t, tc.msg.ValidateBasic())
+			}
+		})
+	}
+}
@@ -10,8 +10,9 @@ import (
 )
 
 const (
-	QueryGetCdps   = "cdps"
-	QueryGetParams = "params"
+	QueryGetCdps      = "cdps"
+	QueryGetOwnerCdps = "owner-cdps"
+	QueryGetParams    = "params"
 )
 
 func NewQuerier(keeper Keeper) sdk.Querier {
@@ -19,6 +20,8 @@ func NewQuerier(keeper Keeper) sdk.Querier {
 		switch path[0] {
 		case QueryGetCdps:
 			return queryGetCdps(ctx, req, keeper)
+		case QueryGetOwnerCdps:
+			return queryGetOwnerCdps(ctx, req, keeper)
 		case QueryGetParams:
 			return queryGetParams(ctx, req, keeper)
 		default:
@@ -61,12 +64,12 @@ func queryGetCdps(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte
 			cdps = types.CDPs{cdp}
 		} else {
 			// owner, but no collateral specified - get all CDPs for one address
-			return nil, sdk.ErrInternal("getting all CDPs belonging to one owner not implemented")
+			cdps = keeper.GetOwnerCDPs(ctx, requestParams.Owner)
 		}
 	} else {
-		// owner not specified -- get all CDPs or all CDPs of one collateral type, optionally filtered by price
+		// owner not specified -- get all CDPs, all CDPs of one collateral type or all CDPs backed by one NFT, optionally filtered by price
 		var errSdk sdk.Error // := doesn't work here
-		cdps, errSdk = keeper.GetCDPs(ctx, requestParams.CollateralName, requestParams.UnderCollateralizedAt)
+		cdps, errSdk = keeper.GetCDPs(ctx, requestParams.CollateralName, requestParams.NftID, requestParams.UnderCollateralizedAt)
 		if errSdk != nil {
 			return nil, errSdk
 		}
@@ -81,6 +84,35 @@ func queryGetCdps(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte
 	return bz, nil
 }
 
+type QueryOwnerCdpsParams struct {
+	Owner sdk.AccAddress // get all the CDPs belonging to this owner
+}
+
+// queryGetOwnerCdps fetches all the FT and NFT backed CDPs of an owner.
+func queryGetOwnerCdps(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	// Decode request
+	var requestParams QueryOwnerCdpsParams
+	err := keeper.cdc.UnmarshalJSON(req.Data, &requestParams)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+	if requestParams.Owner.Empty() {
+		return nil, sdk.ErrInvalidAddress("owner address cannot be empty")
+	}
+
+	cdps := keeper.GetOwnerCDPs(ctx, requestParams.Owner)
+	if cdps == nil {
+		cdps = types.CDPs{} // return an empty list rather than null
+	}
+
+	// Encode results
+	bz, err := codec.MarshalJSONIndent(keeper.cdc, cdps)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+	return bz, nil
+}
+
 // queryGetParams fetches the cdp module parameters
 // TODO does this need to exist? Can you use cliCtx.QueryStore instead?
 func queryGetParams(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
@@ -1,7 +1,9 @@
 package cdp
 
 import (
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/pool"
 	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/pricefeed"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/bank"
 	"github.com/cosmos/cosmos-sdk/x/mock"
@@ -28,9 +30,9 @@ func setUpMockAppWithoutGenesis() (*mock.App, Keeper) {
 	keyCDP := sdk.NewKVStoreKey("cdp")
 	keyPriceFeed := sdk.NewKVStoreKey(pricefeed.StoreKey)
 
-	priceFeedKeeper := pricefeed.NewKeeper(keyPriceFeed, mapp.Cdc, pricefeed.DefaultCodespace, nil)
+	priceFeedKeeper := newMockPricefeed()
 	bankKeeper := bank.NewBaseKeeper(mapp.AccountKeeper, mapp.ParamsKeeper.Subspace(bank.DefaultParamspace), bank.DefaultCodespace)
-	cdpKeeper := NewKeeper(mapp.Cdc, keyCDP, mapp.ParamsKeeper.Subspace("cdpSubspace"), priceFeedKeeper, bankKeeper)
+	cdpKeeper := NewKeeper(mapp.Cdc, keyCDP, mapp.ParamsKeeper.Subspace("cdpSubspace"), priceFeedKeeper, bankKeeper, newMockPool(bankKeeper))
 
 	// Register routes
 	mapp.Router().AddRoute("cdp", NewHandler(cdpKeeper))
@@ -52,8 +54,177 @@ func setUpMockAppWithoutGenesis() (*mock.App, Keeper) {
 	return mapp, cdpKeeper
 }
 
+// setUpMockAppWithPool is setUpMockAppWithoutGenesis with the real pool and pricefeed keepers in place of the mocks.
+// The pricefeed starts with the stable coin and xrp listed, tests post their prices.
+func setUpMockAppWithPool() (*mock.App, Keeper, pool.Keeper, pricefeed.Keeper) {
+	// Create uninitialized mock app
+	mapp := mock.NewApp()
+
+	// Register codecs
+	RegisterCodec(mapp.Cdc)
+
+	// Create keepers
+	keyCDP := sdk.NewKVStoreKey("cdp")
+	keyPriceFeed := sdk.NewKVStoreKey(pricefeed.StoreKey)
+	keyPool := sdk.NewKVStoreKey("pool")
+
+	var cdpKeeper Keeper
+	bankKeeper := bank.NewBaseKeeper(mapp.AccountKeeper, mapp.ParamsKeeper.Subspace(bank.DefaultParamspace), bank.DefaultCodespace)
+	priceFeedKeeper := pricefeed.NewKeeper(keyPriceFeed, mapp.Cdc, mapp.ParamsKeeper.Subspace("pricefeedSubspace"), pricefeed.DefaultCodespace, &cdpKeeper)
+	poolKeeper := pool.NewKeeper(keyPool, bankKeeper, mapp.Cdc)
+	cdpKeeper = NewKeeper(mapp.Cdc, keyCDP, mapp.ParamsKeeper.Subspace("cdpSubspace"), priceFeedKeeper, bankKeeper, poolKeeper)
+
+	// Register routes
+	mapp.Router().AddRoute("cdp", NewHandler(cdpKeeper))
+
+	mapp.SetInitChainer(
+		func(ctx sdk.Context, req abci.RequestInitChain) abci.ResponseInitChain {
+			res := mapp.InitChainer(ctx, req)
+			pricefeed.InitGenesis(ctx, priceFeedKeeper, pricefeed.GenesisState{
+				Params: pricefeed.DefaultGenesisState().Params,
+				Assets: []pricefeed.Asset{
+					{Type: "ft", AssetName: stableDenom, Description: "the stable coin"},
+					{Type: "ft", AssetName: "xrp", Description: "a collateral"},
+				},
+			})
+			InitGenesis(ctx, cdpKeeper, DefaultGenesisState())
+			return res
+		},
+	)
+
+	// Mount and load the stores
+	err := mapp.CompleteSetup(keyPriceFeed, keyCDP, keyPool)
+	if err != nil {
+		panic("mock app setup failed")
+	}
+
+	return mapp, cdpKeeper, poolKeeper, priceFeedKeeper
+}
+
+// mockPricefeed stands in for the pricefeed keeper.
+// Prices posted by oracles become current when SetCurrentPrices is called, as in the pricefeed module.
+// It starts with the stable coin at a price of 1 so tests only need to price it when checking the debt is valued at its price.
+type mockPricefeed struct {
+	posted  map[string]sdk.Int
+	current map[string]sdk.Int
+	asked   map[string]bool
+	stale   map[string]bool
+}
+
+var _ types.PricefeedKeeper = mockPricefeed{}
+
+func newMockPricefeed() mockPricefeed {
+	return mockPricefeed{map[string]sdk.Int{}, map[string]sdk.Int{stableDenom: i(1)}, map[string]bool{}, map[string]bool{}}
+}
+
+func (pf mockPricefeed) GetCurrentPrice(_ sdk.Context, assetCode string, assetName string) types.CurrentPrice {
+	price, found := pf.current[assetName+assetCode]
+	if !found {
+		price = sdk.ZeroInt()
+	}
+	return types.CurrentPrice{AssetName: assetName, AssetCode: assetCode, Price: price, Expiry: i(9999999), Stale: pf.stale[assetName+assetCode]}
+}
+func (pf mockPricefeed) AddAsset(sdk.Context, string, string) {}
+func (pf mockPricefeed) SetPrice(_ sdk.Context, oracle sdk.AccAddress, assetCode string, assetName string, price sdk.Int, expiry sdk.Int) (types.PostedPrice, sdk.Error) {
+	pf.posted[assetName+assetCode] = price
+	return types.PostedPrice{AssetName: assetName, AssetCode: assetCode, OracleAddress: oracle.String(), Price: price, Expiry: expiry}, nil
+}
+func (pf mockPricefeed) SetCurrentPrices(sdk.Context) (sdk.Tags, sdk.Error) {
+	for asset, price := range pf.posted {
+		pf.current[asset] = price
+	}
+	return sdk.EmptyTags(), nil
+}
+func (pf mockPricefeed) AskForPrice(_ sdk.Context, assetCode string, assetName string) {
+	pf.asked[assetName+assetCode] = true
+}
+
+// mockPool stands in for the pool keeper, lending out the stable coin it's funded with.
+// It starts with plenty of liquidity so tests only need to fund it when checking it runs out.
+type mockPool struct {
+	bank      bankKeeper
+	available map[string]sdk.Int
+}
+
+func newMockPool(bank bankKeeper) mockPool {
+	return mockPool{bank, map[string]sdk.Int{stableDenom: i(1000000000)}}
+}
+
+func (mp mockPool) GetDrawableLiquidity(_ sdk.Context, denom string) (sdk.Int, sdk.Error) {
+	available, found := mp.available[denom]
+	if !found {
+		return sdk.ZeroInt(), nil
+	}
+	return available, nil
+}
+func (mp mockPool) DrawLiquidity(ctx sdk.Context, borrower sdk.AccAddress, amount sdk.Coin) sdk.Error {
+	available, _ := mp.GetDrawableLiquidity(ctx, amount.Denom)
+	if available.LT(amount.Amount) {
+		return sdk.ErrInsufficientCoins("not enough liquidity in the pool")
+	}
+	_, err := mp.bank.AddCoins(ctx, borrower, sdk.NewCoins(amount))
+	if err != nil {
+		return err
+	}
+	mp.available[amount.Denom] = available.Sub(amount.Amount)
+	return nil
+}
+func (mp mockPool) RepayLiquidity(ctx sdk.Context, borrower sdk.AccAddress, amount sdk.Coin) sdk.Error {
+	_, err := mp.bank.SubtractCoins(ctx, borrower, sdk.NewCoins(amount))
+	if err != nil {
+		return err
+	}
+	available, _ := mp.GetDrawableLiquidity(ctx, amount.Denom)
+	mp.available[amount.Denom] = available.Add(amount.Amount)
+	return nil
+}
+
+func (mp mockPool) PayFees(ctx sdk.Context, payer sdk.AccAddress, fees sdk.Coin) sdk.Error {
+	_, err := mp.bank.SubtractCoins(ctx, payer, sdk.NewCoins(fees))
+	if err != nil {
+		return err
+	}
+	available, _ := mp.GetDrawableLiquidity(ctx, fees.Denom)
+	mp.available[fees.Denom] = available.Add(fees.Amount)
+	return nil
+}
+
+// setCurrentPrice posts a price and makes it current
+func setCurrentPrice(ctx sdk.Context, keeper Keeper, assetName string, price int64) {
+	keeper.pricefeed.SetPrice(ctx, sdk.AccAddress{}, "", assetName, i(price), i(9999999))
+	keeper.pricefeed.SetCurrentPrices(ctx)
+}
+
+// stableDenom is the denom of the liquidity given out by the test CDPs
+const stableDenom = "uatom"
+
 // Avoid cluttering test cases with long function name
 func i(in int64) sdk.Int                    { return sdk.NewInt(in) }
 func d(str string) sdk.Dec                  { return sdk.MustNewDecFromStr(str) }
 func c(denom string, amount int64) sdk.Coin { return sdk.NewInt64Coin(denom, amount) }
 func cs(coins ...sdk.Coin) sdk.Coins        { return sdk.NewCoins(coins...) }
+
+func ftCDP(owner sdk.AccAddress, denom string, collateral int64, debt int64) types.CDP {
+	return types.CDP{
+		Owner:      owner,
+		Collateral: types.Collateral{Token: BaseFT{TokenName: denom}, Amount: i(collateral), InitialPrice: i(0)},
+		Liquidity:  types.Liquidity{Coin: c(stableDenom, debt), InitialPrice: i(0)},
+
+		AccumulatedFees: i(0),
+	}
+}
+func ftCollateral(denom string, amount int64) types.Collateral {
+	return types.Collateral{Token: BaseFT{TokenName: denom}, Amount: i(amount), InitialPrice: i(0)}
+}
+func liq(denom string, amount int64) types.Liquidity {
+	return types.Liquidity{Coin: sdk.Coin{Denom: denom, Amount: i(amount)}, InitialPrice: i(0)}
+}
+func nftCDP(owner sdk.AccAddress, denom string, id string, debt int64) types.CDP {
+	return types.CDP{
+		Owner:      owner,
+		Collateral: types.Collateral{Token: NewBaseNFT(id, owner, denom, "", "", ""), Amount: i(1), InitialPrice: i(0)},
+		Liquidity:  types.Liquidity{Coin: c(stableDenom, debt), InitialPrice: i(0)},
+
+		AccumulatedFees: i(0),
+	}
+}
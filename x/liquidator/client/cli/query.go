@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/liquidator"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
 )
 
 // GetCmd_GetOutstandingDebt queries for the remaining available debt in the liquidator module after settlement with the module's stablecoin balance.
@@ -31,3 +32,24 @@ func GetCmd_GetOutstandingDebt(queryRoute string, cdc *codec.Codec) *cobra.Comma
 		},
 	}
 }
+
+// GetCmd_GetModuleAccount queries for the address and balance of the liquidator module account.
+func GetCmd_GetModuleAccount(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "module-account",
+		Short: "get the liquidator module account",
+		Long:  "Get the address of the liquidator module account and its balance of seized collateral and stable coins.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			res, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", queryRoute, liquidator.QueryGetModuleAccount), nil)
+			if err != nil {
+				return err
+			}
+			var account types.ModuleAccount
+			cdc.MustUnmarshalJSON(res, &account)
+			return cliCtx.PrintOutput(account)
+		},
+	}
+}
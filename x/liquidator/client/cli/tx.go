@@ -1,13 +1,17 @@
 package cli
 
 import (
+	"io/ioutil"
+
 	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/cdp"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/pricefeed"
 	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
 	"github.com/cosmos/cosmos-sdk/client/context"
 	"github.com/cosmos/cosmos-sdk/client/utils"
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	authtxb "github.com/cosmos/cosmos-sdk/x/auth/client/txbuilder"
+	"github.com/cosmos/cosmos-sdk/x/gov"
 	"github.com/spf13/cobra"
 
 	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/liquidator"
@@ -20,7 +24,7 @@ func GetCmd_SeizeAndStartCollateralAuction(cdc *codec.Codec) *cobra.Command {
 		Long: `Seize a fixed amount of collateral and liquidity from a CDP then start an auction with the collateral.
 The amount of collateral seized is given by the 'AuctionSize' module parameter or, if there isn't enough collateral in the CDP, all the CDP's collateral is seized.
 Debt is seized in proportion to the collateral seized so that the CDP stays at the same collateral to debt ratio.
-A 'forward-reverse' auction is started selling the seized collateral for some stable coin, with a maximum bid of stable coin set to equal the debt seized.
+A 'forward-reverse' auction is started selling the seized collateral for some stable coin, with a maximum bid of stable coin set to the debt seized plus the 'LiquidationPenalty' module parameter (the penalty becomes surplus).
 As this is a forward-reverse auction type, if the max stable coin is bid then bidding continues by bidding down the amount of collateral taken by the bidder. At the end, extra collateral is returned to the original CDP owner.`,
 		Args: cobra.ExactArgs(4),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -39,7 +43,7 @@ As this is a forward-reverse auction type, if the max stable coin is bid then bi
 			name := args[1]
 			// TODO validate denom?
 			id := args[2]
-			amount, _ := sdk.NewIntFromString(args[4])
+			amount, _ := sdk.NewIntFromString(args[3])
 
 			//composing collateral
 			var collateral types.Collateral
@@ -96,3 +100,79 @@ func GetCmd_StartDebtAuction(cdc *codec.Codec) *cobra.Command {
 	}
 	return cmd
 }
+
+func GetCmd_StartSurplusAuction(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "burn",
+		Short: "start a surplus auction, burning the gov coin raised",
+		Long:  "Start a forward auction, selling off a fixed amount of surplus stable coin for gov coin, which is burned once the auction closes.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Setup
+			txBldr := authtxb.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			sender := cliCtx.GetFromAddress()
+
+			// Prepare and send message
+			msgs := []sdk.Msg{liquidator.MsgStartSurplusAuction{
+				Sender: sender,
+			}}
+			// TODO print out results like auction ID?
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, msgs)
+		},
+	}
+	return cmd
+}
+
+// collateralProposalJSON is the content of the file describing an add collateral proposal
+type collateralProposalJSON struct {
+	Title            string                      `json:"title"`
+	Description      string                      `json:"description"`
+	Asset            pricefeed.Asset             `json:"asset"`
+	CdpParams        types.CollateralParams      `json:"cdp_params"`
+	LiquidatorParams liquidator.CollateralParams `json:"liquidator_params"`
+	Deposit          sdk.Coins                   `json:"deposit"`
+}
+
+func GetCmd_SubmitAddCollateralProposal(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "add-collateral [proposal-file]",
+		Short: "Submit a proposal to list a new collateral type",
+		Long: `Submit a proposal to list a new collateral type along with an initial deposit.
+If it passes, the asset is added to the pricefeed and CDPs can be opened with it, using the given cdp and liquidator params.
+The proposal details must be supplied via a JSON file, e.g.:
+
+{
+  "title": "List eth",
+  "description": "Let CDPs be opened with eth",
+  "asset": {"type": "ft", "asset_name": "eth", "description": "ether"},
+  "cdp_params": {"Denom": "eth", "LiquidationRatio": "1.5", "DebtLimit": "500000", "StabilityFee": "0.05", "DebtFloor": "10"},
+  "liquidator_params": {"Denom": "eth", "AuctionSize": "1", "LiquidationPenalty": "0.13"},
+  "deposit": [{"denom": "stake", "amount": "10000"}]
+}`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := authtxb.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().
+				WithCodec(cdc).
+				WithAccountDecoder(cdc)
+
+			contents, err := ioutil.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			var proposal collateralProposalJSON
+			if err := cdc.UnmarshalJSON(contents, &proposal); err != nil {
+				return err
+			}
+
+			content := liquidator.NewAddCollateralProposal(proposal.Title, proposal.Description, proposal.Asset, proposal.CdpParams, proposal.LiquidatorParams)
+			msg := gov.NewMsgSubmitProposal(content, proposal.Deposit, cliCtx.GetFromAddress())
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
@@ -28,6 +28,7 @@ func (mc ModuleClient) GetQueryCmd() *cobra.Command {
 
 	queryCmd.AddCommand(client.GetCommands(
 		cli.GetCmd_GetOutstandingDebt(mc.storeKey, mc.cdc),
+		cli.GetCmd_GetModuleAccount(mc.storeKey, mc.cdc),
 	)...)
 
 	return queryCmd
@@ -43,6 +44,7 @@ func (mc ModuleClient) GetTxCmd() *cobra.Command {
 	txCmd.AddCommand(client.PostCommands(
 		cli.GetCmd_SeizeAndStartCollateralAuction(mc.cdc),
 		cli.GetCmd_StartDebtAuction(mc.cdc),
+		cli.GetCmd_StartSurplusAuction(mc.cdc),
 	)...)
 
 	return txCmd
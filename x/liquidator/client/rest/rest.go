@@ -1,6 +1,5 @@
 package rest
 
-/*
 import (
 	"fmt"
 	"net/http"
@@ -13,6 +12,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/liquidator"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
 )
 
 // RegisterRoutes - Central function to define routes that get registered by the main application
@@ -20,7 +20,7 @@ func RegisterRoutes(cliCtx context.CLIContext, r *mux.Router, cdc *codec.Codec)
 	r.HandleFunc("/liquidator/outstandingdebt", queryDebtHandlerFn(cdc, cliCtx)).Methods("GET")
 	r.HandleFunc("/liquidator/seize", seizeCdpHandlerFn(cdc, cliCtx)).Methods("POST")
 	r.HandleFunc("/liquidator/mint", debtAuctionHandlerFn(cdc, cliCtx)).Methods("POST")
-	// r.HandleFunc("liquidator/burn", surplusAuctionHandlerFn(cdc, cliCtx).Methods("POST"))
+	r.HandleFunc("/liquidator/burn", surplusAuctionHandlerFn(cdc, cliCtx)).Methods("POST")
 }
 
 func queryDebtHandlerFn(cdc *codec.Codec, cliCtx context.CLIContext) http.HandlerFunc {
@@ -35,10 +35,10 @@ func queryDebtHandlerFn(cdc *codec.Codec, cliCtx context.CLIContext) http.Handle
 }
 
 type SeizeAndStartCollateralAuctionRequest struct {
-	BaseReq         rest.BaseReq   `json:"base_req"`
-	Sender          sdk.AccAddress `json:"sender"`
-	CdpOwner        sdk.AccAddress `json:"cdp_owner"`
-	CollateralDenom string         `json:"collateral_denom"`
+	BaseReq    rest.BaseReq     `json:"base_req"`
+	Sender     sdk.AccAddress   `json:"sender"`
+	CdpOwner   sdk.AccAddress   `json:"cdp_owner"`
+	Collateral types.Collateral `json:"collateral"`
 }
 
 func seizeCdpHandlerFn(cdc *codec.Codec, cliCtx context.CLIContext) http.HandlerFunc {
@@ -55,9 +55,9 @@ func seizeCdpHandlerFn(cdc *codec.Codec, cliCtx context.CLIContext) http.Handler
 
 		// Create msg
 		msg := liquidator.MsgSeizeAndStartCollateralAuction{
-			req.Sender,
-			req.CdpOwner,
-			req.Colla,
+			Sender:     req.Sender,
+			CdpOwner:   req.CdpOwner,
+			Collateral: req.Collateral,
 		}
 		if err := msg.ValidateBasic(); err != nil {
 			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
@@ -88,7 +88,38 @@ func debtAuctionHandlerFn(cdc *codec.Codec, cliCtx context.CLIContext) http.Hand
 
 		// Create msg
 		msg := liquidator.MsgStartDebtAuction{
-			req.Sender,
+			Sender: req.Sender,
+		}
+		if err := msg.ValidateBasic(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// Generate tx and write response
+		clientrest.WriteGenerateStdTxResponse(w, cdc, cliCtx, req.BaseReq, []sdk.Msg{msg})
+	}
+}
+
+type StartSurplusAuctionRequest struct {
+	BaseReq rest.BaseReq   `json:"base_req"`
+	Sender  sdk.AccAddress `json:"sender"` // TODO use baseReq.From instead?
+}
+
+func surplusAuctionHandlerFn(cdc *codec.Codec, cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get args from post body
+		var req StartSurplusAuctionRequest
+		if !rest.ReadRESTReq(w, r, cdc, &req) {
+			return
+		}
+		req.BaseReq = req.BaseReq.Sanitize()
+		if !req.BaseReq.ValidateBasic(w) {
+			return
+		}
+
+		// Create msg
+		msg := liquidator.MsgStartSurplusAuction{
+			Sender: req.Sender,
 		}
 		if err := msg.ValidateBasic(); err != nil {
 			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
@@ -99,4 +130,3 @@ func debtAuctionHandlerFn(cdc *codec.Codec, cliCtx context.CLIContext) http.Hand
 		clientrest.WriteGenerateStdTxResponse(w, cdc, cliCtx, req.BaseReq, []sdk.Msg{msg})
 	}
 }
-*/
@@ -1,12 +1,16 @@
 package liquidator
 
-import "github.com/cosmos/cosmos-sdk/codec"
+import (
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/cdp"
+	"github.com/cosmos/cosmos-sdk/codec"
+)
 
 var moduleCdc = codec.New()
 
 func init() {
 	cdc := codec.New()
 	RegisterCodec(cdc)
+	cdp.RegisterCodec(cdc) // for the collateral tokens in msgs
 	codec.RegisterCrypto(cdc)
 	moduleCdc = cdc.Seal()
 }
@@ -15,5 +19,6 @@ func init() {
 func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgSeizeAndStartCollateralAuction{}, "liquidator/MsgSeizeAndStartCollateralAuction", nil)
 	cdc.RegisterConcrete(MsgStartDebtAuction{}, "liquidator/MsgStartDebtAuction", nil)
-	// cdc.RegisterConcrete(MsgStartSurplusAuction{}, "liquidator/MsgStartSurplusAuction", nil)
+	cdc.RegisterConcrete(MsgStartSurplusAuction{}, "liquidator/MsgStartSurplusAuction", nil)
+	cdc.RegisterConcrete(AddCollateralProposal{}, "liquidator/AddCollateralProposal", nil)
 }
@@ -2,6 +2,7 @@ package liquidator
 
 import (
 	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/auction"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/pricefeed"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
@@ -15,4 +16,9 @@ type auctionKeeper interface {
 	StartForwardAuction(sdk.Context, sdk.AccAddress, sdk.Coin, sdk.Coin) (auction.ID, sdk.Error)
 	StartReverseAuction(sdk.Context, sdk.AccAddress, sdk.Coin, sdk.Coin) (auction.ID, sdk.Error)
 	StartForwardReverseAuction(sdk.Context, sdk.AccAddress, sdk.Coin, sdk.Coin, sdk.AccAddress) (auction.ID, sdk.Error)
+	GetAuctions(sdk.Context) []auction.Auction
+}
+
+type pricefeedKeeper interface {
+	RegisterAsset(sdk.Context, pricefeed.Asset) sdk.Error
 }
@@ -1,12 +1,16 @@
 package liquidator
 
 import (
+	"fmt"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
 // GenesisState is the state that must be provided at genesis.
 type GenesisState struct {
 	LiquidatorModuleParams LiquidatorModuleParams `json:"params"`
+	SeizedDebt             SeizedDebt             `json:"seized_debt"`
+	PendingPenalties       sdk.Int                `json:"pending_penalties"`
 }
 
 // DefaultGenesisState returns a default genesis state
@@ -14,38 +18,89 @@ type GenesisState struct {
 func DefaultGenesisState() GenesisState {
 	return GenesisState{
 		LiquidatorModuleParams{
-			DebtAuctionSize: sdk.NewInt(1000),
+			DebtAuctionSize:    sdk.NewInt(1000),
+			SurplusAuctionSize: sdk.NewInt(1000),
 			CollateralParams: []CollateralParams{
 				{
-					Denom:       "btc",
-					AuctionSize: sdk.NewInt(1),
+					Denom:              "btc",
+					AuctionSize:        sdk.NewInt(1),
+					LiquidationPenalty: sdk.MustNewDecFromStr("0.13"),
 				},
 				{
-					Denom:       "xrp",
-					AuctionSize: sdk.NewInt(1000),
+					Denom:              "xrp",
+					AuctionSize:        sdk.NewInt(1000),
+					LiquidationPenalty: sdk.MustNewDecFromStr("0.13"),
 				},
 			},
 		},
+		SeizedDebt{sdk.ZeroInt(), sdk.ZeroInt()},
+		sdk.ZeroInt(),
 	}
 }
 
 // InitGenesis sets the genesis state in the keeper.
 func InitGenesis(ctx sdk.Context, keeper Keeper, data GenesisState) {
 	keeper.setParams(ctx, data.LiquidatorModuleParams)
+	keeper.setSeizedDebt(ctx, data.SeizedDebt)
+	// genesis files from before the penalties were paid to the pool don't have any pending
+	pendingPenalties := data.PendingPenalties
+	if pendingPenalties == (sdk.Int{}) {
+		pendingPenalties = sdk.ZeroInt()
+	}
+	keeper.setPendingPenalties(ctx, pendingPenalties)
 }
 
 // ExportGenesis returns a GenesisState for a given context and keeper.
 func ExportGenesis(ctx sdk.Context, keeper Keeper) GenesisState {
-	// TODO implement this
-	return DefaultGenesisState()
+	return GenesisState{
+		LiquidatorModuleParams: keeper.GetParams(ctx),
+		SeizedDebt:             keeper.GetSeizedDebt(ctx),
+		PendingPenalties:       keeper.GetPendingPenalties(ctx),
+	}
 }
 
 // ValidateGenesis performs basic validation of genesis data returning an error for any failed validation criteria.
 func ValidateGenesis(data GenesisState) error {
-	// TODO
-	// check debt auction size > 0
-	// validate denoms
-	// check no repeated denoms
-	// check collateral auction sizes > 0
+	params := data.LiquidatorModuleParams
+	if params.DebtAuctionSize == (sdk.Int{}) || !params.DebtAuctionSize.IsPositive() {
+		return fmt.Errorf("debt auction size must be positive")
+	}
+	if params.SurplusAuctionSize == (sdk.Int{}) || !params.SurplusAuctionSize.IsPositive() {
+		return fmt.Errorf("surplus auction size must be positive")
+	}
+	denoms := make(map[string]bool)
+	for _, cp := range params.CollateralParams {
+		if err := validateCollateralParams(cp); err != nil {
+			return err
+		}
+		if denoms[cp.Denom] {
+			return fmt.Errorf("collateral %s has repeated params", cp.Denom)
+		}
+		denoms[cp.Denom] = true
+	}
+
+	seizedDebt := data.SeizedDebt
+	if seizedDebt.Total == (sdk.Int{}) || seizedDebt.SentToAuction == (sdk.Int{}) {
+		return fmt.Errorf("seized debt must be set")
+	}
+	if seizedDebt.SentToAuction.IsNegative() || seizedDebt.SentToAuction.GT(seizedDebt.Total) {
+		return fmt.Errorf("seized debt sent to auction %s must be between zero and the total seized debt %s", seizedDebt.SentToAuction, seizedDebt.Total)
+	}
+	if data.PendingPenalties != (sdk.Int{}) && data.PendingPenalties.IsNegative() {
+		return fmt.Errorf("pending penalties can't be negative")
+	}
+	return nil
+}
+
+func validateCollateralParams(cp CollateralParams) error {
+	if !(sdk.Coins{{Denom: cp.Denom, Amount: sdk.OneInt()}}).IsValid() {
+		return fmt.Errorf("invalid collateral denom %q", cp.Denom)
+	}
+	if cp.AuctionSize == (sdk.Int{}) || !cp.AuctionSize.IsPositive() {
+		return fmt.Errorf("auction size for collateral %s must be positive", cp.Denom)
+	}
+	if cp.LiquidationPenalty.IsNil() || cp.LiquidationPenalty.IsNegative() {
+		return fmt.Errorf("liquidation penalty for collateral %s must be set and not negative", cp.Denom)
+	}
 	return nil
 }
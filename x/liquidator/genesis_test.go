@@ -0,0 +1,94 @@
+package liquidator
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateGenesis(t *testing.T) {
+	tests := []struct {
+		name       string
+		modify     func(*LiquidatorModuleParams)
+		expectPass bool
+	}{
+		{"default", func(*LiquidatorModuleParams) {}, true},
+		{"zeroDebtAuctionSize", func(p *LiquidatorModuleParams) { p.DebtAuctionSize = i(0) }, false},
+		{"unsetSurplusAuctionSize", func(p *LiquidatorModuleParams) { p.SurplusAuctionSize = sdk.Int{} }, false},
+		{"zeroSurplusAuctionSize", func(p *LiquidatorModuleParams) { p.SurplusAuctionSize = i(0) }, false},
+		{"invalidDenom", func(p *LiquidatorModuleParams) { p.CollateralParams[0].Denom = "BTC" }, false},
+		{"repeatedDenom", func(p *LiquidatorModuleParams) { p.CollateralParams[1].Denom = "btc" }, false},
+		{"zeroAuctionSize", func(p *LiquidatorModuleParams) { p.CollateralParams[0].AuctionSize = i(0) }, false},
+		{"zeroPenalty", func(p *LiquidatorModuleParams) { p.CollateralParams[0].LiquidationPenalty = sdk.ZeroDec() }, true},
+		{"negativePenalty", func(p *LiquidatorModuleParams) {
+			p.CollateralParams[0].LiquidationPenalty = sdk.MustNewDecFromStr("-0.1")
+		}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			genesis := DefaultGenesisState()
+			tc.modify(&genesis.LiquidatorModuleParams)
+			err := ValidateGenesis(genesis)
+			if tc.expectPass {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateGenesis_SeizedDebt(t *testing.T) {
+	tests := []struct {
+		name       string
+		seizedDebt SeizedDebt
+		expectPass bool
+	}{
+		{"zero", SeizedDebt{i(0), i(0)}, true},
+		{"partlySentToAuction", SeizedDebt{i(100), i(40)}, true},
+		{"unset", SeizedDebt{}, false},
+		{"negativeSentToAuction", SeizedDebt{i(100), i(-1)}, false},
+		{"tooMuchSentToAuction", SeizedDebt{i(100), i(101)}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			genesis := DefaultGenesisState()
+			genesis.SeizedDebt = tc.seizedDebt
+			err := ValidateGenesis(genesis)
+			if tc.expectPass {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateGenesis_PendingPenalties(t *testing.T) {
+	genesis := DefaultGenesisState()
+	genesis.PendingPenalties = i(-1)
+	require.Error(t, ValidateGenesis(genesis))
+
+	// genesis files from before the penalties were paid to the pool don't have any pending
+	genesis.PendingPenalties = sdk.Int{}
+	require.NoError(t, ValidateGenesis(genesis))
+	ctx, k := setupTestKeepers()
+	InitGenesis(ctx, k.liquidatorKeeper, genesis)
+	require.Equal(t, i(0), k.liquidatorKeeper.GetPendingPenalties(ctx))
+}
+
+func TestExportImportGenesis(t *testing.T) {
+	// Setup
+	ctx, k := setupTestKeepers()
+	genesis := DefaultGenesisState()
+	genesis.LiquidatorModuleParams.SurplusAuctionSize = i(500)
+	genesis.SeizedDebt = SeizedDebt{i(100), i(40)}
+	genesis.PendingPenalties = i(13)
+
+	// Run test function
+	InitGenesis(ctx, k.liquidatorKeeper, genesis)
+
+	// Check
+	require.Equal(t, genesis, ExportGenesis(ctx, k.liquidatorKeeper))
+}
@@ -14,8 +14,8 @@ func NewHandler(keeper Keeper) sdk.Handler {
 			return handleMsgSeizeAndStartCollateralAuction(ctx, keeper, msg)
 		case MsgStartDebtAuction:
 			return handleMsgStartDebtAuction(ctx, keeper)
-		// case MsgStartSurplusAuction:
-		// 	return handleMsgStartSurplusAuction(ctx, keeper)
+		case MsgStartSurplusAuction:
+			return handleMsgStartSurplusAuction(ctx, keeper)
 		default:
 			errMsg := fmt.Sprintf("Unrecognized liquidator msg type: %T", msg)
 			return sdk.ErrUnknownRequest(errMsg).Result()
@@ -33,7 +33,7 @@ func handleMsgSeizeAndStartCollateralAuction(ctx sdk.Context, keeper Keeper, msg
 
 func handleMsgStartDebtAuction(ctx sdk.Context, keeper Keeper) sdk.Result {
 	// cancel out any debt and stable coins before trying to start auction
-	keeper.settleDebt(ctx)
+	keeper.settleDebt(ctx, keeper.cdpKeeper.GetStableDenom(ctx))
 	// start an auction
 	_, err := keeper.StartDebtAuction(ctx)
 	if err != nil {
@@ -42,13 +42,13 @@ func handleMsgStartDebtAuction(ctx sdk.Context, keeper Keeper) sdk.Result {
 	return sdk.Result{} // TODO tags, return auction ID
 }
 
-// With no stability and liquidation fees, surplus auctions can never be run.
-// func handleMsgStartSurplusAuction(ctx sdk.Context, keeper Keeper) sdk.Result {
-// 	// cancel out any debt and stable coins before trying to start auction
-//  keeper.settleDebt(ctx)
-// 	_, err := keeper.StartSurplusAuction(ctx)
-// 	if err != nil {
-// 		return err.Result()
-// 	}
-// 	return sdk.Result{} // TODO tags
-// }
+func handleMsgStartSurplusAuction(ctx sdk.Context, keeper Keeper) sdk.Result {
+	// cancel out any debt and stable coins before trying to start auction
+	keeper.settleDebt(ctx, keeper.cdpKeeper.GetStableDenom(ctx))
+	// start an auction
+	_, err := keeper.StartSurplusAuction(ctx)
+	if err != nil {
+		return err.Result()
+	}
+	return sdk.Result{} // TODO tags, return auction ID
+}
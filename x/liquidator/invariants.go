@@ -0,0 +1,74 @@
+package liquidator
+
+import (
+	"fmt"
+
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/auction"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// RegisterInvariants registers the liquidator module invariants
+func RegisterInvariants(ir sdk.InvariantRouter, k Keeper) {
+	ir.RegisterRoute(ModuleName, "seized-debt",
+		SeizedDebtInvariant(k))
+	ir.RegisterRoute(ModuleName, "global-debt",
+		GlobalDebtInvariant(k))
+	ir.RegisterRoute(ModuleName, "collateral-in-auctions",
+		CollateralInAuctionsInvariant(k))
+}
+
+// SeizedDebtInvariant checks that no more debt has been sent to auction than has been seized
+func SeizedDebtInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) error {
+		seizedDebt := k.GetSeizedDebt(ctx)
+		if seizedDebt.SentToAuction.IsNegative() || seizedDebt.SentToAuction.GT(seizedDebt.Total) {
+			return fmt.Errorf("seized debt sent to auction %s is not between zero and the total seized debt %s", seizedDebt.SentToAuction, seizedDebt.Total)
+		}
+		return nil
+	}
+}
+
+// GlobalDebtInvariant checks that the cdp global debt equals the total debt of all collateral types plus the seized debt.
+// Seized debt leaves the collateral totals when a CDP is seized, but only leaves the global debt once settled.
+func GlobalDebtInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) error {
+		totalDebt := k.GetSeizedDebt(ctx).Total
+		for _, cp := range k.cdpKeeper.GetParams(ctx).CollateralParams {
+			if collateralState, found := k.cdpKeeper.GetCollateralState(ctx, cp.Denom); found {
+				totalDebt = totalDebt.Add(collateralState.TotalDebt)
+			}
+		}
+		globalDebt := k.cdpKeeper.GetGlobalDebt(ctx)
+		if !globalDebt.Equal(totalDebt) {
+			return fmt.Errorf("global debt %s doesn't equal the collateral and seized debt %s", globalDebt, totalDebt)
+		}
+		return nil
+	}
+}
+
+// CollateralInAuctionsInvariant checks the module account holds no collateral, and the auction escrow holds the lots of the auctions started by the liquidator.
+// Seized collateral is escrowed in the lot of a collateral auction as soon as it's seized, and goes to the buyer or the CDP owner from there.
+// The lots of surplus auctions (stable coin) and debt auctions (minted gov coin) are escrowed the same way, while bids are paid on as soon as they're placed.
+func CollateralInAuctionsInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) error {
+		liquidatorAddress := k.cdpKeeper.GetLiquidatorAccountAddress()
+		coins := k.bankKeeper.GetCoins(ctx, liquidatorAddress)
+		for _, cp := range k.cdpKeeper.GetParams(ctx).CollateralParams {
+			if !coins.AmountOf(cp.Denom).IsZero() {
+				return fmt.Errorf("liquidator module account holds %s%s not up for auction", coins.AmountOf(cp.Denom), cp.Denom)
+			}
+		}
+
+		lots := sdk.NewCoins()
+		for _, a := range k.auctionKeeper.GetAuctions(ctx) {
+			if a.GetInitiator().Equals(liquidatorAddress) {
+				lots = lots.Add(sdk.NewCoins(a.GetLot()))
+			}
+		}
+		escrow := k.bankKeeper.GetCoins(ctx, auction.ModuleAddress)
+		if !escrow.IsAllGTE(lots) {
+			return fmt.Errorf("auction escrow %s doesn't cover the lots of the liquidator auctions %s", escrow, lots)
+		}
+		return nil
+	}
+}
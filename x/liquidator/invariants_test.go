@@ -0,0 +1,47 @@
+package liquidator
+
+import (
+	"testing"
+
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/auction"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/cdp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvariants(t *testing.T) {
+	// Setup, the global debt is all seized debt
+	ctx, k := setupTestKeepers()
+	cdpGenesis := cdp.DefaultGenesisState()
+	cdpGenesis.GlobalDebt = i(100)
+	cdp.InitGenesis(ctx, k.cdpKeeper, cdpGenesis)
+	InitGenesis(ctx, k.liquidatorKeeper, DefaultGenesisState())
+	k.liquidatorKeeper.setSeizedDebt(ctx, SeizedDebt{i(100), i(40)})
+
+	// Check the invariants hold
+	require.NoError(t, SeizedDebtInvariant(k.liquidatorKeeper)(ctx))
+	require.NoError(t, GlobalDebtInvariant(k.liquidatorKeeper)(ctx))
+	require.NoError(t, CollateralInAuctionsInvariant(k.liquidatorKeeper)(ctx))
+
+	// Check the lots of running auctions are held in escrow, the surplus auction sells stable coin and the debt auction minted gov coin
+	k.liquidatorKeeper.setSeizedDebt(ctx, SeizedDebt{i(0), i(0)})
+	k.cdpKeeper.AddCoins(ctx, k.cdpKeeper.GetLiquidatorAccountAddress(), cs(c(stableDenom, 1000)))
+	_, err := k.liquidatorKeeper.StartSurplusAuction(ctx)
+	require.NoError(t, err)
+	k.liquidatorKeeper.setSeizedDebt(ctx, SeizedDebt{i(1000), i(0)})
+	_, err = k.liquidatorKeeper.StartDebtAuction(ctx)
+	require.NoError(t, err)
+	k.liquidatorKeeper.setSeizedDebt(ctx, SeizedDebt{i(100), i(40)})
+	require.NoError(t, CollateralInAuctionsInvariant(k.liquidatorKeeper)(ctx))
+	_, err = k.bankKeeper.SubtractCoins(ctx, auction.ModuleAddress, cs(c(stableDenom, 1)))
+	require.NoError(t, err)
+	require.Error(t, CollateralInAuctionsInvariant(k.liquidatorKeeper)(ctx))
+	k.bankKeeper.AddCoins(ctx, auction.ModuleAddress, cs(c(stableDenom, 1)))
+	require.NoError(t, CollateralInAuctionsInvariant(k.liquidatorKeeper)(ctx))
+
+	// Break them
+	k.liquidatorKeeper.setSeizedDebt(ctx, SeizedDebt{i(90), i(100)})
+	require.Error(t, SeizedDebtInvariant(k.liquidatorKeeper)(ctx))
+	require.Error(t, GlobalDebtInvariant(k.liquidatorKeeper)(ctx))
+	k.liquidatorKeeper.bankKeeper.AddCoins(ctx, k.cdpKeeper.GetLiquidatorAccountAddress(), cs(c("btc", 1)))
+	require.Error(t, CollateralInAuctionsInvariant(k.liquidatorKeeper)(ctx))
+}
@@ -1,6 +1,8 @@
 package liquidator
 
 import (
+	"fmt"
+
 	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/auction"
 	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/cdp"
 	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
@@ -32,6 +34,7 @@ func NewKeeper(cdc *codec.Codec, storeKey sdk.StoreKey, subspace params.Subspace
 
 // SeizeAndStartCollateralAuction pulls collateral out of a CDP and sells it in an auction for stable coin. Excess collateral goes to the original CDP owner.
 // Known as Cat.bite in maker
+// The auction tries to raise the seized debt plus a liquidation penalty, the penalty ends up as surplus in the module account.
 // result: stable coin is transferred to module account, collateral is transferred from module account to buyer, (and any excess collateral is transferred to original CDP owner)
 func (k Keeper) SeizeAndStartCollateralAuction(ctx sdk.Context, owner sdk.AccAddress, collateral types.Collateral) (auction.ID, sdk.Error) {
 	// Get CDP
@@ -61,13 +64,21 @@ func (k Keeper) SeizeAndStartCollateralAuction(ctx sdk.Context, owner sdk.AccAdd
 		return 0, err
 	}
 
+	// Add the liquidation penalty on top of the seized debt. Bids above the debt stay in the module account as surplus.
+	penalty := sdk.ZeroInt()
+	if !params.LiquidationPenalty.IsNil() {
+		penalty = sdk.NewDecFromInt(stableToRaise).Mul(params.LiquidationPenalty).RoundInt()
+	}
+
 	// Start "forward reverse" auction type
 	lot := sdk.NewCoin(localCdp.Collateral.Token.GetName(), collateralToSell)
-	maxBid := sdk.NewCoin(k.cdpKeeper.GetStableDenom(), stableToRaise)
+	maxBid := sdk.NewCoin(localCdp.Liquidity.Coin.Denom, stableToRaise.Add(penalty))
 	auctionID, err := k.auctionKeeper.StartForwardReverseAuction(ctx, k.cdpKeeper.GetLiquidatorAccountAddress(), lot, maxBid, owner)
 	if err != nil {
 		panic(err) // TODO how can errors here be handled to be safe with the state update in PartialSeizeCDP?
 	}
+	// The penalty is paid to the pool once raised, see settleDebt
+	k.setPendingPenalties(ctx, k.GetPendingPenalties(ctx).Add(penalty))
 	return auctionID, nil
 }
 
@@ -77,7 +88,7 @@ func (k Keeper) SeizeAndStartCollateralAuction(ctx sdk.Context, owner sdk.AccAdd
 func (k Keeper) StartDebtAuction(ctx sdk.Context) (auction.ID, sdk.Error) {
 
 	// Ensure amount of seized stable coin is 0 (ie Joy = 0)
-	stableCoins := k.bankKeeper.GetCoins(ctx, k.cdpKeeper.GetLiquidatorAccountAddress()).AmountOf(k.cdpKeeper.GetStableDenom())
+	stableCoins := k.bankKeeper.GetCoins(ctx, k.cdpKeeper.GetLiquidatorAccountAddress()).AmountOf(k.cdpKeeper.GetStableDenom(ctx))
 	if !stableCoins.IsZero() {
 		return 0, sdk.ErrInternal("debt auction cannot be started as there is outstanding stable coins")
 	}
@@ -92,7 +103,7 @@ func (k Keeper) StartDebtAuction(ctx sdk.Context) (auction.ID, sdk.Error) {
 	auctionID, err := k.auctionKeeper.StartReverseAuction(
 		ctx,
 		k.cdpKeeper.GetLiquidatorAccountAddress(),
-		sdk.NewCoin(k.cdpKeeper.GetStableDenom(), params.DebtAuctionSize),
+		sdk.NewCoin(k.cdpKeeper.GetStableDenom(ctx), params.DebtAuctionSize),
 		sdk.NewInt64Coin(k.cdpKeeper.GetGovDenom(), 2^255-1), // TODO is there a way to avoid potentially minting infinite gov coin?
 	)
 	if err != nil {
@@ -104,32 +115,38 @@ func (k Keeper) StartDebtAuction(ctx sdk.Context) (auction.ID, sdk.Error) {
 	return auctionID, nil
 }
 
-// With no stability and liquidation fees, surplus auctions can never be run.
 // StartSurplusAuction sells off excess stable coin in exchange for gov coin, which is burned
 // Known as Vow.flap in maker
-// result: stable coin removed from module account (eventually to buyer), gov coin transferred to module account
-// func (k Keeper) StartSurplusAuction(ctx sdk.Context) (auction.ID, sdk.Error) {
-
-// 	// TODO ensure seized debt is 0
-
-// 	// check there is enough surplus to be sold
-// 	surplus := k.bankKeeper.GetCoins(ctx, k.cdpKeeper.GetLiquidatorAccountAddress()).AmountOf(k.cdpKeeper.GetStableDenom())
-// 	if surplus.LT(SurplusAuctionSize) {
-// 		return 0, sdk.ErrInternal("not enough surplus stable coin to start an auction")
-// 	}
-// 	// start normal auction, selling stable coin
-// 	auctionID, err := k.auctionKeeper.StartForwardAuction(
-// 		ctx,
-// 		k.cdpKeeper.GetLiquidatorAccountAddress(),
-// 		sdk.NewCoin(k.cdpKeeper.GetStableDenom(), SurplusAuctionSize),
-// 		sdk.NewInt64Coin(k.cdpKeeper.GetGovDenom(), 0),
-// 	)
-// 	if err != nil {
-// 		return 0, err
-// 	}
-// 	// Starting the auction will remove coins from the account, so they don't need modified here.
-// 	return auctionID, nil
-// }
+// Surplus comes from the liquidator part of the stability fees collected by the cdp module, the rest goes to the pool.
+// result: stable coin removed from module account (eventually to buyer), gov coin transferred to module account (where it's burned, as the module account doesn't hold gov coin)
+func (k Keeper) StartSurplusAuction(ctx sdk.Context) (auction.ID, sdk.Error) {
+
+	// Ensure there is no seized debt left, stable coin should be used to cover it before being sold off
+	seizedDebt := k.GetSeizedDebt(ctx)
+	if !seizedDebt.Total.IsZero() {
+		return 0, sdk.ErrInternal("surplus auction cannot be started as there is outstanding seized debt")
+	}
+
+	// check there is enough surplus to be sold
+	params := k.GetParams(ctx)
+	stableDenom := k.cdpKeeper.GetStableDenom(ctx)
+	surplus := k.bankKeeper.GetCoins(ctx, k.cdpKeeper.GetLiquidatorAccountAddress()).AmountOf(stableDenom)
+	if surplus.LT(params.SurplusAuctionSize) {
+		return 0, sdk.ErrInternal("not enough surplus stable coin to start an auction")
+	}
+	// start normal auction, selling stable coin
+	auctionID, err := k.auctionKeeper.StartForwardAuction(
+		ctx,
+		k.cdpKeeper.GetLiquidatorAccountAddress(),
+		sdk.NewCoin(stableDenom, params.SurplusAuctionSize),
+		sdk.NewInt64Coin(k.cdpKeeper.GetGovDenom(), 0),
+	)
+	if err != nil {
+		return 0, err
+	}
+	// Starting the auction will remove coins from the account, so they don't need modified here.
+	return auctionID, nil
+}
 
 // PartialSeizeCDP seizes some collateral and debt from an under-collateralized CDP.
 func (k Keeper) partialSeizeCDP(ctx sdk.Context, owner sdk.AccAddress, collateral types.Collateral, collateralToSeize sdk.Int, debtToSeize sdk.Int) sdk.Error { // aka Cat.bite
@@ -154,16 +171,25 @@ func (k Keeper) partialSeizeCDP(ctx sdk.Context, owner sdk.AccAddress, collatera
 }
 
 // SettleDebt removes equal amounts of debt and stable coin from the liquidator's reserves (and also updates the global debt in the cdp module).
-// This is called in the handler when a debt or surplus auction is started
+// The stable coin goes back to the pool, repaying the liquidity it lent to the seized CDPs.
+// Stable coin left over pays the pending liquidation penalties to the pool, penalties not raised by the time the collateral auctions close are dropped.
+// This is called in the handler when a debt or surplus auction is started. Nothing is written if it fails.
 // TODO Should this be called with an amount, rather than annihilating the maximum?
-func (k Keeper) settleDebt(ctx sdk.Context) sdk.Error {
+func (k Keeper) settleDebt(ctx sdk.Context, stableDenom string) sdk.Error {
 	// Calculate max amount of debt and stable coins that can be settled (ie annihilated)
 	debt := k.GetSeizedDebt(ctx)
-	stableCoins := k.bankKeeper.GetCoins(ctx, k.cdpKeeper.GetLiquidatorAccountAddress()).AmountOf(k.cdpKeeper.GetStableDenom())
+	stableCoins := k.bankKeeper.GetCoins(ctx, k.cdpKeeper.GetLiquidatorAccountAddress()).AmountOf(stableDenom)
 	settleAmount := sdk.MinInt(debt.Total, stableCoins)
+	cacheCtx, write := ctx.CacheContext()
+
+	// Move the stable coin from the module account back to the pool
+	err := k.cdpKeeper.ReturnSeizedLiquidity(cacheCtx, sdk.NewCoin(stableDenom, settleAmount))
+	if err != nil {
+		return err
+	}
 
 	// Call cdp module to reduce GlobalDebt. This can fail if genesis not set
-	err := k.cdpKeeper.ReduceGlobalDebt(ctx, settleAmount)
+	err = k.cdpKeeper.ReduceGlobalDebt(cacheCtx, settleAmount)
 	if err != nil {
 		return err
 	}
@@ -173,13 +199,35 @@ func (k Keeper) settleDebt(ctx sdk.Context) sdk.Error {
 	if err != nil {
 		return err // this should not error in this context
 	}
-	k.setSeizedDebt(ctx, updatedDebt)
+	k.setSeizedDebt(cacheCtx, updatedDebt)
 
-	// Subtract stable coin from moduleAccout
-	k.bankKeeper.SubtractCoins(ctx, k.cdpKeeper.GetLiquidatorAccountAddress(), sdk.Coins{sdk.NewCoin(k.cdpKeeper.GetStableDenom(), settleAmount)})
+	// Pay the penalties raised on top of the seized debt to the pool
+	stableDenom = k.cdpKeeper.GetStableDenom(cacheCtx)
+	stableCoins = k.bankKeeper.GetCoins(cacheCtx, k.cdpKeeper.GetLiquidatorAccountAddress()).AmountOf(stableDenom)
+	penalty := sdk.MinInt(k.GetPendingPenalties(cacheCtx), stableCoins)
+	err = k.cdpKeeper.PayLiquidationPenalty(cacheCtx, sdk.NewCoin(stableDenom, penalty))
+	if err != nil {
+		return err
+	}
+	pendingPenalties := k.GetPendingPenalties(cacheCtx).Sub(penalty)
+	if !k.hasCollateralAuctions(cacheCtx) {
+		pendingPenalties = sdk.ZeroInt()
+	}
+	k.setPendingPenalties(cacheCtx, pendingPenalties)
+	write()
 	return nil
 }
 
+// hasCollateralAuctions returns whether any auction of seized collateral is still running
+func (k Keeper) hasCollateralAuctions(ctx sdk.Context) bool {
+	for _, a := range k.auctionKeeper.GetAuctions(ctx) {
+		if _, ok := a.(*auction.ForwardReverseAuction); ok && a.GetInitiator().Equals(k.cdpKeeper.GetLiquidatorAccountAddress()) {
+			return true
+		}
+	}
+	return false
+}
+
 // ---------- Module Parameters ----------
 
 func (k Keeper) GetParams(ctx sdk.Context) LiquidatorModuleParams {
@@ -188,6 +236,22 @@ func (k Keeper) GetParams(ctx sdk.Context) LiquidatorModuleParams {
 	return params
 }
 
+// AddCollateralParams sets the auction params of a new collateral type, as listed through governance
+func (k Keeper) AddCollateralParams(ctx sdk.Context, collateralParams CollateralParams) sdk.Error {
+	params := k.GetParams(ctx)
+	for _, cp := range params.CollateralParams {
+		if cp.Denom == collateralParams.Denom {
+			return sdk.ErrInternal(fmt.Sprintf("collateral %s already has params", collateralParams.Denom))
+		}
+	}
+	if err := validateCollateralParams(collateralParams); err != nil {
+		return sdk.ErrInternal(err.Error())
+	}
+	params.CollateralParams = append(params.CollateralParams, collateralParams)
+	k.setParams(ctx, params)
+	return nil
+}
+
 // This is only needed to be able to setup the store from the genesis file. The keeper should not change any of the params itself.
 func (k Keeper) setParams(ctx sdk.Context, params LiquidatorModuleParams) {
 	k.paramsSubspace.Set(ctx, moduleParamsKey, &params)
@@ -202,8 +266,7 @@ func (k Keeper) GetSeizedDebt(ctx sdk.Context) SeizedDebt {
 	store := ctx.KVStore(k.storeKey)
 	bz := store.Get(k.getSeizedDebtKey())
 	if bz == nil {
-		// TODO make initial seized debt and CDPs configurable at genesis, then panic here if not found
-		bz = k.cdc.MustMarshalBinaryLengthPrefixed(SeizedDebt{sdk.ZeroInt(), sdk.ZeroInt()})
+		panic("seized debt not set in genesis")
 	}
 	var seizedDebt SeizedDebt
 	k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &seizedDebt)
@@ -214,3 +277,24 @@ func (k Keeper) setSeizedDebt(ctx sdk.Context, debt SeizedDebt) {
 	bz := k.cdc.MustMarshalBinaryLengthPrefixed(debt)
 	store.Set(k.getSeizedDebtKey(), bz)
 }
+
+func (k Keeper) getPendingPenaltiesKey() []byte {
+	return []byte("pendingPenalties")
+}
+
+// GetPendingPenalties returns the liquidation penalties added to the collateral auctions that haven't been paid to the pool yet
+func (k Keeper) GetPendingPenalties(ctx sdk.Context) sdk.Int {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(k.getPendingPenaltiesKey())
+	if bz == nil {
+		return sdk.ZeroInt()
+	}
+	var penalties sdk.Int
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &penalties)
+	return penalties
+}
+func (k Keeper) setPendingPenalties(ctx sdk.Context, penalties sdk.Int) {
+	store := ctx.KVStore(k.storeKey)
+	bz := k.cdc.MustMarshalBinaryLengthPrefixed(penalties)
+	store.Set(k.getPendingPenaltiesKey(), bz)
+}
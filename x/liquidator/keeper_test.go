@@ -3,8 +3,10 @@ package liquidator
 import (
 	"testing"
 
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/auction"
 	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/cdp"
 	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/pricefeed"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/mock"
 	"github.com/stretchr/testify/require"
@@ -18,36 +20,140 @@ func TestKeeper_SeizeAndStartCollateralAuction(t *testing.T) {
 
 	cdp.InitGenesis(ctx, k.cdpKeeper, cdp.DefaultGenesisState())
 	InitGenesis(ctx, k.liquidatorKeeper, DefaultGenesisState())
-	pricefeed.InitGenesis(ctx, k.pricefeedKeeper, pricefeed.GenesisState{Assets: []pricefeed.Asset{{"btc", "a description"}}})
-	k.pricefeedKeeper.SetPrice(ctx, addrs[0], "btc", sdk.MustNewDecFromStr("8000.00"), i(999999999))
+	pricefeed.InitGenesis(ctx, k.pricefeedKeeper, pricefeed.GenesisState{
+		Assets: []pricefeed.Asset{
+			{Type: "ft", AssetName: "btc", Description: "a description"},
+			{Type: "ft", AssetName: stableDenom, Description: "the stable coin"},
+		},
+		Oracles: []pricefeed.Oracle{{OracleAddress: addrs[0].String()}},
+	})
+	k.pricefeedKeeper.SetPrice(ctx, addrs[0], "", stableDenom, i(1), i(999999999))
+	k.pricefeedKeeper.SetPrice(ctx, addrs[0], "", "btc", i(8000), i(999999999))
 	k.pricefeedKeeper.SetCurrentPrices(ctx)
-	k.bankKeeper.AddCoins(ctx, addrs[0], cs(c("btc", 100)))
+	k.bankKeeper.AddCoins(ctx, addrs[0], cs(c("btc", 100), c(stableDenom, 16000)))
+	k.poolKeeper.DepositFundFromAddress(ctx, addrs[0], c(stableDenom, 16000))
 
-	k.cdpKeeper.ModifyCDP(ctx, addrs[0], "btc", i(3), i(16000))
+	k.cdpKeeper.ModifyCDP(ctx, addrs[0], ftCollateral("btc", 3), types.Liquidity{Coin: c(stableDenom, 16000), InitialPrice: i(0)})
 
-	k.pricefeedKeeper.SetPrice(ctx, addrs[0], "btc", sdk.MustNewDecFromStr("7999.99"), i(999999999))
+	k.pricefeedKeeper.SetPrice(ctx, addrs[0], "", "btc", i(7999), i(999999999))
 	k.pricefeedKeeper.SetCurrentPrices(ctx)
 
 	// Run test function
-	auctionID, err := k.liquidatorKeeper.SeizeAndStartCollateralAuction(ctx, addrs[0], "btc")
+	auctionID, err := k.liquidatorKeeper.SeizeAndStartCollateralAuction(ctx, addrs[0], ftCollateral("btc", 0))
 
 	// Check CDP
 	require.NoError(t, err)
-	cdp, found := k.cdpKeeper.GetCDP(ctx, addrs[0], "btc")
+	cdp, found := k.cdpKeeper.GetCDP(ctx, addrs[0], "btc", "")
 	require.True(t, found)
-	require.Equal(t, cdp.CollateralAmount, i(2)) // original amount - params.CollateralAuctionSize
-	require.Equal(t, cdp.Debt, i(10667))         // original debt scaled by amount of collateral removed
+	require.Equal(t, cdp.Collateral.Amount, i(2))         // original amount - params.CollateralAuctionSize
+	require.Equal(t, cdp.Liquidity.Coin.Amount, i(10667)) // original debt scaled by amount of collateral removed
 	// Check auction exists
-	_, found = k.auctionKeeper.GetAuction(ctx, auctionID)
+	a, found := k.auctionKeeper.GetAuction(ctx, auctionID)
 	require.True(t, found)
-	// TODO check auction values are correct?
+	// Check the max bid includes the liquidation penalty
+	require.Equal(t, c(stableDenom, 6026), a.(*auction.ForwardReverseAuction).MaxBid) // seized debt (5333) + 13% penalty
+	require.Equal(t, i(5333), k.liquidatorKeeper.GetSeizedDebt(ctx).Total)
+}
+
+func TestKeeper_settleDebt_RepaysPool(t *testing.T) {
+	// Setup a CDP drawing all the pool liquidity
+	ctx, k := setupTestKeepers()
+	_, addrs := mock.GeneratePrivKeyAddressPairs(2)
+	owner, bidder := addrs[0], addrs[1]
+	cdp.InitGenesis(ctx, k.cdpKeeper, cdp.DefaultGenesisState())
+	InitGenesis(ctx, k.liquidatorKeeper, DefaultGenesisState())
+	pricefeed.InitGenesis(ctx, k.pricefeedKeeper, pricefeed.GenesisState{
+		Assets: []pricefeed.Asset{
+			{Type: "ft", AssetName: "btc", Description: "a description"},
+			{Type: "ft", AssetName: stableDenom, Description: "the stable coin"},
+		},
+		Oracles: []pricefeed.Oracle{{OracleAddress: owner.String()}},
+	})
+	k.pricefeedKeeper.SetPrice(ctx, owner, "", stableDenom, i(1), i(999999999))
+	k.pricefeedKeeper.SetPrice(ctx, owner, "", "btc", i(8000), i(999999999))
+	k.pricefeedKeeper.SetCurrentPrices(ctx)
+	k.bankKeeper.AddCoins(ctx, owner, cs(c("btc", 100), c(stableDenom, 16000)))
+	k.bankKeeper.AddCoins(ctx, bidder, cs(c(stableDenom, 10000)))
+	require.NoError(t, k.poolKeeper.DepositFundFromAddress(ctx, owner, c(stableDenom, 16000)))
+	require.NoError(t, k.cdpKeeper.ModifyCDP(ctx, owner, ftCollateral("btc", 3), types.Liquidity{Coin: c(stableDenom, 16000), InitialPrice: i(0)}))
+	require.Equal(t, i(16000), k.poolKeeper.GetLentLiquidity(ctx, stableDenom))
+
+	// Seize part of the CDP and sell the collateral, raising the seized debt and the penalty
+	k.pricefeedKeeper.SetPrice(ctx, owner, "", "btc", i(7999), i(999999999))
+	k.pricefeedKeeper.SetCurrentPrices(ctx)
+	auctionID, err := k.liquidatorKeeper.SeizeAndStartCollateralAuction(ctx, owner, ftCollateral("btc", 0))
+	require.NoError(t, err)
+	require.NoError(t, k.auctionKeeper.PlaceBid(ctx, auctionID, bidder, c(stableDenom, 6026), c("btc", 1)))
+
+	require.Equal(t, i(693), k.liquidatorKeeper.GetPendingPenalties(ctx))
+
+	// Settle and check the seized debt is repaid to the pool, and the penalty is paid to it on top
+	require.NoError(t, k.liquidatorKeeper.settleDebt(ctx, stableDenom))
+	require.Equal(t, i(0), k.liquidatorKeeper.GetSeizedDebt(ctx).Total)
+	require.Equal(t, i(10667), k.poolKeeper.GetLentLiquidity(ctx, stableDenom))
+	available, err := k.poolKeeper.GetAvailableLiquidity(ctx, stableDenom)
+	require.NoError(t, err)
+	require.Equal(t, i(5333+693), available)
+	require.Equal(t, i(10667), k.cdpKeeper.GetGlobalDebt(ctx))
+	require.True(t, k.cdpKeeper.GetCoins(ctx, k.cdpKeeper.GetLiquidatorAccountAddress()).Empty())
+	require.True(t, k.bankKeeper.GetCoins(ctx, k.cdpKeeper.GetLiquidatorAccountAddress()).Empty())
+	require.Equal(t, i(0), k.liquidatorKeeper.GetPendingPenalties(ctx))
+
+	// Check the penalty raised the share price of the depositors
+	sharePrice, err := k.poolKeeper.GetSharePrice(ctx, stableDenom)
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewDec(16693).QuoInt64(16000), sharePrice)
+}
+
+func TestKeeper_settleDebt_UnraisedPenalty(t *testing.T) {
+	// Setup a CDP drawing all the pool liquidity
+	ctx, k := setupTestKeepers()
+	_, addrs := mock.GeneratePrivKeyAddressPairs(2)
+	owner, bidder := addrs[0], addrs[1]
+	cdp.InitGenesis(ctx, k.cdpKeeper, cdp.DefaultGenesisState())
+	InitGenesis(ctx, k.liquidatorKeeper, DefaultGenesisState())
+	pricefeed.InitGenesis(ctx, k.pricefeedKeeper, pricefeed.GenesisState{
+		Assets: []pricefeed.Asset{
+			{Type: "ft", AssetName: "btc", Description: "a description"},
+			{Type: "ft", AssetName: stableDenom, Description: "the stable coin"},
+		},
+		Oracles: []pricefeed.Oracle{{OracleAddress: owner.String()}},
+	})
+	k.pricefeedKeeper.SetPrice(ctx, owner, "", stableDenom, i(1), i(999999999))
+	k.pricefeedKeeper.SetPrice(ctx, owner, "", "btc", i(8000), i(999999999))
+	k.pricefeedKeeper.SetCurrentPrices(ctx)
+	k.bankKeeper.AddCoins(ctx, owner, cs(c("btc", 100), c(stableDenom, 16000)))
+	k.bankKeeper.AddCoins(ctx, bidder, cs(c(stableDenom, 10000)))
+	require.NoError(t, k.poolKeeper.DepositFundFromAddress(ctx, owner, c(stableDenom, 16000)))
+	require.NoError(t, k.cdpKeeper.ModifyCDP(ctx, owner, ftCollateral("btc", 3), types.Liquidity{Coin: c(stableDenom, 16000), InitialPrice: i(0)}))
+
+	// Seize part of the CDP and bid more than the seized debt, but less than the penalty on top
+	k.pricefeedKeeper.SetPrice(ctx, owner, "", "btc", i(7999), i(999999999))
+	k.pricefeedKeeper.SetCurrentPrices(ctx)
+	auctionID, err := k.liquidatorKeeper.SeizeAndStartCollateralAuction(ctx, owner, ftCollateral("btc", 0))
+	require.NoError(t, err)
+	require.NoError(t, k.auctionKeeper.PlaceBid(ctx, auctionID, bidder, c(stableDenom, 5500), c("btc", 1)))
+
+	// Check the part of the penalty raised is paid while the auction runs, the rest stays pending
+	require.NoError(t, k.liquidatorKeeper.settleDebt(ctx, stableDenom))
+	available, err := k.poolKeeper.GetAvailableLiquidity(ctx, stableDenom)
+	require.NoError(t, err)
+	require.Equal(t, i(5500), available)
+	require.Equal(t, i(693-167), k.liquidatorKeeper.GetPendingPenalties(ctx))
+
+	// Check the penalty not raised is dropped once the auction closes
+	closeCtx := ctx.WithBlockHeight(int64(auction.MaxAuctionDuration))
+	require.NoError(t, k.auctionKeeper.CloseAuction(closeCtx, auctionID))
+	require.NoError(t, k.liquidatorKeeper.settleDebt(closeCtx, stableDenom))
+	require.Equal(t, i(0), k.liquidatorKeeper.GetPendingPenalties(closeCtx))
 }
 
 func TestKeeper_StartDebtAuction(t *testing.T) {
 	// Setup
 	ctx, k := setupTestKeepers()
+	cdp.InitGenesis(ctx, k.cdpKeeper, cdp.DefaultGenesisState())
 	InitGenesis(ctx, k.liquidatorKeeper, DefaultGenesisState())
-	initSDebt := SeizedDebt{i(2000), i(0)}
+	initSDebt := SeizedDebt{i(1500), i(0)}
 	k.liquidatorKeeper.setSeizedDebt(ctx, initSDebt)
 
 	// Execute
@@ -62,32 +168,98 @@ func TestKeeper_StartDebtAuction(t *testing.T) {
 		},
 		k.liquidatorKeeper.GetSeizedDebt(ctx),
 	)
+	a, found := k.auctionKeeper.GetAuction(ctx, auctionID)
+	require.True(t, found)
+	// Check the auction buys the debt auction size of the configured stable coin
+	require.Equal(t, c(stableDenom, 1000), a.(*auction.ReverseAuction).Bid)
+	require.Equal(t, k.cdpKeeper.GetGovDenom(), a.(*auction.ReverseAuction).Lot.Denom)
+
+	// Check there's no second auction without enough seized debt left
+	_, err = k.liquidatorKeeper.StartDebtAuction(ctx)
+	require.Error(t, err)
+}
+
+func TestHandler_StartDebtAuction(t *testing.T) {
+	// Setup seized debt and some stable coin in the module account to settle it with
+	ctx, k := setupTestKeepers()
+	cdpGenesis := cdp.DefaultGenesisState()
+	cdpGenesis.GlobalDebt = i(2000)
+	cdp.InitGenesis(ctx, k.cdpKeeper, cdpGenesis)
+	InitGenesis(ctx, k.liquidatorKeeper, DefaultGenesisState())
+	k.liquidatorKeeper.setSeizedDebt(ctx, SeizedDebt{i(2000), i(0)})
+	k.cdpKeeper.AddCoins(ctx, k.cdpKeeper.GetLiquidatorAccountAddress(), cs(c(stableDenom, 500)))
+	_, addrs := mock.GeneratePrivKeyAddressPairs(1)
+
+	// Execute
+	res := NewHandler(k.liquidatorKeeper)(ctx, MsgStartDebtAuction{Sender: addrs[0]})
+
+	// Check the stable coin settled part of the debt before the rest was sent to auction
+	require.True(t, res.IsOK(), res.Log)
+	require.True(t, k.cdpKeeper.GetCoins(ctx, k.cdpKeeper.GetLiquidatorAccountAddress()).AmountOf(stableDenom).IsZero())
+	require.Equal(t, SeizedDebt{i(1500), i(1000)}, k.liquidatorKeeper.GetSeizedDebt(ctx))
+	require.Equal(t, i(1500), k.cdpKeeper.GetGlobalDebt(ctx))
+}
+
+func TestKeeper_StartSurplusAuction(t *testing.T) {
+	// Setup
+	ctx, k := setupTestKeepers()
+	cdp.InitGenesis(ctx, k.cdpKeeper, cdp.DefaultGenesisState())
+	InitGenesis(ctx, k.liquidatorKeeper, DefaultGenesisState())
+	initSurplus := i(1500)
+	k.liquidatorKeeper.bankKeeper.AddCoins(ctx, k.cdpKeeper.GetLiquidatorAccountAddress(), cs(sdk.NewCoin(stableDenom, initSurplus)))
+	k.liquidatorKeeper.setSeizedDebt(ctx, SeizedDebt{i(0), i(0)})
+
+	// Execute
+	auctionID, err := k.liquidatorKeeper.StartSurplusAuction(ctx)
+
+	// Check
+	require.NoError(t, err)
+	require.Equal(t,
+		initSurplus.Sub(k.liquidatorKeeper.GetParams(ctx).SurplusAuctionSize),
+		k.liquidatorKeeper.bankKeeper.GetCoins(ctx,
+			k.cdpKeeper.GetLiquidatorAccountAddress(),
+		).AmountOf(stableDenom),
+	)
 	_, found := k.auctionKeeper.GetAuction(ctx, auctionID)
 	require.True(t, found)
-	// TODO check auction values are correct?
+
+	// Check the remaining surplus is too small for another auction
+	_, err = k.liquidatorKeeper.StartSurplusAuction(ctx)
+	require.Error(t, err)
 }
 
-// func TestKeeper_StartSurplusAuction(t *testing.T) {
-// 	// Setup
-// 	ctx, k := setupTestKeepers()
-// 	initSurplus := i(2000)
-// 	k.liquidatorKeeper.bankKeeper.AddCoins(ctx, k.cdpKeeper.GetLiquidatorAccountAddress(), cs(sdk.NewCoin(k.cdpKeeper.GetStableDenom(), initSurplus)))
-// 	k.liquidatorKeeper.setSeizedDebt(ctx, i(0))
-
-// 	// Execute
-// 	auctionID, err := k.liquidatorKeeper.StartSurplusAuction(ctx)
-
-// 	// Check
-// 	require.NoError(t, err)
-// 	require.Equal(t,
-// 		initSurplus.Sub(SurplusAuctionSize),
-// 		k.liquidatorKeeper.bankKeeper.GetCoins(ctx,
-// 			k.cdpKeeper.GetLiquidatorAccountAddress(),
-// 		).AmountOf(k.cdpKeeper.GetStableDenom()),
-// 	)
-// 	_, found := k.auctionKeeper.GetAuction(ctx, auctionID)
-// 	require.True(t, found)
-// }
+func TestHandler_StartSurplusAuction(t *testing.T) {
+	// Setup surplus stable coin in the module account
+	ctx, k := setupTestKeepers()
+	cdp.InitGenesis(ctx, k.cdpKeeper, cdp.DefaultGenesisState())
+	InitGenesis(ctx, k.liquidatorKeeper, DefaultGenesisState())
+	k.cdpKeeper.AddCoins(ctx, k.cdpKeeper.GetLiquidatorAccountAddress(), cs(c(stableDenom, 1500)))
+	_, addrs := mock.GeneratePrivKeyAddressPairs(1)
+
+	// Execute
+	res := NewHandler(k.liquidatorKeeper)(ctx, MsgStartSurplusAuction{Sender: addrs[0]})
+
+	// Check the stable coin of the cdp module is sold off
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, cs(c(stableDenom, 500)), k.cdpKeeper.GetCoins(ctx, k.cdpKeeper.GetLiquidatorAccountAddress()))
+	require.Equal(t, cs(c(stableDenom, 1000)), k.bankKeeper.GetCoins(ctx, auction.ModuleAddress))
+}
+
+func TestKeeper_StartSurplusAuction_SeizedDebt(t *testing.T) {
+	// Setup
+	ctx, k := setupTestKeepers()
+	cdp.InitGenesis(ctx, k.cdpKeeper, cdp.DefaultGenesisState())
+	InitGenesis(ctx, k.liquidatorKeeper, DefaultGenesisState())
+	k.liquidatorKeeper.bankKeeper.AddCoins(ctx, k.cdpKeeper.GetLiquidatorAccountAddress(), cs(c(stableDenom, 2000)))
+	k.liquidatorKeeper.setSeizedDebt(ctx, SeizedDebt{i(10), i(0)})
+
+	// Execute
+	_, err := k.liquidatorKeeper.StartSurplusAuction(ctx)
+
+	// Check surplus can't be sold while there is seized debt
+	require.Error(t, err)
+	require.Equal(t, cs(c(stableDenom, 2000)), k.liquidatorKeeper.bankKeeper.GetCoins(ctx, k.cdpKeeper.GetLiquidatorAccountAddress()))
+}
 
 func TestKeeper_partialSeizeCDP(t *testing.T) {
 	// Setup
@@ -97,25 +269,40 @@ func TestKeeper_partialSeizeCDP(t *testing.T) {
 
 	cdp.InitGenesis(ctx, k.cdpKeeper, cdp.DefaultGenesisState())
 	InitGenesis(ctx, k.liquidatorKeeper, DefaultGenesisState())
-	pricefeed.InitGenesis(ctx, k.pricefeedKeeper, pricefeed.GenesisState{Assets: []pricefeed.Asset{{"btc", "a description"}}})
-	k.pricefeedKeeper.SetPrice(ctx, addrs[0], "btc", sdk.MustNewDecFromStr("8000.00"), i(999999999))
+	pricefeed.InitGenesis(ctx, k.pricefeedKeeper, pricefeed.GenesisState{
+		Assets: []pricefeed.Asset{
+			{Type: "ft", AssetName: "btc", Description: "a description"},
+			{Type: "ft", AssetName: stableDenom, Description: "the stable coin"},
+		},
+		Oracles: []pricefeed.Oracle{{OracleAddress: addrs[0].String()}},
+	})
+	k.pricefeedKeeper.SetPrice(ctx, addrs[0], "", stableDenom, i(1), i(999999999))
+	k.pricefeedKeeper.SetPrice(ctx, addrs[0], "", "btc", i(8000), i(999999999))
 	k.pricefeedKeeper.SetCurrentPrices(ctx)
-	k.bankKeeper.AddCoins(ctx, addrs[0], cs(c("btc", 100)))
+	k.bankKeeper.AddCoins(ctx, addrs[0], cs(c("btc", 100), c(stableDenom, 16000)))
+	k.poolKeeper.DepositFundFromAddress(ctx, addrs[0], c(stableDenom, 16000))
 
-	k.cdpKeeper.ModifyCDP(ctx, addrs[0], "btc", i(3), i(16000))
+	k.cdpKeeper.ModifyCDP(ctx, addrs[0], ftCollateral("btc", 3), types.Liquidity{Coin: c(stableDenom, 16000), InitialPrice: i(0)})
+
+	// check the CDP can't be seized while the price is stale, here as the oracle price expired
+	k.pricefeedKeeper.SetPrice(ctx, addrs[0], "", "btc", i(7999), i(ctx.BlockHeight()))
+	k.pricefeedKeeper.SetCurrentPrices(ctx)
+	k.pricefeedKeeper.SetCurrentPrices(ctx.WithBlockHeight(ctx.BlockHeight() + 1))
+	require.True(t, k.pricefeedKeeper.GetCurrentPrice(ctx, "", "btc").Stale)
+	require.Error(t, k.liquidatorKeeper.partialSeizeCDP(ctx, addrs[0], ftCollateral("btc", 0), i(2), i(10000)))
 
-	k.pricefeedKeeper.SetPrice(ctx, addrs[0], "btc", sdk.MustNewDecFromStr("7999.99"), i(999999999))
+	k.pricefeedKeeper.SetPrice(ctx, addrs[0], "", "btc", i(7999), i(999999999))
 	k.pricefeedKeeper.SetCurrentPrices(ctx)
 
 	// Run test function
-	err := k.liquidatorKeeper.partialSeizeCDP(ctx, addrs[0], "btc", i(2), i(10000))
+	err := k.liquidatorKeeper.partialSeizeCDP(ctx, addrs[0], ftCollateral("btc", 0), i(2), i(10000))
 
 	// Check
 	require.NoError(t, err)
-	cdp, found := k.cdpKeeper.GetCDP(ctx, addrs[0], "btc")
+	cdp, found := k.cdpKeeper.GetCDP(ctx, addrs[0], "btc", "")
 	require.True(t, found)
-	require.Equal(t, i(1), cdp.CollateralAmount)
-	require.Equal(t, i(6000), cdp.Debt)
+	require.Equal(t, i(1), cdp.Collateral.Amount)
+	require.Equal(t, i(6000), cdp.Liquidity.Coin.Amount)
 }
 
 func TestKeeper_GetSetSeizedDebt(t *testing.T) {
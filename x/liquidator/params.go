@@ -15,15 +15,15 @@ This approach will be awkward if we ever need to write individual parameters (be
 */
 
 type LiquidatorModuleParams struct {
-	DebtAuctionSize sdk.Int
-	//SurplusAuctionSize sdk.Int
-	CollateralParams []CollateralParams
+	DebtAuctionSize    sdk.Int
+	SurplusAuctionSize sdk.Int // Amount of stable coin to sell off in any one surplus auction. Known as bump in Maker.
+	CollateralParams   []CollateralParams
 }
 
 type CollateralParams struct {
-	Denom       string  // Coin name of collateral type
-	AuctionSize sdk.Int // Max amount of collateral to sell off in any one auction. Known as lump in Maker.
-	// LiquidationPenalty
+	Denom              string  // Coin name of collateral type
+	AuctionSize        sdk.Int // Max amount of collateral to sell off in any one auction. Known as lump in Maker.
+	LiquidationPenalty sdk.Dec // Fraction of the seized debt added on top of it to the max bid of collateral auctions. Known as chop in Maker.
 }
 
 var moduleParamsKey = []byte("LiquidatorModuleParams")
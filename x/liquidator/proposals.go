@@ -0,0 +1,123 @@
+package liquidator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/cdp"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/pricefeed"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+)
+
+// ProposalTypeAddCollateral type of AddCollateralProposal
+const ProposalTypeAddCollateral = "AddCollateral"
+
+var _ gov.Content = AddCollateralProposal{}
+
+func init() {
+	gov.RegisterProposalType(ProposalTypeAddCollateral)
+	gov.RegisterProposalTypeCodec(AddCollateralProposal{}, "liquidator/AddCollateralProposal")
+}
+
+// AddCollateralProposal lists a new collateral type: its asset is priced by the pricefeed, CDPs can be opened with it
+// and they are liquidated in auctions. The cdp and liquidator params must be for the same denom as the asset name.
+type AddCollateralProposal struct {
+	Title            string                 `json:"title"`
+	Description      string                 `json:"description"`
+	Asset            pricefeed.Asset        `json:"asset"`
+	CdpParams        types.CollateralParams `json:"cdp_params"`
+	LiquidatorParams CollateralParams       `json:"liquidator_params"`
+}
+
+// NewAddCollateralProposal creates a new add collateral proposal
+func NewAddCollateralProposal(title, description string, asset pricefeed.Asset, cdpParams types.CollateralParams, liquidatorParams CollateralParams) AddCollateralProposal {
+	return AddCollateralProposal{title, description, asset, cdpParams, liquidatorParams}
+}
+
+// GetTitle returns the title of the proposal
+func (p AddCollateralProposal) GetTitle() string { return p.Title }
+
+// GetDescription returns the description of the proposal
+func (p AddCollateralProposal) GetDescription() string { return p.Description }
+
+// ProposalRoute returns the routing key of the proposal
+func (p AddCollateralProposal) ProposalRoute() string { return ModuleName }
+
+// ProposalType returns the type of the proposal
+func (p AddCollateralProposal) ProposalType() string { return ProposalTypeAddCollateral }
+
+// ValidateBasic runs basic stateless validity checks
+func (p AddCollateralProposal) ValidateBasic() sdk.Error {
+	err := gov.ValidateAbstract(sdk.CodespaceType(ModuleName), p)
+	if err != nil {
+		return err
+	}
+	if p.CdpParams.Denom != p.Asset.AssetName || p.LiquidatorParams.Denom != p.Asset.AssetName {
+		return sdk.ErrInternal("cdp and liquidator params must be for the listed asset")
+	}
+	if len(p.Asset.AssetCode) > 0 {
+		return sdk.ErrInternal("collateral assets are listed by name, their code must be empty")
+	}
+	if err := pricefeed.ValidateAsset(p.Asset); err != nil {
+		return sdk.ErrInternal(err.Error())
+	}
+	if err := cdp.ValidateCollateralParams(p.CdpParams); err != nil {
+		return sdk.ErrInternal(err.Error())
+	}
+	if err := validateCollateralParams(p.LiquidatorParams); err != nil {
+		return sdk.ErrInternal(err.Error())
+	}
+	return nil
+}
+
+// implement fmt.Stringer
+func (p AddCollateralProposal) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`Add Collateral Proposal:
+  Title:               %s
+  Description:         %s
+  Asset:               %s (%s)
+  Liquidation Ratio:   %s
+  Debt Limit:          %s
+  Stability Fee:       %s
+  Debt Floor:          %s
+  Auction Size:        %s
+  Liquidation Penalty: %s`,
+		p.Title, p.Description, p.Asset.AssetName, p.Asset.Type,
+		p.CdpParams.LiquidationRatio, p.CdpParams.DebtLimit, p.CdpParams.StabilityFee, p.CdpParams.DebtFloor,
+		p.LiquidatorParams.AuctionSize, p.LiquidatorParams.LiquidationPenalty,
+	))
+}
+
+// NewCollateralProposalHandler handles the liquidator governance proposals
+func NewCollateralProposalHandler(k Keeper, pricefeed pricefeedKeeper) gov.Handler {
+	return func(ctx sdk.Context, content gov.Content) sdk.Error {
+		switch c := content.(type) {
+		case AddCollateralProposal:
+			return handleAddCollateralProposal(ctx, k, pricefeed, c)
+		default:
+			errMsg := fmt.Sprintf("unrecognized liquidator proposal content type: %T", c)
+			return sdk.ErrUnknownRequest(errMsg)
+		}
+	}
+}
+
+// handleAddCollateralProposal lists the collateral in all the modules, or in none of them if any rejects it
+func handleAddCollateralProposal(ctx sdk.Context, k Keeper, pricefeed pricefeedKeeper, p AddCollateralProposal) sdk.Error {
+	cacheCtx, writeCache := ctx.CacheContext()
+	err := pricefeed.RegisterAsset(cacheCtx, p.Asset)
+	if err != nil {
+		return err
+	}
+	err = k.cdpKeeper.AddCollateralParams(cacheCtx, p.CdpParams)
+	if err != nil {
+		return err
+	}
+	err = k.AddCollateralParams(cacheCtx, p.LiquidatorParams)
+	if err != nil {
+		return err
+	}
+	writeCache()
+	return nil
+}
@@ -0,0 +1,101 @@
+package liquidator
+
+import (
+	"testing"
+
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/cdp"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/pricefeed"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func ethCollateralProposal() AddCollateralProposal {
+	return NewAddCollateralProposal("List eth", "Let CDPs be opened with eth",
+		pricefeed.Asset{Type: "ft", AssetName: "eth", Description: "ether"},
+		types.CollateralParams{Denom: "eth", LiquidationRatio: sdk.MustNewDecFromStr("1.5"), DebtLimit: i(500000), StabilityFee: sdk.MustNewDecFromStr("0.05"), DebtFloor: i(10)},
+		CollateralParams{Denom: "eth", AuctionSize: i(1), LiquidationPenalty: sdk.MustNewDecFromStr("0.13")},
+	)
+}
+
+func TestAddCollateralProposal_ValidateBasic(t *testing.T) {
+	tests := []struct {
+		name       string
+		modify     func(*AddCollateralProposal)
+		expectPass bool
+	}{
+		{"normal", func(*AddCollateralProposal) {}, true},
+		{"emptyTitle", func(p *AddCollateralProposal) { p.Title = "" }, false},
+		{"invalidAssetType", func(p *AddCollateralProposal) { p.Asset.Type = "coin" }, false},
+		{"assetCode", func(p *AddCollateralProposal) { p.Asset.AssetCode = "1" }, false},
+		{"cdpParamsOfOtherDenom", func(p *AddCollateralProposal) { p.CdpParams.Denom = "btc" }, false},
+		{"liquidatorParamsOfOtherDenom", func(p *AddCollateralProposal) { p.LiquidatorParams.Denom = "btc" }, false},
+		{"invalidDenom", func(p *AddCollateralProposal) {
+			p.Asset.AssetName, p.CdpParams.Denom, p.LiquidatorParams.Denom = "ETH", "ETH", "ETH"
+		}, false},
+		{"liquidationRatioOfOne", func(p *AddCollateralProposal) { p.CdpParams.LiquidationRatio = sdk.OneDec() }, false},
+		{"debtFloorAboveLimit", func(p *AddCollateralProposal) { p.CdpParams.DebtFloor = i(500001) }, false},
+		{"zeroAuctionSize", func(p *AddCollateralProposal) { p.LiquidatorParams.AuctionSize = i(0) }, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			proposal := ethCollateralProposal()
+			tc.modify(&proposal)
+			if tc.expectPass {
+				require.Nil(t, proposal.ValidateBasic())
+			} else {
+				require.NotNil(t, proposal.ValidateBasic())
+			}
+		})
+	}
+}
+
+func TestAddCollateralProposalHandler(t *testing.T) {
+	// Setup
+	ctx, k := setupTestKeepers()
+	cdp.InitGenesis(ctx, k.cdpKeeper, cdp.DefaultGenesisState())
+	InitGenesis(ctx, k.liquidatorKeeper, DefaultGenesisState())
+	pricefeed.InitGenesis(ctx, k.pricefeedKeeper, pricefeed.DefaultGenesisState())
+	handler := NewCollateralProposalHandler(k.liquidatorKeeper, k.pricefeedKeeper)
+
+	// Run test function
+	proposal := ethCollateralProposal()
+	require.Nil(t, handler(ctx, proposal))
+
+	// Check the collateral is listed in all the modules
+	_, found := k.pricefeedKeeper.GetAsset(ctx, "", "eth")
+	require.True(t, found)
+	require.Equal(t, proposal.CdpParams, k.cdpKeeper.GetParams(ctx).GetCollateralParams("eth"))
+	require.Equal(t, proposal.LiquidatorParams, k.liquidatorKeeper.GetParams(ctx).GetCollateralParams("eth"))
+
+	// Check it can't be listed twice
+	require.NotNil(t, handler(ctx, proposal))
+}
+
+func TestAddCollateralProposalHandler_Atomic(t *testing.T) {
+	// Setup, with the liquidator params of eth already set
+	ctx, k := setupTestKeepers()
+	cdp.InitGenesis(ctx, k.cdpKeeper, cdp.DefaultGenesisState())
+	genesis := DefaultGenesisState()
+	genesis.LiquidatorModuleParams.CollateralParams = append(genesis.LiquidatorModuleParams.CollateralParams, ethCollateralProposal().LiquidatorParams)
+	InitGenesis(ctx, k.liquidatorKeeper, genesis)
+	pricefeed.InitGenesis(ctx, k.pricefeedKeeper, pricefeed.DefaultGenesisState())
+	handler := NewCollateralProposalHandler(k.liquidatorKeeper, k.pricefeedKeeper)
+
+	// Run test function
+	require.NotNil(t, handler(ctx, ethCollateralProposal()))
+
+	// Check neither the pricefeed nor the cdp module listed the collateral
+	_, found := k.pricefeedKeeper.GetAsset(ctx, "", "eth")
+	require.False(t, found)
+	require.False(t, k.cdpKeeper.GetParams(ctx).IsCollateralPresent("eth"))
+
+	// Check the debt limit is checked against the global one
+	proposal := ethCollateralProposal()
+	proposal.Asset.AssetName, proposal.CdpParams.Denom, proposal.LiquidatorParams.Denom = "dot", "dot", "dot"
+	proposal.CdpParams.DebtLimit = k.cdpKeeper.GetParams(ctx).GlobalDebtLimit.AddRaw(1)
+	require.Nil(t, proposal.ValidateBasic())
+	require.NotNil(t, handler(ctx, proposal))
+	_, found = k.pricefeedKeeper.GetAsset(ctx, "", "dot")
+	require.False(t, found)
+}
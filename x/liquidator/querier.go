@@ -1,6 +1,7 @@
 package liquidator
 
 import (
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	abci "github.com/tendermint/tendermint/abci/types"
@@ -8,6 +9,7 @@ import (
 
 const (
 	QueryGetOutstandingDebt = "outstanding_debt" // Get the outstanding seized debt
+	QueryGetModuleAccount   = "module-account"   // Get the liquidator module account
 )
 
 func NewQuerier(keeper Keeper) sdk.Querier {
@@ -15,6 +17,8 @@ func NewQuerier(keeper Keeper) sdk.Querier {
 		switch path[0] {
 		case QueryGetOutstandingDebt:
 			return queryGetOutstandingDebt(ctx, path[1:], req, keeper)
+		case QueryGetModuleAccount:
+			return queryGetModuleAccount(ctx, path[1:], req, keeper)
 		// case QueryGetSurplus:
 		// 	return queryGetSurplus()
 		default:
@@ -28,7 +32,7 @@ func queryGetOutstandingDebt(ctx sdk.Context, path []string, req abci.RequestQue
 	stableCoins := keeper.bankKeeper.GetCoins(
 		ctx,
 		keeper.cdpKeeper.GetLiquidatorAccountAddress(),
-	).AmountOf(keeper.cdpKeeper.GetStableDenom())
+	).AmountOf(keeper.cdpKeeper.GetStableDenom(ctx))
 	seizedDebt := keeper.GetSeizedDebt(ctx)
 	settleAmount := sdk.MinInt(seizedDebt.Total, stableCoins)
 	seizedDebt, err := seizedDebt.Settle(settleAmount)
@@ -46,3 +50,16 @@ func queryGetOutstandingDebt(ctx sdk.Context, path []string, req abci.RequestQue
 	}
 	return bz, nil
 }
+
+func queryGetModuleAccount(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	// The liquidator account holds the seized collateral and the stable coins raised or collected as fees
+	address := keeper.cdpKeeper.GetLiquidatorAccountAddress()
+	account := types.ModuleAccount{Address: address, Coins: keeper.bankKeeper.GetCoins(ctx, address)}
+
+	// Encode and return
+	bz, err := codec.MarshalJSONIndent(keeper.cdc, account)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+	return bz, nil
+}
@@ -13,7 +13,9 @@ import (
 
 	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/auction"
 	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/cdp"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/pool"
 	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/pricefeed"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
 )
 
 // Avoid cluttering test cases with long function name
@@ -21,11 +23,19 @@ func i(in int64) sdk.Int                    { return sdk.NewInt(in) }
 func c(denom string, amount int64) sdk.Coin { return sdk.NewInt64Coin(denom, amount) }
 func cs(coins ...sdk.Coin) sdk.Coins        { return sdk.NewCoins(coins...) }
 
+// stableDenom is the denom of the liquidity given out by the test CDPs
+const stableDenom = "uatom"
+
+func ftCollateral(denom string, amount int64) types.Collateral {
+	return types.Collateral{Token: cdp.BaseFT{TokenName: denom}, Amount: i(amount), InitialPrice: i(0)}
+}
+
 type keepers struct {
 	paramsKeeper     params.Keeper
 	accountKeeper    auth.AccountKeeper
 	bankKeeper       bank.Keeper
 	pricefeedKeeper  pricefeed.Keeper
+	poolKeeper       pool.Keeper
 	auctionKeeper    auction.Keeper
 	cdpKeeper        cdp.Keeper
 	liquidatorKeeper Keeper
@@ -38,6 +48,7 @@ func setupTestKeepers() (sdk.Context, keepers) {
 	tkeyParams := sdk.NewTransientStoreKey(params.TStoreKey)
 	keyAcc := sdk.NewKVStoreKey(auth.StoreKey)
 	keyPriceFeed := sdk.NewKVStoreKey(pricefeed.StoreKey)
+	keyPool := sdk.NewKVStoreKey("pool")
 	keyCDP := sdk.NewKVStoreKey("cdp")
 	keyAuction := sdk.NewKVStoreKey("auction")
 	keyLiquidator := sdk.NewKVStoreKey("liquidator")
@@ -48,6 +59,7 @@ func setupTestKeepers() (sdk.Context, keepers) {
 	ms.MountStoreWithDB(tkeyParams, sdk.StoreTypeTransient, db)
 	ms.MountStoreWithDB(keyAcc, sdk.StoreTypeIAVL, db)
 	ms.MountStoreWithDB(keyPriceFeed, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyPool, sdk.StoreTypeIAVL, db)
 	ms.MountStoreWithDB(keyCDP, sdk.StoreTypeIAVL, db)
 	ms.MountStoreWithDB(keyAuction, sdk.StoreTypeIAVL, db)
 	ms.MountStoreWithDB(keyLiquidator, sdk.StoreTypeIAVL, db)
@@ -72,13 +84,16 @@ func setupTestKeepers() (sdk.Context, keepers) {
 		paramsKeeper.Subspace(bank.DefaultParamspace),
 		bank.DefaultCodespace,
 	)
-	pricefeedKeeper := pricefeed.NewKeeper(keyPriceFeed, cdc, pricefeed.DefaultCodespace, nil)
-	cdpKeeper := cdp.NewKeeper(
+	var cdpKeeper cdp.Keeper
+	pricefeedKeeper := pricefeed.NewKeeper(keyPriceFeed, cdc, paramsKeeper.Subspace("pricefeedSubspace"), pricefeed.DefaultCodespace, &cdpKeeper)
+	poolKeeper := pool.NewKeeper(keyPool, bankKeeper, cdc)
+	cdpKeeper = cdp.NewKeeper(
 		cdc,
 		keyCDP,
 		paramsKeeper.Subspace("cdpSubspace"),
 		pricefeedKeeper,
 		bankKeeper,
+		poolKeeper,
 	)
 	auctionKeeper := auction.NewKeeper(cdc, cdpKeeper, keyAuction) // Note: cdp keeper stands in for bank keeper
 	liquidatorKeeper := NewKeeper(
@@ -87,7 +102,7 @@ func setupTestKeepers() (sdk.Context, keepers) {
 		paramsKeeper.Subspace("liquidatorSubspace"),
 		cdpKeeper,
 		auctionKeeper,
-		bankKeeper,
+		cdpKeeper,
 	) // Note: cdp keeper stands in for bank keeper
 
 	// Create context
@@ -98,6 +113,7 @@ func setupTestKeepers() (sdk.Context, keepers) {
 		accountKeeper,
 		bankKeeper,
 		pricefeedKeeper,
+		poolKeeper,
 		auctionKeeper,
 		cdpKeeper,
 		liquidatorKeeper,
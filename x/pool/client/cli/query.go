@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/pool"
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
 
 	"github.com/cosmos/cosmos-sdk/client/context"
 	"github.com/cosmos/cosmos-sdk/codec"
@@ -12,10 +13,10 @@ import (
 
 func GetCmdGetFunds(queryRoute string, cdc *codec.Codec) *cobra.Command {
 	return &cobra.Command{
-		Use:   "get-funds [address]",
+		Use:   "get-funds [address] [denom]",
 		Short: "get the funds for a specified account",
-		Long:  "Get the current funds value for the given account address.",
-		Args:  cobra.ExactArgs(1),
+		Long:  "Get the current funds value for the given account address, in all the denoms it deposited or only in the given one.",
+		Args:  cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cliCtx := context.NewCLIContext().WithCodec(cdc)
 
@@ -25,8 +26,14 @@ func GetCmdGetFunds(queryRoute string, cdc *codec.Codec) *cobra.Command {
 				return err
 			}
 
+			var denom string
+			if len(args) > 1 {
+				denom = args[1]
+			}
+
 			bz, err := cdc.MarshalJSON(pool.QueryFundsParams{
 				Owner: ownerAddress,
+				Denom: denom,
 			})
 			if err != nil {
 				return err
@@ -39,7 +46,7 @@ func GetCmdGetFunds(queryRoute string, cdc *codec.Codec) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			var funds sdk.Coin
+			var funds sdk.Coins
 			cdc.MustUnmarshalJSON(res, &funds)
 			return cliCtx.PrintOutput(funds)
 		},
@@ -66,3 +73,134 @@ func GetCmdGetAllFunds(queryRoute string, cdc *codec.Codec) *cobra.Command {
 		},
 	}
 }
+
+func GetCmdGetShares(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "shares [address] [denom]",
+		Short: "get the pool shares of a specified account",
+		Long:  "Get the pool shares owned by the given account address, in all the denoms it deposited or only in the given one. Their value is given by get-funds.",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			// Prepare params for querier
+			ownerAddress, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+
+			var denom string
+			if len(args) > 1 {
+				denom = args[1]
+			}
+
+			bz, err := cdc.MarshalJSON(pool.QueryFundsParams{
+				Owner: ownerAddress,
+				Denom: denom,
+			})
+			if err != nil {
+				return err
+			}
+
+			res, err := cliCtx.QueryWithData(
+				fmt.Sprintf("custom/%s/%s", queryRoute, pool.QueryShares),
+				bz,
+			)
+			if err != nil {
+				return err
+			}
+			var shares sdk.Coins
+			cdc.MustUnmarshalJSON(res, &shares)
+			return cliCtx.PrintOutput(shares)
+		},
+	}
+}
+
+func GetCmdGetSharePrice(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "share-price [denom]",
+		Short: "get the value of a pool share",
+		Long:  "Get the amount of the given denom that one pool share can be withdrawn for.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			// Prepare params for querier
+			bz, err := cdc.MarshalJSON(pool.QuerySharePriceParams{
+				Denom: args[0],
+			})
+			if err != nil {
+				return err
+			}
+
+			res, err := cliCtx.QueryWithData(
+				fmt.Sprintf("custom/%s/%s", queryRoute, pool.QuerySharePrice),
+				bz,
+			)
+			if err != nil {
+				return err
+			}
+			var price sdk.Dec
+			cdc.MustUnmarshalJSON(res, &price)
+			return cliCtx.PrintOutput(price)
+		},
+	}
+}
+
+func GetCmdGetModuleAccount(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "module-account",
+		Short: "get the pool module account",
+		Long:  "Get the address of the module account holding the pool funds, and its balance.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			res, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", queryRoute, pool.QueryModuleAccount), nil)
+			if err != nil {
+				return err
+			}
+
+			var account types.ModuleAccount
+			cdc.MustUnmarshalJSON(res, &account)
+			return cliCtx.PrintOutput(account)
+		},
+	}
+}
+
+func GetCmdGetWithdrawal(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "withdrawal [address] [denom]",
+		Short: "get the queued withdrawal of a specified account",
+		Long:  "Get the amount of the given denom the given account address is waiting to withdraw, and its position in the withdrawal queue.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			// Prepare params for querier
+			ownerAddress, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+
+			bz, err := cdc.MarshalJSON(pool.QueryFundsParams{
+				Owner: ownerAddress,
+				Denom: args[1],
+			})
+			if err != nil {
+				return err
+			}
+
+			res, err := cliCtx.QueryWithData(
+				fmt.Sprintf("custom/%s/%s", queryRoute, pool.QueryWithdrawal),
+				bz,
+			)
+			if err != nil {
+				return err
+			}
+			var withdrawal pool.QueuedWithdrawal
+			cdc.MustUnmarshalJSON(res, &withdrawal)
+			return cliCtx.PrintOutput(withdrawal)
+		},
+	}
+}
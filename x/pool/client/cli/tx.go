@@ -48,7 +48,7 @@ func GetCmdWithdrawFunds(cdc *codec.Codec) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "withdraw [amount]",
 		Short: "withdraw given funds from the pool into the signer address",
-		Long:  ``,
+		Long:  "Withdraw the given funds from the pool into the signer address. If the pool lacks the liquidity, the withdrawal is queued and paid as liquidity returns to the pool.",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Setup
@@ -77,3 +77,29 @@ func GetCmdWithdrawFunds(cdc *codec.Codec) *cobra.Command {
 	}
 	return cmd
 }
+
+func GetCmdCancelWithdrawal(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cancel-withdrawal [denom]",
+		Short: "cancel the queued withdrawal of the given denom of the signer address",
+		Long:  ``,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Setup
+			txBldr := authtxb.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().
+				WithCodec(cdc).
+				WithAccountDecoder(cdc)
+
+			// Prepare and send message
+			msg := pool.NewMsgCancelWithdrawal(cliCtx.GetFromAddress(), args[0])
+			err := msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+	return cmd
+}
@@ -28,6 +28,10 @@ func (mc ModuleClient) GetQueryCmd() *cobra.Command {
 	queryCmd.AddCommand(client.GetCommands(
 		cli.GetCmdGetFunds(mc.storeKey, mc.cdc),
 		cli.GetCmdGetAllFunds(mc.storeKey, mc.cdc),
+		cli.GetCmdGetShares(mc.storeKey, mc.cdc),
+		cli.GetCmdGetSharePrice(mc.storeKey, mc.cdc),
+		cli.GetCmdGetModuleAccount(mc.storeKey, mc.cdc),
+		cli.GetCmdGetWithdrawal(mc.storeKey, mc.cdc),
 	)...)
 
 	return queryCmd
@@ -43,6 +47,7 @@ func (mc ModuleClient) GetTxCmd() *cobra.Command {
 	txCmd.AddCommand(client.PostCommands(
 		cli.GetCmdDepositFunds(mc.cdc),
 		cli.GetCmdWithdrawFunds(mc.cdc),
+		cli.GetCmdCancelWithdrawal(mc.cdc),
 	)...)
 
 	return txCmd
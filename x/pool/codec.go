@@ -16,4 +16,5 @@ func init() {
 func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgDepositFund{}, "pool/MsgDepositFund", nil)
 	cdc.RegisterConcrete(MsgWithdrawFund{}, "pool/MsgWithdrawFund", nil)
+	cdc.RegisterConcrete(MsgCancelWithdrawal{}, "pool/MsgCancelWithdrawal", nil)
 }
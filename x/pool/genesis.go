@@ -0,0 +1,165 @@
+package pool
+
+import (
+	"bytes"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// GenesisState is the state that must be provided at genesis.
+// The funds themselves are held by the pool module account, which is part of the auth genesis.
+type GenesisState struct {
+	Deposits         []Deposit           `json:"deposits"`
+	TotalShares      sdk.Coins           `json:"total_shares"`
+	Lent             sdk.Coins           `json:"lent"`
+	Withdrawals      []WithdrawalRequest `json:"withdrawals"` // queued, in order within each denom
+	NextWithdrawalID uint64              `json:"next_withdrawal_id"`
+}
+
+// Deposit is the pool shares owned by a depositor
+type Deposit struct {
+	Depositor sdk.AccAddress `json:"depositor"`
+	Shares    sdk.Coins      `json:"shares"`
+}
+
+// DefaultGenesisState returns a default genesis state
+func DefaultGenesisState() GenesisState {
+	return GenesisState{
+		Deposits:    []Deposit{},
+		TotalShares: sdk.NewCoins(),
+		Lent:        sdk.NewCoins(),
+		Withdrawals: []WithdrawalRequest{},
+	}
+}
+
+// InitGenesis sets the genesis state in the keeper.
+func InitGenesis(ctx sdk.Context, keeper Keeper, data GenesisState) {
+	for _, deposit := range data.Deposits {
+		for _, shares := range deposit.Shares {
+			keeper.setAccountShares(ctx, deposit.Depositor, shares)
+		}
+	}
+	for _, totalShares := range data.TotalShares {
+		keeper.setTotalShares(ctx, totalShares.Denom, totalShares.Amount)
+	}
+	for _, lent := range data.Lent {
+		keeper.setLentLiquidity(ctx, lent.Denom, lent.Amount)
+	}
+	for _, request := range data.Withdrawals {
+		keeper.setWithdrawalRequest(ctx, request)
+	}
+	keeper.setNextWithdrawalID(ctx, data.NextWithdrawalID)
+	keeper.setDepositsMigrated(ctx)      // a new store has no legacy deposits
+	keeper.setModuleAccountMigrated(ctx) // nor funds at the legacy address
+}
+
+// ExportGenesis returns a GenesisState for a given context and keeper.
+func ExportGenesis(ctx sdk.Context, keeper Keeper) GenesisState {
+	store := ctx.KVStore(keeper.fundsStoreKey)
+
+	// Shares are stored by account, so the shares of each account are next to each other
+	deposits := []Deposit{}
+	iter := sdk.KVStorePrefixIterator(store, sharesKeyPrefix)
+	for ; iter.Valid(); iter.Next() {
+		depositor := sdk.AccAddress(iter.Key()[len(sharesKeyPrefix) : len(sharesKeyPrefix)+sdk.AddrLen])
+		var shares sdk.Coin
+		keeper.cdc.MustUnmarshalBinaryBare(iter.Value(), &shares)
+		if last := len(deposits) - 1; last >= 0 && deposits[last].Depositor.Equals(depositor) {
+			deposits[last].Shares = deposits[last].Shares.Add(sdk.NewCoins(shares))
+			continue
+		}
+		deposits = append(deposits, Deposit{Depositor: depositor, Shares: sdk.NewCoins(shares)})
+	}
+	iter.Close()
+
+	totalShares := sdk.NewCoins()
+	iter = sdk.KVStorePrefixIterator(store, totalSharesKeyPrefix)
+	for ; iter.Valid(); iter.Next() {
+		var amount sdk.Int
+		keeper.cdc.MustUnmarshalBinaryBare(iter.Value(), &amount)
+		totalShares = totalShares.Add(sdk.NewCoins(sdk.NewCoin(string(iter.Key()[len(totalSharesKeyPrefix):]), amount)))
+	}
+	iter.Close()
+
+	lent := sdk.NewCoins()
+	iter = sdk.KVStorePrefixIterator(store, lentKeyPrefix)
+	for ; iter.Valid(); iter.Next() {
+		var amount sdk.Int
+		keeper.cdc.MustUnmarshalBinaryBare(iter.Value(), &amount)
+		lent = lent.Add(sdk.NewCoins(sdk.NewCoin(string(iter.Key()[len(lentKeyPrefix):]), amount)))
+	}
+	iter.Close()
+
+	// The queue keys are ordered by denom then ID, so each queue is exported in order
+	withdrawals := []WithdrawalRequest{}
+	iter = sdk.KVStorePrefixIterator(store, withdrawalQueueKeyPrefix)
+	for ; iter.Valid(); iter.Next() {
+		var request WithdrawalRequest
+		keeper.cdc.MustUnmarshalBinaryBare(iter.Value(), &request)
+		withdrawals = append(withdrawals, request)
+	}
+	iter.Close()
+
+	return GenesisState{
+		Deposits:         deposits,
+		TotalShares:      totalShares,
+		Lent:             lent,
+		Withdrawals:      withdrawals,
+		NextWithdrawalID: keeper.getNextWithdrawalID(ctx),
+	}
+}
+
+// ValidateGenesis performs basic validation of genesis data returning an error for any failed validation criteria.
+func ValidateGenesis(data GenesisState) error {
+	// Sum up the shares of the depositors, checking each depositor is listed once
+	depositorsShares := sdk.NewCoins()
+	depositors := make(map[string]sdk.Coins)
+	for _, deposit := range data.Deposits {
+		if deposit.Depositor.Empty() {
+			return fmt.Errorf("deposit must have a depositor")
+		}
+		if _, ok := depositors[deposit.Depositor.String()]; ok {
+			return fmt.Errorf("duplicate deposit of %s", deposit.Depositor)
+		}
+		depositors[deposit.Depositor.String()] = deposit.Shares
+		if !deposit.Shares.IsValid() {
+			return fmt.Errorf("invalid shares %s of %s", deposit.Shares, deposit.Depositor)
+		}
+		depositorsShares = depositorsShares.Add(deposit.Shares)
+	}
+	if !data.TotalShares.IsValid() {
+		return fmt.Errorf("invalid total shares %s", data.TotalShares)
+	}
+	if !data.TotalShares.IsEqual(depositorsShares) {
+		return fmt.Errorf("total shares are %s, but depositors own %s", data.TotalShares, depositorsShares)
+	}
+	if !data.Lent.IsValid() {
+		return fmt.Errorf("invalid lent liquidity %s", data.Lent)
+	}
+
+	// Check each queued withdrawal is unique and is of a denom its depositor has shares of
+	ids := make(map[uint64]bool)
+	requests := make(map[string]bool)
+	for _, request := range data.Withdrawals {
+		if ids[request.ID] {
+			return fmt.Errorf("duplicate withdrawal id %d", request.ID)
+		}
+		ids[request.ID] = true
+		if request.ID >= data.NextWithdrawalID {
+			return fmt.Errorf("withdrawal id %d is not below the next withdrawal id %d", request.ID, data.NextWithdrawalID)
+		}
+		if !(sdk.Coins{request.Amount}).IsValid() {
+			return fmt.Errorf("invalid withdrawal amount %s of %s", request.Amount, request.Depositor)
+		}
+		key := string(bytes.Join([][]byte{request.Depositor, []byte(request.Amount.Denom)}, nil))
+		if requests[key] {
+			return fmt.Errorf("duplicate %s withdrawal of %s", request.Amount.Denom, request.Depositor)
+		}
+		requests[key] = true
+		if !depositors[request.Depositor.String()].AmountOf(request.Amount.Denom).IsPositive() {
+			return fmt.Errorf("%s withdrawal of %s has no deposit", request.Amount.Denom, request.Depositor)
+		}
+	}
+	return nil
+}
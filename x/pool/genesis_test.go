@@ -0,0 +1,98 @@
+package pool
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateGenesis(t *testing.T) {
+	_, addrs := mock.GeneratePrivKeyAddressPairs(2)
+	validGenesis := func() GenesisState {
+		return GenesisState{
+			Deposits: []Deposit{
+				{addrs[0], cs(c(stableDenom, 300), c("xrp", 10))},
+				{addrs[1], cs(c(stableDenom, 200))},
+			},
+			TotalShares:      cs(c(stableDenom, 500), c("xrp", 10)),
+			Lent:             cs(c(stableDenom, 400)),
+			Withdrawals:      []WithdrawalRequest{{0, addrs[1], c(stableDenom, 100)}, {2, addrs[0], c(stableDenom, 50)}},
+			NextWithdrawalID: 3,
+		}
+	}
+	tests := []struct {
+		name       string
+		modify     func(*GenesisState)
+		expectPass bool
+	}{
+		{"valid", func(*GenesisState) {}, true},
+		{"default", func(gs *GenesisState) { *gs = DefaultGenesisState() }, true},
+		{"emptyDepositor", func(gs *GenesisState) { gs.Deposits[0].Depositor = nil }, false},
+		{"duplicateDeposit", func(gs *GenesisState) { gs.Deposits[1].Depositor = addrs[0] }, false},
+		{"zeroShares", func(gs *GenesisState) { gs.Deposits[1].Shares = sdk.Coins{c(stableDenom, 0)} }, false},
+		{"totalSharesMismatch", func(gs *GenesisState) { gs.TotalShares = cs(c(stableDenom, 501), c("xrp", 10)) }, false},
+		{"invalidLent", func(gs *GenesisState) { gs.Lent = sdk.Coins{c(stableDenom, 0)} }, false},
+		{"duplicateWithdrawalID", func(gs *GenesisState) { gs.Withdrawals[1].ID = 0 }, false},
+		{"withdrawalIDNotBelowNext", func(gs *GenesisState) { gs.NextWithdrawalID = 2 }, false},
+		{"zeroWithdrawal", func(gs *GenesisState) { gs.Withdrawals[0].Amount = c(stableDenom, 0) }, false},
+		{"duplicateWithdrawal", func(gs *GenesisState) { gs.Withdrawals[1].Depositor = addrs[1] }, false},
+		{"withdrawalWithoutDeposit", func(gs *GenesisState) { gs.Withdrawals[0].Amount = c("xrp", 5) }, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			genesis := validGenesis()
+			tc.modify(&genesis)
+			err := ValidateGenesis(genesis)
+			if tc.expectPass {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestExportImportGenesis(t *testing.T) {
+	// Setup a pool with deposits of several denoms, lent liquidity and queued withdrawals
+	ctx, k, bk := setupTestKeeper()
+	_, addrs := mock.GeneratePrivKeyAddressPairs(4)
+	depositor, other, third, borrower := addrs[0], addrs[1], addrs[2], addrs[3]
+	for _, addr := range []sdk.AccAddress{depositor, other, third} {
+		bk.AddCoins(ctx, addr, cs(c(stableDenom, 1000), c("xrp", 1000)))
+	}
+	require.NoError(t, k.DepositFundFromAddress(ctx, depositor, c(stableDenom, 500)))
+	require.NoError(t, k.DepositFundFromAddress(ctx, depositor, c("xrp", 100)))
+	require.NoError(t, k.DepositFundFromAddress(ctx, other, c(stableDenom, 300)))
+	require.NoError(t, k.DepositFundFromAddress(ctx, third, c(stableDenom, 200)))
+	require.NoError(t, k.DrawLiquidity(ctx, borrower, c(stableDenom, 900)))
+	for _, addr := range []sdk.AccAddress{other, depositor, third} {
+		queued, err := k.RequestWithdrawal(ctx, addr, c(stableDenom, 150))
+		require.NoError(t, err)
+		require.True(t, queued)
+	}
+	require.NoError(t, k.CancelWithdrawal(ctx, depositor, stableDenom))
+
+	// Export, check the result is valid and import it into a new store
+	genesis := ExportGenesis(ctx, k)
+	require.NoError(t, ValidateGenesis(genesis))
+	require.Equal(t, cs(c(stableDenom, 1000), c("xrp", 100)), genesis.TotalShares)
+	require.Equal(t, cs(c(stableDenom, 900)), genesis.Lent)
+	require.Len(t, genesis.Deposits, 3)
+	require.Len(t, genesis.Withdrawals, 2)
+	newCtx, newK, _ := setupTestKeeper()
+	InitGenesis(newCtx, newK, genesis)
+
+	// Check the state round trips, along with the queue positions and totals kept next to the requests
+	require.Equal(t, genesis, ExportGenesis(newCtx, newK))
+	require.Equal(t, cs(c(stableDenom, 500), c("xrp", 100)), newK.GetAccountShares(newCtx, depositor))
+	require.Equal(t, i(1000), newK.GetTotalShares(newCtx, stableDenom))
+	require.Equal(t, i(900), newK.GetLentLiquidity(newCtx, stableDenom))
+	require.Equal(t, i(300), newK.GetQueuedWithdrawalsTotal(newCtx, stableDenom))
+	withdrawal, found := newK.GetQueuedWithdrawal(newCtx, third, stableDenom)
+	require.True(t, found)
+	require.Equal(t, int64(2), withdrawal.Position)
+	require.Equal(t, k.getNextWithdrawalID(ctx), newK.getNextWithdrawalID(newCtx))
+	require.Equal(t, depositsVersion, newK.getDepositsVersion(newCtx))
+}
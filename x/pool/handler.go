@@ -14,6 +14,8 @@ func NewHandler(keeper Keeper) sdk.Handler {
 			return handleMsgDepositFund(ctx, keeper, msg)
 		case MsgWithdrawFund:
 			return handleMsgWithdrawFund(ctx, keeper, msg)
+		case MsgCancelWithdrawal:
+			return handleMsgCancelWithdrawal(ctx, keeper, msg)
 		default:
 			errMsg := fmt.Sprintf("Unrecognized pool msg type: %T", msg)
 			return sdk.ErrUnknownRequest(errMsg).Result()
@@ -32,25 +34,34 @@ func handleMsgDepositFund(ctx sdk.Context, keeper Keeper, msg MsgDepositFund) sd
 	return sdk.Result{}
 }
 
-// handles the message that allows a user to withdraw funds from the pool
+// handles the message that allows a user to withdraw funds from the pool, or to queue the withdrawal
 func handleMsgWithdrawFund(ctx sdk.Context, keeper Keeper, msg MsgWithdrawFund) sdk.Result {
 
-	err := keeper.WithdrawFundToAddress(ctx, msg.Amount, msg.Sender)
+	queued, err := keeper.RequestWithdrawal(ctx, msg.Sender, msg.Amount)
 	if err != nil {
 		return err.Result()
 	}
 
+	if queued {
+		return sdk.Result{Log: "withdrawal queued until the pool has enough liquidity"}
+	}
 	return sdk.Result{}
 }
 
-// EndBlocker distributes the rewards
-func EndBlocker(ctx sdk.Context, k Keeper) sdk.Tags {
+// handles the message that allows a user to cancel a queued withdrawal
+func handleMsgCancelWithdrawal(ctx sdk.Context, keeper Keeper, msg MsgCancelWithdrawal) sdk.Result {
 
-	// Running in the end blocker ensures that rewards will update at most once per block
-	err := k.DistributeReward(ctx)
+	err := keeper.CancelWithdrawal(ctx, msg.Sender, msg.Denom)
 	if err != nil {
-		panic(err)
+		return err.Result()
 	}
 
-	return sdk.Tags{}
+	return sdk.Result{}
+}
+
+// EndBlocker runs at the end of every block
+// Rewards don't need to be distributed, they raise the share price as soon as they reach the pool account.
+// Queued withdrawals are paid with the liquidity repaid to the pool.
+func EndBlocker(ctx sdk.Context, k Keeper) sdk.Tags {
+	return k.ProcessWithdrawalQueue(ctx)
 }
@@ -0,0 +1,33 @@
+package pool
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// RegisterInvariants registers the pool module invariants
+func RegisterInvariants(ir sdk.InvariantRouter, k Keeper) {
+	ir.RegisterRoute(ModuleName, "shares",
+		SharesInvariant(k))
+}
+
+// SharesInvariant checks that the shares issued for each denom equal the sum of the depositors shares, and that the pool holds funds to back them
+func SharesInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) error {
+		for _, shares := range k.getDepositorsShares(ctx) {
+			totalShares := k.GetTotalShares(ctx, shares.Denom)
+			if !totalShares.Equal(shares.Amount) {
+				return fmt.Errorf("%s shares issued are %s, but depositors own %s", shares.Denom, totalShares, shares.Amount)
+			}
+			value, err := k.getPoolValue(ctx, shares.Denom)
+			if err != nil {
+				return err
+			}
+			if shares.Amount.IsPositive() && !value.IsPositive() {
+				return fmt.Errorf("pool holds no %s to back its shares", shares.Denom)
+			}
+		}
+		return nil
+	}
+}
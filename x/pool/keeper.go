@@ -1,15 +1,28 @@
 package pool
 
 import (
+	"bytes"
 	"fmt"
+
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/bank"
 )
 
+/*
+How the pool works:
+ - Depositors own shares of the pool, one share balance for each deposited coin denom, like the tokens of a liquidity pool.
+ - The value of the pool for a denom is the pool account balance of that denom plus the liquidity lent out to CDPs, the share price is that value divided by the issued shares.
+ - CDP owners draw their liquidity from the pool account and repay it back there, so only the pool account balance can be drawn or withdrawn.
+ - Deposits mint shares at the current share price, withdrawals burn shares at the current share price.
+ - Anything else sent to the pool account (eg rewards for the depositors) increases the value of the pool without minting shares, raising the share price for all depositors.
+ - That's how depositors earn interest: CDP owners pay part of their stability fees to the pool, the rest goes to the liquidator.
+*/
+
 // Keeper cdp Keeper
 type Keeper struct {
-	fundsStoreKey sdk.StoreKey // key for the keystore that contains the pairs account -> deposited funds
+	fundsStoreKey sdk.StoreKey // key for the keystore that contains the pairs account -> deposited shares
 	bankKeeper    bank.Keeper
 	cdc           *codec.Codec
 }
@@ -22,86 +35,91 @@ func NewKeeper(fundsStoreKey sdk.StoreKey, bankKeeper bank.Keeper, cdc *codec.Co
 	}
 }
 
-// DepositFundFromAddress allows to take the given amount from the account balance and store it into the pool
+// ModuleAddress is the address of the module account holding the pool funds
+var ModuleAddress = types.NewModuleAddress(ModuleName)
+
+// DepositFundFromAddress allows to take the given amount from the account balance and store it into the pool, minting shares for it at the current share price
 func (k Keeper) DepositFundFromAddress(ctx sdk.Context, account sdk.AccAddress, amount sdk.Coin) sdk.Error {
 
-	// remove the coins from the sender
-	_, err := k.bankKeeper.SubtractCoins(ctx, account, []sdk.Coin{amount})
+	// get any existing shares of the deposited denom for the user
+	shares := k.GetAccountDenomShares(ctx, account, amount.Denom)
+
+	// compute the shares to mint before the coins are added to the pool
+	totalShares := k.GetTotalShares(ctx, amount.Denom)
+	value, err := k.getPoolValue(ctx, amount.Denom)
 	if err != nil {
 		return err
 	}
-
-	// get the pool
-	pool, addError := sdk.AccAddressFromHex("00000000000000000000")
-	if addError != nil {
-		return sdk.ErrInternal(addError.Error())
+	var newShares sdk.Int
+	switch {
+	case totalShares.IsZero():
+		newShares = amount.Amount // the first deposit sets a share price of 1
+	case value.IsZero():
+		return sdk.ErrInternal("pool has no funds left to back its shares")
+	default:
+		newShares = amount.Amount.Mul(totalShares).Quo(value) // rounded down, in favour of the pool
+	}
+	if !newShares.IsPositive() {
+		return sdk.ErrInvalidCoins("deposit is too small to buy a pool share")
 	}
 
-	// add the funds to the pool
-	_, err = k.bankKeeper.AddCoins(ctx, pool, []sdk.Coin{amount})
+	// move the coins from the sender to the pool
+	_, err = k.bankKeeper.SubtractCoins(ctx, account, []sdk.Coin{amount})
 	if err != nil {
 		return err
 	}
-
-	// get any existing funds for the user
-	coin, fundsError := k.GetAccountFunds(ctx, account)
-	if fundsError != nil {
-		coin = sdk.NewCoin(amount.Denom, sdk.NewInt(0))
-	}
-
-	if coin.Denom != amount.Denom {
-		return sdk.ErrInvalidCoins(fmt.Sprintf("invalid coin, expected %s got %s", coin.Denom, amount.Denom))
-	}
-
-	// add the coin amount and set the denom
-	coin.Amount = coin.Amount.Add(amount.Amount)
-	coin.Denom = amount.Denom
-
-	// set the deposit
-	bytes, marshalError := k.cdc.MarshalBinaryBare(coin)
-	if marshalError != nil {
-		return sdk.ErrInternal(marshalError.Error())
+	_, err = k.bankKeeper.AddCoins(ctx, ModuleAddress, []sdk.Coin{amount})
+	if err != nil {
+		return err
 	}
 
-	store := ctx.KVStore(k.fundsStoreKey)
-	store.Set(account, bytes)
+	// mint the shares
+	shares.Amount = shares.Amount.Add(newShares)
+	k.setAccountShares(ctx, account, shares)
+	k.setTotalShares(ctx, amount.Denom, totalShares.Add(newShares))
 
-	return err
+	return nil
 }
 
-// WithdrawFundToAddress allows to take the specified amount from the pool and store into the given account balance
+// WithdrawFundToAddress allows to take the specified amount from the pool and store into the given account balance, burning shares for it at the current share price
 func (k Keeper) WithdrawFundToAddress(ctx sdk.Context, amount sdk.Coin, account sdk.AccAddress) sdk.Error {
 
-	existingAmount, err := k.GetAccountFunds(ctx, account)
+	// check for valid denom
+	shares := k.GetAccountDenomShares(ctx, account, amount.Denom)
+	if !shares.IsPositive() {
+		return sdk.ErrInsufficientCoins("specified address has no funds with given coin denom")
+	}
+
+	// compute the shares to burn, rounded up in favour of the pool
+	totalShares := k.GetTotalShares(ctx, amount.Denom)
+	value, err := k.getPoolValue(ctx, amount.Denom)
 	if err != nil {
 		return err
 	}
-
-	// check for valid denom
-	if existingAmount.Denom != amount.Denom {
-		return sdk.ErrInvalidCoins("specified address has no funds with given coin denom")
+	if value.IsZero() {
+		return sdk.ErrInsufficientCoins("pool has no funds to withdraw")
 	}
+	burnedShares := amount.Amount.Mul(totalShares).Add(value).Sub(sdk.OneInt()).Quo(value)
 
 	// check for valid amount
-	if existingAmount.Amount.LT(amount.Amount) {
+	if shares.Amount.LT(burnedShares) {
 		return sdk.ErrInsufficientCoins("specified address has not enough funds to withdraw")
 	}
-
-	// update the funds status
-	existingAmount.Amount = existingAmount.Amount.Sub(amount.Amount)
-	existingAmount.Denom = amount.Denom
-
-	store := ctx.KVStore(k.fundsStoreKey)
-	store.Set(account, k.cdc.MustMarshalBinaryBare(existingAmount))
-
-	// get the pool
-	pool, addressErr := sdk.AccAddressFromHex("00000000000000000000")
-	if addressErr != nil {
-		return sdk.ErrInternal(addressErr.Error())
+	available, err := k.GetAvailableLiquidity(ctx, amount.Denom)
+	if err != nil {
+		return err
+	}
+	if available.LT(amount.Amount) {
+		return sdk.ErrInsufficientCoins("not enough liquidity in the pool, the rest of its funds are lent to CDPs")
 	}
 
+	// burn the shares
+	shares.Amount = shares.Amount.Sub(burnedShares)
+	k.setAccountShares(ctx, account, shares)
+	k.setTotalShares(ctx, amount.Denom, totalShares.Sub(burnedShares))
+
 	// remove the coins from the pool
-	_, err = k.bankKeeper.SubtractCoins(ctx, pool, []sdk.Coin{amount})
+	_, err = k.bankKeeper.SubtractCoins(ctx, ModuleAddress, []sdk.Coin{amount})
 	if err != nil {
 		return sdk.ErrInternal(err.Error())
 	}
@@ -115,100 +133,339 @@ func (k Keeper) WithdrawFundToAddress(ctx sdk.Context, amount sdk.Coin, account
 	return nil
 }
 
-// GetAccountFunds returns the funds of the given account
-func (k Keeper) GetAccountFunds(ctx sdk.Context, account sdk.AccAddress) (sdk.Coin, sdk.Error) {
-
-	// default funds have the given bond denom and 0
-	var amount sdk.Coin
+// GetAccountFunds returns the current value of the shares of the given account, for each denom it deposited
+func (k Keeper) GetAccountFunds(ctx sdk.Context, account sdk.AccAddress) (sdk.Coins, sdk.Error) {
+	allShares := k.GetAccountShares(ctx, account)
+	if allShares.Empty() {
+		return nil, sdk.ErrInsufficientCoins("address has no funds")
+	}
 
-	// read the saved funds
-	store := ctx.KVStore(k.fundsStoreKey)
-	value := store.Get(account)
+	funds := sdk.NewCoins()
+	for _, shares := range allShares {
+		fund, err := k.GetAccountDenomFunds(ctx, account, shares.Denom)
+		if err != nil {
+			return nil, err
+		}
+		funds = funds.Add(sdk.NewCoins(fund))
+	}
+	return funds, nil
+}
 
-	// if nil return 0
-	if value == nil {
-		return amount, sdk.ErrInsufficientCoins("address has no funds")
+// GetAccountDenomFunds returns the current value of the shares of the given denom owned by the given account
+func (k Keeper) GetAccountDenomFunds(ctx sdk.Context, account sdk.AccAddress, denom string) (sdk.Coin, sdk.Error) {
+	shares := k.GetAccountDenomShares(ctx, account, denom)
+	if !shares.IsPositive() {
+		return sdk.Coin{}, sdk.ErrInsufficientCoins("specified address has no funds with given coin denom")
 	}
 
-	// unwrap the value
-	err := k.cdc.UnmarshalBinaryBare(value, &amount)
+	totalShares := k.GetTotalShares(ctx, shares.Denom)
+	if totalShares.IsZero() {
+		return sdk.NewCoin(shares.Denom, sdk.ZeroInt()), nil
+	}
+	value, err := k.getPoolValue(ctx, shares.Denom)
 	if err != nil {
-		return amount, sdk.ErrInternal(err.Error())
+		return sdk.Coin{}, err
 	}
+	return sdk.NewCoin(shares.Denom, shares.Amount.Mul(value).Quo(totalShares)), nil // rounded down, in favour of the pool
+}
 
-	return amount, nil
+// GetTotalFunds returns the funds held by the pool, which the depositors own through their shares along with the liquidity lent to CDPs
+func (k Keeper) GetTotalFunds(ctx sdk.Context) (sdk.Coins, sdk.Error) {
+	return k.bankKeeper.GetCoins(ctx, ModuleAddress), nil
 }
 
-// DistributeReward distributes the given reward between all the funders
-func (k Keeper) DistributeReward(ctx sdk.Context) error {
+// GetSharePrice returns the value of one share of the pool for the given denom, one if no shares have been issued yet
+func (k Keeper) GetSharePrice(ctx sdk.Context, denom string) (sdk.Dec, sdk.Error) {
+	totalShares := k.GetTotalShares(ctx, denom)
+	if totalShares.IsZero() {
+		return sdk.OneDec(), nil
+	}
+	value, err := k.getPoolValue(ctx, denom)
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+	return sdk.NewDecFromInt(value).Quo(sdk.NewDecFromInt(totalShares)), nil
+}
+
+// getPoolValue returns the amount of the given denom owned by the depositors, including the liquidity lent to CDPs
+func (k Keeper) getPoolValue(ctx sdk.Context, denom string) (sdk.Int, sdk.Error) {
+	available, err := k.GetAvailableLiquidity(ctx, denom)
+	if err != nil {
+		return sdk.Int{}, err
+	}
+	return available.Add(k.GetLentLiquidity(ctx, denom)), nil
+}
 
-	// find the total funds for the given reward coin
+// GetAvailableLiquidity returns the amount of the given denom held by the pool account, which can be withdrawn or drawn from CDPs
+func (k Keeper) GetAvailableLiquidity(ctx sdk.Context, denom string) (sdk.Int, sdk.Error) {
 	funds, err := k.GetTotalFunds(ctx)
+	if err != nil {
+		return sdk.Int{}, err
+	}
+	return funds.AmountOf(denom), nil
+}
+
+// GetDrawableLiquidity returns the amount of the given denom held by the pool account which can be drawn from CDPs, that is not waiting in the queue to be withdrawn
+func (k Keeper) GetDrawableLiquidity(ctx sdk.Context, denom string) (sdk.Int, sdk.Error) {
+	available, err := k.GetAvailableLiquidity(ctx, denom)
+	if err != nil {
+		return sdk.Int{}, err
+	}
+	return sdk.MaxInt(available.Sub(k.GetQueuedWithdrawalsTotal(ctx, denom)), sdk.ZeroInt()), nil
+}
+
+// DrawLiquidity moves the given amount from the pool to the owner of a CDP drawing it, failing if the pool doesn't hold enough of it besides the queued withdrawals
+func (k Keeper) DrawLiquidity(ctx sdk.Context, borrower sdk.AccAddress, amount sdk.Coin) sdk.Error {
+	if !amount.IsPositive() {
+		return nil
+	}
+	drawable, err := k.GetDrawableLiquidity(ctx, amount.Denom)
+	if err != nil {
+		return err
+	}
+	if drawable.LT(amount.Amount) {
+		return sdk.ErrInsufficientCoins(fmt.Sprintf("not enough liquidity in the pool; %s%s < %s", drawable, amount.Denom, amount))
+	}
+
+	_, err = k.bankKeeper.SubtractCoins(ctx, ModuleAddress, sdk.NewCoins(amount))
+	if err != nil {
+		return err
+	}
+	_, err = k.bankKeeper.AddCoins(ctx, borrower, sdk.NewCoins(amount))
 	if err != nil {
 		return err
 	}
 
-	store := ctx.KVStore(k.fundsStoreKey)
+	k.setLentLiquidity(ctx, amount.Denom, k.GetLentLiquidity(ctx, amount.Denom).Add(amount.Amount))
+	return nil
+}
 
-	// iterate over all the funds coins
-	for _, fundCoin := range funds {
+// RepayLiquidity moves the given amount from the owner of a CDP repaying it back to the pool
+func (k Keeper) RepayLiquidity(ctx sdk.Context, borrower sdk.AccAddress, amount sdk.Coin) sdk.Error {
+	if !amount.IsPositive() {
+		return nil
+	}
+	_, err := k.bankKeeper.SubtractCoins(ctx, borrower, sdk.NewCoins(amount))
+	if err != nil {
+		return err
+	}
+	_, err = k.bankKeeper.AddCoins(ctx, ModuleAddress, sdk.NewCoins(amount))
+	if err != nil {
+		return err
+	}
 
-		// divide the reward
-		investorsIterator := store.Iterator(nil, nil)
-		for ; investorsIterator.Valid(); investorsIterator.Next() {
+	// debt created before the pool funded CDPs wasn't lent by it, repaying it only adds to the pool value
+	lent := sdk.MaxInt(k.GetLentLiquidity(ctx, amount.Denom).Sub(amount.Amount), sdk.ZeroInt())
+	k.setLentLiquidity(ctx, amount.Denom, lent)
+	return nil
+}
 
-			// get the fund
-			var fund sdk.Coin
-			k.cdc.MustUnmarshalBinaryBare(investorsIterator.Value(), &fund)
+// PayFees moves the given stability fees from the owner of a CDP to the pool.
+// Unlike repaid liquidity they weren't lent out, so they add to the pool value, raising the share price for the depositors.
+func (k Keeper) PayFees(ctx sdk.Context, payer sdk.AccAddress, fees sdk.Coin) sdk.Error {
+	if !fees.IsPositive() {
+		return nil
+	}
+	_, err := k.bankKeeper.SubtractCoins(ctx, payer, sdk.NewCoins(fees))
+	if err != nil {
+		return err
+	}
+	_, err = k.bankKeeper.AddCoins(ctx, ModuleAddress, sdk.NewCoins(fees))
+	return err
+}
 
-			// update only the same coins, not others
-			if fund.Denom == fundCoin.Denom {
+// ---------- Store Wrappers ----------
 
-				// compute the divided
-				dividend := fund.Amount.Quo(fundCoin.Amount)
+var (
+	sharesKeyPrefix      = []byte("shares:")
+	totalSharesKeyPrefix = []byte("totalShares:")
+	lentKeyPrefix        = []byte("lent:")
+)
 
-				// add it to the fund
-				fund.Amount = fund.Amount.Add(dividend)
+func getAccountSharesPrefix(account sdk.AccAddress) []byte {
+	return bytes.Join([][]byte{sharesKeyPrefix, account}, nil)
+}
+func getSharesKey(account sdk.AccAddress, denom string) []byte {
+	return bytes.Join([][]byte{sharesKeyPrefix, account, []byte(denom)}, nil)
+}
+func getTotalSharesKey(denom string) []byte {
+	return bytes.Join([][]byte{totalSharesKeyPrefix, []byte(denom)}, nil)
+}
+func getLentKey(denom string) []byte {
+	return bytes.Join([][]byte{lentKeyPrefix, []byte(denom)}, nil)
+}
 
-				// save it inside the store
-				store.Set(investorsIterator.Key(), k.cdc.MustMarshalBinaryBare(fund))
-			}
-		}
+// GetAccountShares returns the pool shares of the given account, one balance for each denom it deposited
+func (k Keeper) GetAccountShares(ctx sdk.Context, account sdk.AccAddress) sdk.Coins {
+	store := ctx.KVStore(k.fundsStoreKey)
+	iter := sdk.KVStorePrefixIterator(store, getAccountSharesPrefix(account))
+	defer iter.Close()
+
+	allShares := sdk.NewCoins()
+	for ; iter.Valid(); iter.Next() {
+		var shares sdk.Coin
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), &shares)
+		allShares = allShares.Add(sdk.NewCoins(shares))
+	}
+	return allShares
+}
 
+// GetAccountDenomShares returns the pool shares of the given denom owned by the given account, zero if it has none
+func (k Keeper) GetAccountDenomShares(ctx sdk.Context, account sdk.AccAddress, denom string) sdk.Coin {
+	store := ctx.KVStore(k.fundsStoreKey)
+	bz := store.Get(getSharesKey(account, denom))
+	if bz == nil {
+		return sdk.NewCoin(denom, sdk.ZeroInt())
+	}
+	var shares sdk.Coin
+	k.cdc.MustUnmarshalBinaryBare(bz, &shares)
+	return shares
+}
+func (k Keeper) setAccountShares(ctx sdk.Context, account sdk.AccAddress, shares sdk.Coin) {
+	store := ctx.KVStore(k.fundsStoreKey)
+	if shares.IsZero() {
+		store.Delete(getSharesKey(account, shares.Denom))
+		return
 	}
+	store.Set(getSharesKey(account, shares.Denom), k.cdc.MustMarshalBinaryBare(shares))
+}
 
-	return nil
+// GetTotalShares returns the shares issued for the given denom
+func (k Keeper) GetTotalShares(ctx sdk.Context, denom string) sdk.Int {
+	store := ctx.KVStore(k.fundsStoreKey)
+	bz := store.Get(getTotalSharesKey(denom))
+	if bz == nil {
+		return sdk.ZeroInt()
+	}
+	var totalShares sdk.Int
+	k.cdc.MustUnmarshalBinaryBare(bz, &totalShares)
+	return totalShares
+}
+func (k Keeper) setTotalShares(ctx sdk.Context, denom string, totalShares sdk.Int) {
+	store := ctx.KVStore(k.fundsStoreKey)
+	store.Set(getTotalSharesKey(denom), k.cdc.MustMarshalBinaryBare(totalShares))
 }
 
-func (k Keeper) GetTotalFunds(ctx sdk.Context) (sdk.Coins, sdk.Error) {
-	var values sdk.Coins
+// GetLentLiquidity returns the amount of the given denom drawn from the pool by CDP owners and not yet repaid
+func (k Keeper) GetLentLiquidity(ctx sdk.Context, denom string) sdk.Int {
+	store := ctx.KVStore(k.fundsStoreKey)
+	bz := store.Get(getLentKey(denom))
+	if bz == nil {
+		return sdk.ZeroInt()
+	}
+	var lent sdk.Int
+	k.cdc.MustUnmarshalBinaryBare(bz, &lent)
+	return lent
+}
+func (k Keeper) setLentLiquidity(ctx sdk.Context, denom string, lent sdk.Int) {
+	store := ctx.KVStore(k.fundsStoreKey)
+	store.Set(getLentKey(denom), k.cdc.MustMarshalBinaryBare(lent))
+}
 
-	// compute all the funds sum
-	funds := make(map[string]sdk.Int)
+// getDepositorsShares returns the sum of the shares of every account, used to check the total shares
+func (k Keeper) getDepositorsShares(ctx sdk.Context) sdk.Coins {
+	store := ctx.KVStore(k.fundsStoreKey)
+	iter := sdk.KVStorePrefixIterator(store, sharesKeyPrefix)
+	defer iter.Close()
+
+	allShares := sdk.NewCoins()
+	for ; iter.Valid(); iter.Next() {
+		var shares sdk.Coin
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), &shares)
+		allShares = allShares.Add(sdk.NewCoins(shares))
+	}
+	return allShares
+}
+
+// depositsVersionKey stores the version of the deposits kept by the store, see MigrateDeposits.
+// Version 1 stores the deposits as shares of the pool.
+var depositsVersionKey = []byte("depositsVersion")
 
+const depositsVersion byte = 1
+
+func (k Keeper) getDepositsVersion(ctx sdk.Context) byte {
+	bz := ctx.KVStore(k.fundsStoreKey).Get(depositsVersionKey)
+	if len(bz) != 1 {
+		return 0
+	}
+	return bz[0]
+}
+func (k Keeper) setDepositsMigrated(ctx sdk.Context) {
+	ctx.KVStore(k.fundsStoreKey).Set(depositsVersionKey, []byte{depositsVersion})
+}
+
+// MigrateDeposits turns the deposits stored by older versions under the raw account address, as the deposited coin, into pool shares.
+// Those deposits were claims on the same amount of coins, so they become shares at a price of one, as the first deposits of a denom do.
+// It does nothing once the store is up to date, stores initialized from genesis already are.
+func (k Keeper) MigrateDeposits(ctx sdk.Context) {
+	if k.getDepositsVersion(ctx) >= depositsVersion {
+		return
+	}
 	store := ctx.KVStore(k.fundsStoreKey)
-	sumIterator := store.Iterator(nil, nil)
-	for ; sumIterator.Valid(); sumIterator.Next() {
 
-		// get the fund
-		var fund sdk.Coin
-		err := k.cdc.UnmarshalBinaryBare(sumIterator.Value(), &fund)
-		if err != nil {
-			return values, sdk.ErrInternal(err.Error())
+	// Collect the deposits first, the store can't be written while iterating
+	var accounts []sdk.AccAddress
+	var deposits []sdk.Coin
+	iter := store.Iterator(nil, nil)
+	for ; iter.Valid(); iter.Next() {
+		if len(iter.Key()) != sdk.AddrLen {
+			continue // deposits are only stored under an account address
 		}
-
-		// sum up the total
-		amount := fund.Amount
-		if value, ok := funds[fund.Denom]; ok {
-			amount = value.Add(amount)
+		var deposit sdk.Coin
+		if err := k.cdc.UnmarshalBinaryBare(iter.Value(), &deposit); err != nil || deposit.Amount == (sdk.Int{}) {
+			continue
 		}
+		accounts = append(accounts, sdk.AccAddress(iter.Key()))
+		deposits = append(deposits, deposit)
+	}
+	iter.Close()
 
-		funds[fund.Denom] = amount
+	for i, account := range accounts {
+		store.Delete(account)
+		if !deposits[i].IsPositive() {
+			continue // fully withdrawn
+		}
+		shares := k.GetAccountDenomShares(ctx, account, deposits[i].Denom)
+		shares.Amount = shares.Amount.Add(deposits[i].Amount)
+		k.setAccountShares(ctx, account, shares)
+		k.setTotalShares(ctx, deposits[i].Denom, k.GetTotalShares(ctx, deposits[i].Denom).Add(deposits[i].Amount))
 	}
+	k.setDepositsMigrated(ctx)
+}
+
+// legacyAddress is the hex-zero address "00000000000000000000" where older versions held the pool funds
+var legacyAddress = sdk.AccAddress(make([]byte, 10))
 
-	for k, v := range funds {
-		values = append(values, sdk.NewCoin(k, v))
+// moduleAccountVersionKey stores the version of the account holding the pool funds, see MigrateModuleAccount.
+// Version 1 holds them in the pool module account.
+var moduleAccountVersionKey = []byte("moduleAccountVersion")
+
+const moduleAccountVersion byte = 1
+
+func (k Keeper) getModuleAccountVersion(ctx sdk.Context) byte {
+	bz := ctx.KVStore(k.fundsStoreKey).Get(moduleAccountVersionKey)
+	if len(bz) != 1 {
+		return 0
 	}
+	return bz[0]
+}
+func (k Keeper) setModuleAccountMigrated(ctx sdk.Context) {
+	ctx.KVStore(k.fundsStoreKey).Set(moduleAccountVersionKey, []byte{moduleAccountVersion})
+}
 
-	return values, nil
+// MigrateModuleAccount moves the pool funds held by older versions at the legacy hex-zero address into the pool module account.
+// It does nothing once the store is up to date, stores initialized from genesis already are.
+func (k Keeper) MigrateModuleAccount(ctx sdk.Context) {
+	if k.getModuleAccountVersion(ctx) >= moduleAccountVersion {
+		return
+	}
+	funds := k.bankKeeper.GetCoins(ctx, legacyAddress)
+	if !funds.Empty() {
+		err := k.bankKeeper.SendCoins(ctx, legacyAddress, ModuleAddress, funds)
+		if err != nil {
+			ctx.Logger().Error(fmt.Sprintf("could not move the pool funds to the module account: %s", err))
+			return // retried next block
+		}
+	}
+	k.setModuleAccountMigrated(ctx)
 }
@@ -0,0 +1,209 @@
+package pool
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeeper_DepositFundFromAddress(t *testing.T) {
+	ctx, k, bk := setupTestKeeper()
+	_, addrs := mock.GeneratePrivKeyAddressPairs(2)
+	bk.AddCoins(ctx, addrs[0], cs(c(stableDenom, 1000)))
+	bk.AddCoins(ctx, addrs[1], cs(c(stableDenom, 1000)))
+
+	// the first deposit mints shares one to one
+	require.NoError(t, k.DepositFundFromAddress(ctx, addrs[0], c(stableDenom, 300)))
+	require.Equal(t, c(stableDenom, 300), k.GetAccountDenomShares(ctx, addrs[0], stableDenom))
+	require.Equal(t, i(300), k.GetTotalShares(ctx, stableDenom))
+	require.Equal(t, cs(c(stableDenom, 700)), bk.GetCoins(ctx, addrs[0]))
+	require.Equal(t, cs(c(stableDenom, 300)), bk.GetCoins(ctx, ModuleAddress))
+
+	// fees raise the share price to 400/300
+	bk.AddCoins(ctx, ModuleAddress, cs(c(stableDenom, 100)))
+	price, err := k.GetSharePrice(ctx, stableDenom)
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewDec(4).Quo(sdk.NewDec(3)), price)
+
+	// later deposits mint shares at the share price, rounded down
+	require.NoError(t, k.DepositFundFromAddress(ctx, addrs[1], c(stableDenom, 101)))
+	require.Equal(t, c(stableDenom, 75), k.GetAccountDenomShares(ctx, addrs[1], stableDenom)) // 101*300/400 = 75.75
+	require.Equal(t, i(375), k.GetTotalShares(ctx, stableDenom))
+	require.Equal(t, cs(c(stableDenom, 899)), bk.GetCoins(ctx, addrs[1]))
+
+	// deposits too small to buy a share are rejected
+	require.Error(t, k.DepositFundFromAddress(ctx, addrs[1], c(stableDenom, 1)))
+	require.Equal(t, cs(c(stableDenom, 899)), bk.GetCoins(ctx, addrs[1]))
+
+	// deposits can't exceed the account balance
+	require.Error(t, k.DepositFundFromAddress(ctx, addrs[1], c(stableDenom, 900)))
+	require.Equal(t, i(375), k.GetTotalShares(ctx, stableDenom))
+}
+
+func TestKeeper_WithdrawFundToAddress(t *testing.T) {
+	ctx, k, bk := setupTestKeeper()
+	_, addrs := mock.GeneratePrivKeyAddressPairs(2)
+	bk.AddCoins(ctx, addrs[0], cs(c(stableDenom, 300)))
+	require.NoError(t, k.DepositFundFromAddress(ctx, addrs[0], c(stableDenom, 300)))
+	bk.AddCoins(ctx, ModuleAddress, cs(c(stableDenom, 100)))
+
+	// withdrawals burn shares at the share price, rounded up
+	require.NoError(t, k.WithdrawFundToAddress(ctx, c(stableDenom, 101), addrs[0]))
+	require.Equal(t, c(stableDenom, 224), k.GetAccountDenomShares(ctx, addrs[0], stableDenom)) // 101*300/400 = 75.75 burned
+	require.Equal(t, i(224), k.GetTotalShares(ctx, stableDenom))
+	require.Equal(t, cs(c(stableDenom, 101)), bk.GetCoins(ctx, addrs[0]))
+	require.Equal(t, cs(c(stableDenom, 299)), bk.GetCoins(ctx, ModuleAddress))
+
+	// the remaining shares are worth the rest of the pool
+	funds, err := k.GetAccountDenomFunds(ctx, addrs[0], stableDenom)
+	require.NoError(t, err)
+	require.Equal(t, c(stableDenom, 299), funds)
+
+	// withdrawals can't burn more shares than the account owns
+	require.Error(t, k.WithdrawFundToAddress(ctx, c(stableDenom, 300), addrs[0]))
+	require.Error(t, k.WithdrawFundToAddress(ctx, c(stableDenom, 1), addrs[1]))
+
+	// withdrawing everything burns all the shares
+	require.NoError(t, k.WithdrawFundToAddress(ctx, c(stableDenom, 299), addrs[0]))
+	require.True(t, k.GetAccountShares(ctx, addrs[0]).Empty())
+	require.True(t, k.GetTotalShares(ctx, stableDenom).IsZero())
+	require.Equal(t, cs(c(stableDenom, 400)), bk.GetCoins(ctx, addrs[0]))
+}
+
+func TestKeeper_PoolValueWithLentLiquidity(t *testing.T) {
+	ctx, k, bk := setupTestKeeper()
+	_, addrs := mock.GeneratePrivKeyAddressPairs(2)
+	depositor, borrower := addrs[0], addrs[1]
+	bk.AddCoins(ctx, depositor, cs(c(stableDenom, 1000)))
+	require.NoError(t, k.DepositFundFromAddress(ctx, depositor, c(stableDenom, 1000)))
+
+	// lent liquidity still belongs to the depositors
+	require.NoError(t, k.DrawLiquidity(ctx, borrower, c(stableDenom, 600)))
+	require.Equal(t, i(600), k.GetLentLiquidity(ctx, stableDenom))
+	available, err := k.GetAvailableLiquidity(ctx, stableDenom)
+	require.NoError(t, err)
+	require.Equal(t, i(400), available)
+	price, err := k.GetSharePrice(ctx, stableDenom)
+	require.NoError(t, err)
+	require.Equal(t, sdk.OneDec(), price)
+	funds, err := k.GetAccountDenomFunds(ctx, depositor, stableDenom)
+	require.NoError(t, err)
+	require.Equal(t, c(stableDenom, 1000), funds)
+
+	// only the liquidity held by the pool can be drawn or withdrawn
+	require.Error(t, k.DrawLiquidity(ctx, borrower, c(stableDenom, 401)))
+	require.Error(t, k.WithdrawFundToAddress(ctx, c(stableDenom, 401), depositor))
+
+	// fees raise the share price, repaid liquidity doesn't
+	bk.AddCoins(ctx, borrower, cs(c(stableDenom, 100)))
+	require.NoError(t, k.PayFees(ctx, borrower, c(stableDenom, 100)))
+	require.Equal(t, i(600), k.GetLentLiquidity(ctx, stableDenom))
+	require.NoError(t, k.RepayLiquidity(ctx, borrower, c(stableDenom, 600)))
+	require.True(t, k.GetLentLiquidity(ctx, stableDenom).IsZero())
+	price, err = k.GetSharePrice(ctx, stableDenom)
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewDecWithPrec(11, 1), price)
+	funds, err = k.GetAccountDenomFunds(ctx, depositor, stableDenom)
+	require.NoError(t, err)
+	require.Equal(t, c(stableDenom, 1100), funds)
+	require.True(t, bk.GetCoins(ctx, borrower).Empty())
+}
+
+func TestKeeper_MigrateDeposits(t *testing.T) {
+	ctx, k, _ := setupTestKeeper()
+	_, addrs := mock.GeneratePrivKeyAddressPairs(3)
+
+	// deposits were stored as the deposited coin under the raw account address
+	store := ctx.KVStore(k.fundsStoreKey)
+	store.Set(addrs[0], k.cdc.MustMarshalBinaryBare(c(stableDenom, 300)))
+	store.Set(addrs[1], k.cdc.MustMarshalBinaryBare(c(stableDenom, 200)))
+	store.Set(addrs[2], k.cdc.MustMarshalBinaryBare(c(stableDenom, 0)))
+
+	k.MigrateDeposits(ctx)
+
+	require.Equal(t, c(stableDenom, 300), k.GetAccountDenomShares(ctx, addrs[0], stableDenom))
+	require.Equal(t, c(stableDenom, 200), k.GetAccountDenomShares(ctx, addrs[1], stableDenom))
+	require.True(t, k.GetAccountShares(ctx, addrs[2]).Empty())
+	require.Equal(t, i(500), k.GetTotalShares(ctx, stableDenom))
+	for _, addr := range addrs {
+		require.False(t, store.Has(addr))
+	}
+	require.Equal(t, depositsVersion, k.getDepositsVersion(ctx))
+
+	// migrating again does nothing
+	store.Set(addrs[2], k.cdc.MustMarshalBinaryBare(c(stableDenom, 100)))
+	k.MigrateDeposits(ctx)
+	require.True(t, k.GetAccountShares(ctx, addrs[2]).Empty())
+	require.Equal(t, i(500), k.GetTotalShares(ctx, stableDenom))
+}
+
+func TestKeeper_MultiDenomDeposits(t *testing.T) {
+	ctx, k, bk := setupTestKeeper()
+	_, addrs := mock.GeneratePrivKeyAddressPairs(3)
+	depositor, other, borrower := addrs[0], addrs[1], addrs[2]
+	bk.AddCoins(ctx, depositor, cs(c(stableDenom, 1000), c("xrp", 1000)))
+	bk.AddCoins(ctx, other, cs(c("xrp", 1000)))
+
+	// one account can deposit several denoms, each minting its own shares
+	require.NoError(t, k.DepositFundFromAddress(ctx, depositor, c(stableDenom, 600)))
+	require.NoError(t, k.DepositFundFromAddress(ctx, depositor, c("xrp", 200)))
+	require.NoError(t, k.DepositFundFromAddress(ctx, other, c("xrp", 300)))
+	require.Equal(t, cs(c(stableDenom, 600), c("xrp", 200)), k.GetAccountShares(ctx, depositor))
+	require.Equal(t, cs(c("xrp", 300)), k.GetAccountShares(ctx, other))
+	require.Equal(t, i(600), k.GetTotalShares(ctx, stableDenom))
+	require.Equal(t, i(500), k.GetTotalShares(ctx, "xrp"))
+
+	// lending and fees of one denom leave the other one untouched
+	require.NoError(t, k.DrawLiquidity(ctx, borrower, c(stableDenom, 400)))
+	bk.AddCoins(ctx, ModuleAddress, cs(c(stableDenom, 60)))
+	require.Equal(t, i(400), k.GetLentLiquidity(ctx, stableDenom))
+	require.True(t, k.GetLentLiquidity(ctx, "xrp").IsZero())
+	price, err := k.GetSharePrice(ctx, stableDenom)
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewDecWithPrec(11, 1), price)
+	price, err = k.GetSharePrice(ctx, "xrp")
+	require.NoError(t, err)
+	require.Equal(t, sdk.OneDec(), price)
+	funds, err := k.GetAccountFunds(ctx, depositor)
+	require.NoError(t, err)
+	require.Equal(t, cs(c(stableDenom, 660), c("xrp", 200)), funds)
+
+	// the liquidity lent out of one denom doesn't limit withdrawals of the other
+	require.Error(t, k.WithdrawFundToAddress(ctx, c(stableDenom, 261), depositor))
+	require.NoError(t, k.WithdrawFundToAddress(ctx, c("xrp", 200), depositor))
+	require.Equal(t, cs(c(stableDenom, 600)), k.GetAccountShares(ctx, depositor))
+	require.Equal(t, i(300), k.GetTotalShares(ctx, "xrp"))
+	require.Equal(t, cs(c(stableDenom, 400), c("xrp", 1000)), bk.GetCoins(ctx, depositor))
+
+	// withdrawing a denom that was never deposited fails
+	require.Error(t, k.WithdrawFundToAddress(ctx, c(stableDenom, 1), other))
+	_, err = k.GetAccountDenomFunds(ctx, other, stableDenom)
+	require.Error(t, err)
+}
+
+func TestKeeper_MigrateModuleAccount(t *testing.T) {
+	ctx, k, bk := setupTestKeeper()
+	_, addrs := mock.GeneratePrivKeyAddressPairs(1)
+	bk.AddCoins(ctx, addrs[0], cs(c(stableDenom, 300)))
+	require.NoError(t, k.DepositFundFromAddress(ctx, addrs[0], c(stableDenom, 300)))
+
+	// older versions held the pool funds at the hex-zero address
+	legacy, err := sdk.AccAddressFromHex("00000000000000000000")
+	require.NoError(t, err)
+	require.Equal(t, legacyAddress, legacy)
+	bk.AddCoins(ctx, legacy, cs(c(stableDenom, 200), c("xrp", 50)))
+
+	k.MigrateModuleAccount(ctx)
+
+	require.True(t, bk.GetCoins(ctx, legacy).Empty())
+	require.Equal(t, cs(c(stableDenom, 500), c("xrp", 50)), bk.GetCoins(ctx, ModuleAddress))
+	require.Equal(t, moduleAccountVersion, k.getModuleAccountVersion(ctx))
+
+	// migrating again does nothing
+	bk.AddCoins(ctx, legacy, cs(c(stableDenom, 10)))
+	k.MigrateModuleAccount(ctx)
+	require.Equal(t, cs(c(stableDenom, 10)), bk.GetCoins(ctx, legacy))
+	require.Equal(t, cs(c(stableDenom, 500), c("xrp", 50)), bk.GetCoins(ctx, ModuleAddress))
+}
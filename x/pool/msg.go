@@ -45,7 +45,7 @@ func (msg MsgDepositFund) GetSigners() []sdk.AccAddress {
 
 // ============================================
 
-// MsgWithdrawFund inserts a given amount into the pool
+// MsgWithdrawFund withdraws a given amount from the pool, queuing the withdrawal if the pool lacks the liquidity
 type MsgWithdrawFund struct {
 	Sender sdk.AccAddress
 	Amount sdk.Coin
@@ -83,3 +83,46 @@ func (msg MsgWithdrawFund) GetSignBytes() []byte {
 func (msg MsgWithdrawFund) GetSigners() []sdk.AccAddress {
 	return []sdk.AccAddress{msg.Sender}
 }
+
+// ============================================
+
+// MsgCancelWithdrawal removes a queued withdrawal of the given denom from the queue
+type MsgCancelWithdrawal struct {
+	Sender sdk.AccAddress
+	Denom  string
+}
+
+func NewMsgCancelWithdrawal(sender sdk.AccAddress, denom string) MsgCancelWithdrawal {
+	return MsgCancelWithdrawal{
+		Sender: sender,
+		Denom:  denom,
+	}
+}
+
+// Route return the message type used for routing the message.
+func (msg MsgCancelWithdrawal) Route() string { return "pool" }
+
+// Type returns a human-readable string for the message, intended for utilization within tags.
+func (msg MsgCancelWithdrawal) Type() string { return "cancel_withdrawal" }
+
+// ValidateBasic does a simple validation check that doesn't require access to any other information.
+func (msg MsgCancelWithdrawal) ValidateBasic() sdk.Error {
+	if msg.Sender.Empty() {
+		return sdk.ErrInternal("invalid (empty) sender address")
+	}
+	if msg.Denom == "" {
+		return sdk.ErrInternal("invalid (empty) denom")
+	}
+	return nil
+}
+
+// GetSignBytes gets the canonical byte representation of the Msg.
+func (msg MsgCancelWithdrawal) GetSignBytes() []byte {
+	bz := moduleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners returns the addresses of signers that must sign.
+func (msg MsgCancelWithdrawal) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
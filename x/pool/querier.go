@@ -2,14 +2,20 @@ package pool
 
 import (
 	"fmt"
+
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	abci "github.com/tendermint/tendermint/abci/types"
 )
 
 const (
-	QueryTotalFunds = "funds"
-	QueryReadFunds  = "get-funds"
+	QueryTotalFunds    = "funds"
+	QueryReadFunds     = "get-funds"
+	QueryShares        = "shares"
+	QuerySharePrice    = "share-price"
+	QueryModuleAccount = "module-account"
+	QueryWithdrawal    = "withdrawal"
 )
 
 func NewQuerier(keeper Keeper) sdk.Querier {
@@ -19,17 +25,27 @@ func NewQuerier(keeper Keeper) sdk.Querier {
 			return queryGetTotalFunds(ctx, req, keeper)
 		case QueryReadFunds:
 			return queryGetFunds(ctx, req, keeper)
+		case QueryShares:
+			return queryGetShares(ctx, req, keeper)
+		case QuerySharePrice:
+			return queryGetSharePrice(ctx, req, keeper)
+		case QueryModuleAccount:
+			return queryGetModuleAccount(ctx, req, keeper)
+		case QueryWithdrawal:
+			return queryGetWithdrawal(ctx, req, keeper)
 		default:
 			return nil, sdk.ErrUnknownRequest("unknown pool query endpoint")
 		}
 	}
 }
 
+// QueryFundsParams are the params of the funds and shares queries of an owner, restricted to the given denom unless it's empty
 type QueryFundsParams struct {
 	Owner sdk.AccAddress
+	Denom string
 }
 
-// queryGetFunds fetched the funds for a specific owner
+// queryGetFunds fetched the funds for a specific owner, in all the denoms deposited or in the requested one
 func queryGetFunds(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
 	// Decode request
 	var requestParams QueryFundsParams
@@ -38,14 +54,22 @@ func queryGetFunds(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byt
 		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
 	}
 
-	// Get CDPs
-	fund, err := keeper.GetAccountFunds(ctx, requestParams.Owner)
-	if err != nil {
-		return nil, sdk.ErrInternal(err.Error())
+	// Get funds
+	var funds sdk.Coins
+	var sdkErr sdk.Error
+	if requestParams.Denom == "" {
+		funds, sdkErr = keeper.GetAccountFunds(ctx, requestParams.Owner)
+	} else {
+		var fund sdk.Coin
+		fund, sdkErr = keeper.GetAccountDenomFunds(ctx, requestParams.Owner, requestParams.Denom)
+		funds = sdk.Coins{fund}
+	}
+	if sdkErr != nil {
+		return nil, sdk.ErrInternal(sdkErr.Error())
 	}
 
 	// Encode results
-	bz, err := codec.MarshalJSONIndent(keeper.cdc, fund)
+	bz, err := codec.MarshalJSONIndent(keeper.cdc, funds)
 	if err != nil {
 		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
 	}
@@ -68,3 +92,94 @@ func queryGetTotalFunds(ctx sdk.Context, _ abci.RequestQuery, keeper Keeper) ([]
 	}
 	return bz, nil
 }
+
+// queryGetShares fetches the pool shares of a specific owner
+func queryGetShares(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	// Decode request
+	var requestParams QueryFundsParams
+	err := keeper.cdc.UnmarshalJSON(req.Data, &requestParams)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+
+	// Get shares
+	shares := keeper.GetAccountShares(ctx, requestParams.Owner)
+	if requestParams.Denom != "" {
+		shares = sdk.NewCoins(keeper.GetAccountDenomShares(ctx, requestParams.Owner, requestParams.Denom))
+	}
+	if shares.Empty() {
+		return nil, sdk.ErrInsufficientCoins("address has no shares")
+	}
+
+	// Encode results
+	bz, err := codec.MarshalJSONIndent(keeper.cdc, shares)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+	return bz, nil
+}
+
+type QuerySharePriceParams struct {
+	Denom string
+}
+
+// queryGetSharePrice fetches the value of one pool share of a denom
+func queryGetSharePrice(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	// Decode request
+	var requestParams QuerySharePriceParams
+	err := keeper.cdc.UnmarshalJSON(req.Data, &requestParams)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+
+	// Get share price
+	price, sdkErr := keeper.GetSharePrice(ctx, requestParams.Denom)
+	if sdkErr != nil {
+		return nil, sdkErr
+	}
+
+	// Encode results
+	bz, err := codec.MarshalJSONIndent(keeper.cdc, price)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+	return bz, nil
+}
+
+// queryGetModuleAccount fetches the address and balance of the pool module account
+func queryGetModuleAccount(ctx sdk.Context, _ abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	funds, err := keeper.GetTotalFunds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Encode results
+	bz, jsonError := codec.MarshalJSONIndent(keeper.cdc, types.ModuleAccount{Address: ModuleAddress, Coins: funds})
+	if jsonError != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", jsonError.Error()))
+	}
+	return bz, nil
+}
+
+// queryGetWithdrawal fetches the queued withdrawal of a denom of a specific owner, with its position in the queue
+func queryGetWithdrawal(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	// Decode request
+	var requestParams QueryFundsParams
+	err := keeper.cdc.UnmarshalJSON(req.Data, &requestParams)
+	if err != nil {
+		return nil, sdk.ErrInternal(fmt.Sprintf("failed to parse params: %s", err))
+	}
+
+	// Get queued withdrawal
+	withdrawal, found := keeper.GetQueuedWithdrawal(ctx, requestParams.Owner, requestParams.Denom)
+	if !found {
+		return nil, sdk.ErrInternal("address has no queued withdrawal with given coin denom")
+	}
+
+	// Encode results
+	bz, err := codec.MarshalJSONIndent(keeper.cdc, withdrawal)
+	if err != nil {
+		return nil, sdk.ErrInternal(sdk.AppendMsgToErr("could not marshal result to JSON", err.Error()))
+	}
+	return bz, nil
+}
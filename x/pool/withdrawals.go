@@ -0,0 +1,331 @@
+package pool
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+/*
+How the withdrawal queue works:
+ - Withdrawals are paid straight away when the pool holds enough liquidity and nobody is waiting to withdraw the same denom.
+ - Otherwise, as the rest of the funds are lent to CDPs, the withdrawal is queued. Each denom has its own first come first served queue.
+ - At the end of every block each queue is paid out in order with the liquidity repaid to the pool in the meantime, up to the first request it can't pay in full.
+ - The liquidity waiting to be withdrawn can't be drawn from CDPs, else a busy pool would never pay its queue.
+ - Queued withdrawals can be paid in several parts, and the shares are only burnt when each part is paid, at the share price of that moment.
+ - Depositors can cancel their queued withdrawal, a new withdrawal of the same denom is added to the queued one, keeping its position.
+*/
+
+// TagWithdrawalFailed tag of the queued withdrawals that failed to be paid, as depositor:denom
+const TagWithdrawalFailed = "withdrawal-failed"
+
+// WithdrawalRequest is a withdrawal waiting in the queue for the pool to get back enough liquidity
+type WithdrawalRequest struct {
+	ID        uint64         `json:"id"`
+	Depositor sdk.AccAddress `json:"depositor"`
+	Amount    sdk.Coin       `json:"amount"` // still to be withdrawn
+}
+
+// QueuedWithdrawal is a withdrawal request along with its position in the queue of its denom, starting from 1
+type QueuedWithdrawal struct {
+	WithdrawalRequest
+	Position int64 `json:"position"`
+}
+
+// implement fmt.Stringer
+func (qw QueuedWithdrawal) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`Depositor: %s
+Amount: %s
+Position: %d`, qw.Depositor, qw.Amount, qw.Position))
+}
+
+// RequestWithdrawal withdraws the given amount from the pool if it has the liquidity to, otherwise it queues the withdrawal.
+// It returns whether the withdrawal has been queued.
+func (k Keeper) RequestWithdrawal(ctx sdk.Context, account sdk.AccAddress, amount sdk.Coin) (bool, sdk.Error) {
+	if !amount.IsPositive() {
+		return false, sdk.ErrInvalidCoins("withdrawal amount must be positive")
+	}
+
+	// check the account owns enough funds, including those it is already waiting to withdraw
+	funds, err := k.GetAccountDenomFunds(ctx, account, amount.Denom)
+	if err != nil {
+		return false, err
+	}
+	request, queued := k.getWithdrawalRequest(ctx, account, amount.Denom)
+	if !queued {
+		request = WithdrawalRequest{Depositor: account, Amount: sdk.NewCoin(amount.Denom, sdk.ZeroInt())}
+	}
+	request.Amount = request.Amount.Add(amount)
+	if funds.Amount.LT(request.Amount.Amount) {
+		return false, sdk.ErrInsufficientCoins("specified address has not enough funds to withdraw")
+	}
+
+	// withdraw straight away if nobody is waiting before
+	available, err := k.GetAvailableLiquidity(ctx, amount.Denom)
+	if err != nil {
+		return false, err
+	}
+	if !queued && !k.hasQueuedWithdrawals(ctx, amount.Denom) && amount.Amount.LTE(available) {
+		return false, k.WithdrawFundToAddress(ctx, amount, account)
+	}
+
+	// otherwise join the back of the queue, or add to the queued request
+	if !queued {
+		request.ID = k.getNextWithdrawalID(ctx)
+		k.setNextWithdrawalID(ctx, request.ID+1)
+	}
+	k.setWithdrawalRequest(ctx, request)
+	return true, nil
+}
+
+// CancelWithdrawal removes the queued withdrawal of the given denom of the given account
+func (k Keeper) CancelWithdrawal(ctx sdk.Context, account sdk.AccAddress, denom string) sdk.Error {
+	request, found := k.getWithdrawalRequest(ctx, account, denom)
+	if !found {
+		return sdk.ErrInternal("address has no queued withdrawal with given coin denom")
+	}
+	k.deleteWithdrawalRequest(ctx, request)
+	return nil
+}
+
+// GetQueuedWithdrawal returns the queued withdrawal of the given denom of the given account, with its position in the queue
+func (k Keeper) GetQueuedWithdrawal(ctx sdk.Context, account sdk.AccAddress, denom string) (QueuedWithdrawal, bool) {
+	request, found := k.getWithdrawalRequest(ctx, account, denom)
+	if !found {
+		return QueuedWithdrawal{}, false
+	}
+
+	// count the requests up to this one
+	position := k.countWithdrawalRequestsFrom(ctx, denom, 0) - k.countWithdrawalRequestsFrom(ctx, denom, request.ID+1)
+	return QueuedWithdrawal{request, position}, true
+}
+
+// GetQueuedWithdrawalsTotal returns the amount of the given denom waiting in the queue to be withdrawn
+func (k Keeper) GetQueuedWithdrawalsTotal(ctx sdk.Context, denom string) sdk.Int {
+	store := ctx.KVStore(k.fundsStoreKey)
+	bz := store.Get(getWithdrawalQueueTotalKey(denom))
+	if bz == nil {
+		return sdk.ZeroInt()
+	}
+	var total sdk.Int
+	k.cdc.MustUnmarshalBinaryBare(bz, &total)
+	return total
+}
+
+// ProcessWithdrawalQueue pays out the queue of each denom in order, up to the first request the pool liquidity can't pay in full.
+// A request that fails to be paid stays queued, blocking its queue, and a tag is returned for it.
+func (k Keeper) ProcessWithdrawalQueue(ctx sdk.Context) sdk.Tags {
+	tags := sdk.EmptyTags()
+
+	// only the denoms held by the pool can pay anything
+	for _, coin := range k.bankKeeper.GetCoins(ctx, ModuleAddress) {
+		for {
+			request, found := k.getFirstWithdrawalRequest(ctx, coin.Denom)
+			if !found {
+				break
+			}
+			paid, err := k.payWithdrawalRequest(ctx, request)
+			if err != nil {
+				ctx.Logger().Error(fmt.Sprintf("could not pay the queued withdrawal of %s to %s: %s", request.Amount, request.Depositor, err))
+				tags = tags.AppendTag(TagWithdrawalFailed, fmt.Sprintf("%s:%s", request.Depositor, request.Amount.Denom))
+				break
+			}
+			if !paid {
+				break
+			}
+		}
+	}
+	return tags
+}
+
+// payWithdrawalRequest pays as much of a queued withdrawal as the pool liquidity allows, returning whether it has been paid in full.
+// Nothing is written if the payment fails.
+func (k Keeper) payWithdrawalRequest(ctx sdk.Context, request WithdrawalRequest) (bool, sdk.Error) {
+	denom := request.Amount.Denom
+
+	// the funds of the depositor could have lost value since the request was queued
+	funds, err := k.GetAccountDenomFunds(ctx, request.Depositor, denom)
+	if err != nil {
+		k.deleteWithdrawalRequest(ctx, request)
+		return true, nil
+	}
+	available, err := k.GetAvailableLiquidity(ctx, denom)
+	if err != nil {
+		return false, err
+	}
+	amount := sdk.MinInt(sdk.MinInt(request.Amount.Amount, funds.Amount), available)
+	if !amount.IsPositive() {
+		return false, nil
+	}
+
+	cacheCtx, write := ctx.CacheContext()
+	err = k.WithdrawFundToAddress(cacheCtx, sdk.NewCoin(denom, amount), request.Depositor)
+	if err != nil {
+		return false, err
+	}
+	write()
+
+	if amount.Equal(request.Amount.Amount) || amount.Equal(funds.Amount) {
+		k.deleteWithdrawalRequest(ctx, request)
+		return true, nil
+	}
+	request.Amount.Amount = request.Amount.Amount.Sub(amount)
+	k.setWithdrawalRequest(ctx, request)
+	return false, nil
+}
+
+// ---------- Store Wrappers ----------
+
+var (
+	withdrawalQueueKeyPrefix      = []byte("withdrawalQueue:")      // denom -> ID -> request, ordered by ID
+	withdrawalRequestKeyPrefix    = []byte("withdrawalRequest:")    // account -> denom -> ID
+	withdrawalCountKeyPrefix      = []byte("withdrawalCount:")      // denom -> tree index -> number of requests, see addWithdrawalRequestCount
+	withdrawalQueueTotalKeyPrefix = []byte("withdrawalQueueTotal:") // denom -> amount queued
+	nextWithdrawalIDKey           = []byte("nextWithdrawalID")
+)
+
+func getWithdrawalQueueDenomPrefix(denom string) []byte {
+	return bytes.Join([][]byte{withdrawalQueueKeyPrefix, []byte(denom), []byte(":")}, nil)
+}
+func getWithdrawalQueueKey(denom string, id uint64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, id) // big endian keeps the requests ordered by ID
+	return bytes.Join([][]byte{getWithdrawalQueueDenomPrefix(denom), bz}, nil)
+}
+func getWithdrawalRequestKey(account sdk.AccAddress, denom string) []byte {
+	return bytes.Join([][]byte{withdrawalRequestKeyPrefix, account, []byte(denom)}, nil)
+}
+func getWithdrawalCountKey(denom string, index uint64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, index)
+	return bytes.Join([][]byte{withdrawalCountKeyPrefix, []byte(denom), []byte(":"), bz}, nil)
+}
+func getWithdrawalQueueTotalKey(denom string) []byte {
+	return bytes.Join([][]byte{withdrawalQueueTotalKeyPrefix, []byte(denom)}, nil)
+}
+
+func (k Keeper) getWithdrawalRequest(ctx sdk.Context, account sdk.AccAddress, denom string) (WithdrawalRequest, bool) {
+	store := ctx.KVStore(k.fundsStoreKey)
+	bz := store.Get(getWithdrawalRequestKey(account, denom))
+	if bz == nil {
+		return WithdrawalRequest{}, false
+	}
+	var id uint64
+	k.cdc.MustUnmarshalBinaryBare(bz, &id)
+	var request WithdrawalRequest
+	k.cdc.MustUnmarshalBinaryBare(store.Get(getWithdrawalQueueKey(denom, id)), &request)
+	return request, true
+}
+func (k Keeper) setWithdrawalRequest(ctx sdk.Context, request WithdrawalRequest) {
+	store := ctx.KVStore(k.fundsStoreKey)
+	key := getWithdrawalQueueKey(request.Amount.Denom, request.ID)
+	queued := request.Amount.Amount
+	if bz := store.Get(key); bz != nil {
+		var old WithdrawalRequest
+		k.cdc.MustUnmarshalBinaryBare(bz, &old)
+		queued = queued.Sub(old.Amount.Amount)
+	} else {
+		k.addWithdrawalRequestCount(ctx, request.Amount.Denom, request.ID, 1)
+	}
+	k.setQueuedWithdrawalsTotal(ctx, request.Amount.Denom, k.GetQueuedWithdrawalsTotal(ctx, request.Amount.Denom).Add(queued))
+	store.Set(key, k.cdc.MustMarshalBinaryBare(request))
+	store.Set(getWithdrawalRequestKey(request.Depositor, request.Amount.Denom), k.cdc.MustMarshalBinaryBare(request.ID))
+}
+func (k Keeper) deleteWithdrawalRequest(ctx sdk.Context, request WithdrawalRequest) {
+	store := ctx.KVStore(k.fundsStoreKey)
+	key := getWithdrawalQueueKey(request.Amount.Denom, request.ID)
+	bz := store.Get(key)
+	if bz == nil {
+		return
+	}
+	var old WithdrawalRequest
+	k.cdc.MustUnmarshalBinaryBare(bz, &old)
+	k.addWithdrawalRequestCount(ctx, request.Amount.Denom, request.ID, -1)
+	k.setQueuedWithdrawalsTotal(ctx, request.Amount.Denom, k.GetQueuedWithdrawalsTotal(ctx, request.Amount.Denom).Sub(old.Amount.Amount))
+	store.Delete(key)
+	store.Delete(getWithdrawalRequestKey(request.Depositor, request.Amount.Denom))
+}
+
+// getFirstWithdrawalRequest returns the request at the front of the queue of the given denom
+func (k Keeper) getFirstWithdrawalRequest(ctx sdk.Context, denom string) (WithdrawalRequest, bool) {
+	store := ctx.KVStore(k.fundsStoreKey)
+	iter := sdk.KVStorePrefixIterator(store, getWithdrawalQueueDenomPrefix(denom))
+	defer iter.Close()
+	if !iter.Valid() {
+		return WithdrawalRequest{}, false
+	}
+	var request WithdrawalRequest
+	k.cdc.MustUnmarshalBinaryBare(iter.Value(), &request)
+	return request, true
+}
+func (k Keeper) hasQueuedWithdrawals(ctx sdk.Context, denom string) bool {
+	store := ctx.KVStore(k.fundsStoreKey)
+	iter := sdk.KVStorePrefixIterator(store, getWithdrawalQueueDenomPrefix(denom))
+	defer iter.Close()
+	return iter.Valid()
+}
+
+func (k Keeper) setQueuedWithdrawalsTotal(ctx sdk.Context, denom string, total sdk.Int) {
+	store := ctx.KVStore(k.fundsStoreKey)
+	if total.IsZero() {
+		store.Delete(getWithdrawalQueueTotalKey(denom))
+		return
+	}
+	store.Set(getWithdrawalQueueTotalKey(denom), k.cdc.MustMarshalBinaryBare(total))
+}
+
+// The requests queued for each denom are counted by a binary indexed tree over the request IDs,
+// so the position of a request is found reading log(n) counts instead of walking the queue up to it.
+// The count at index i is the number of requests with IDs from i-1 to i-1+lowbit(i)-1, where lowbit(i) is the lowest set bit of i.
+// Queuing or removing a request updates one count for each bit set in its index, the positions are only needed by queries.
+
+// addWithdrawalRequestCount adds delta to the counts of the requests of the given denom covering the given ID
+func (k Keeper) addWithdrawalRequestCount(ctx sdk.Context, denom string, id uint64, delta int64) {
+	store := ctx.KVStore(k.fundsStoreKey)
+	for i := id + 1; i != 0; i -= i & -i {
+		key := getWithdrawalCountKey(denom, i)
+		count := k.getWithdrawalRequestCount(ctx, key) + delta
+		if count == 0 {
+			store.Delete(key)
+		} else {
+			store.Set(key, k.cdc.MustMarshalBinaryBare(count))
+		}
+	}
+}
+
+// countWithdrawalRequestsFrom returns the number of requests queued for the given denom with an ID from the given one on
+func (k Keeper) countWithdrawalRequestsFrom(ctx sdk.Context, denom string, id uint64) int64 {
+	var count int64
+	for i := id + 1; i != 0; i += i & -i { // stops when the index overflows past the last one
+		count += k.getWithdrawalRequestCount(ctx, getWithdrawalCountKey(denom, i))
+	}
+	return count
+}
+
+func (k Keeper) getWithdrawalRequestCount(ctx sdk.Context, key []byte) int64 {
+	bz := ctx.KVStore(k.fundsStoreKey).Get(key)
+	if bz == nil {
+		return 0
+	}
+	var count int64
+	k.cdc.MustUnmarshalBinaryBare(bz, &count)
+	return count
+}
+
+func (k Keeper) getNextWithdrawalID(ctx sdk.Context) uint64 {
+	store := ctx.KVStore(k.fundsStoreKey)
+	bz := store.Get(nextWithdrawalIDKey)
+	if bz == nil {
+		return 0
+	}
+	var id uint64
+	k.cdc.MustUnmarshalBinaryBare(bz, &id)
+	return id
+}
+func (k Keeper) setNextWithdrawalID(ctx sdk.Context, id uint64) {
+	store := ctx.KVStore(k.fundsStoreKey)
+	store.Set(nextWithdrawalIDKey, k.cdc.MustMarshalBinaryBare(id))
+}
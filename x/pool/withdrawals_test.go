@@ -0,0 +1,191 @@
+package pool
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeeper_RequestWithdrawal(t *testing.T) {
+	ctx, k, bk := setupTestKeeper()
+	_, addrs := mock.GeneratePrivKeyAddressPairs(3)
+	depositor, other, borrower := addrs[0], addrs[1], addrs[2]
+	bk.AddCoins(ctx, depositor, cs(c(stableDenom, 1000)))
+	bk.AddCoins(ctx, other, cs(c(stableDenom, 1000)))
+	require.NoError(t, k.DepositFundFromAddress(ctx, depositor, c(stableDenom, 500)))
+	require.NoError(t, k.DepositFundFromAddress(ctx, other, c(stableDenom, 500)))
+	require.NoError(t, k.DrawLiquidity(ctx, borrower, c(stableDenom, 800)))
+
+	// withdrawals are paid straight away when the pool has the liquidity
+	queued, err := k.RequestWithdrawal(ctx, depositor, c(stableDenom, 100))
+	require.NoError(t, err)
+	require.False(t, queued)
+	require.Equal(t, cs(c(stableDenom, 600)), bk.GetCoins(ctx, depositor))
+
+	// otherwise they're queued, without touching the funds
+	queued, err = k.RequestWithdrawal(ctx, depositor, c(stableDenom, 150))
+	require.NoError(t, err)
+	require.True(t, queued)
+	require.Equal(t, cs(c(stableDenom, 600)), bk.GetCoins(ctx, depositor))
+	require.Equal(t, c(stableDenom, 400), k.GetAccountDenomShares(ctx, depositor, stableDenom))
+
+	// later withdrawals queue behind, even if the pool could pay them
+	queued, err = k.RequestWithdrawal(ctx, other, c(stableDenom, 50))
+	require.NoError(t, err)
+	require.True(t, queued)
+
+	// a new withdrawal adds to the queued one, keeping its position
+	queued, err = k.RequestWithdrawal(ctx, depositor, c(stableDenom, 50))
+	require.NoError(t, err)
+	require.True(t, queued)
+	withdrawal, found := k.GetQueuedWithdrawal(ctx, depositor, stableDenom)
+	require.True(t, found)
+	require.Equal(t, c(stableDenom, 200), withdrawal.Amount)
+	require.Equal(t, int64(1), withdrawal.Position)
+	withdrawal, found = k.GetQueuedWithdrawal(ctx, other, stableDenom)
+	require.True(t, found)
+	require.Equal(t, c(stableDenom, 50), withdrawal.Amount)
+	require.Equal(t, int64(2), withdrawal.Position)
+	require.Equal(t, i(250), k.GetQueuedWithdrawalsTotal(ctx, stableDenom))
+
+	// withdrawals can't exceed the funds of the account, including the queued ones
+	_, err = k.RequestWithdrawal(ctx, depositor, c(stableDenom, 201))
+	require.Error(t, err)
+
+	// cancelling moves the requests behind forward
+	require.NoError(t, k.CancelWithdrawal(ctx, depositor, stableDenom))
+	_, found = k.GetQueuedWithdrawal(ctx, depositor, stableDenom)
+	require.False(t, found)
+	withdrawal, found = k.GetQueuedWithdrawal(ctx, other, stableDenom)
+	require.True(t, found)
+	require.Equal(t, int64(1), withdrawal.Position)
+	require.Equal(t, i(50), k.GetQueuedWithdrawalsTotal(ctx, stableDenom))
+	require.Error(t, k.CancelWithdrawal(ctx, depositor, stableDenom))
+}
+
+func TestKeeper_GetQueuedWithdrawal_Position(t *testing.T) {
+	ctx, k, bk := setupTestKeeper()
+	_, addrs := mock.GeneratePrivKeyAddressPairs(20)
+	for _, addr := range addrs {
+		bk.AddCoins(ctx, addr, cs(c(stableDenom, 10), c("xrp", 10)))
+		require.NoError(t, k.DepositFundFromAddress(ctx, addr, c(stableDenom, 10)))
+		require.NoError(t, k.DepositFundFromAddress(ctx, addr, c("xrp", 10)))
+	}
+	require.NoError(t, k.DrawLiquidity(ctx, addrs[0], c(stableDenom, 200)))
+	require.NoError(t, k.DrawLiquidity(ctx, addrs[0], c("xrp", 200)))
+
+	// queue everybody, interleaving the denoms
+	for _, addr := range addrs {
+		for _, denom := range []string{stableDenom, "xrp"} {
+			queued, err := k.RequestWithdrawal(ctx, addr, c(denom, 1))
+			require.NoError(t, err)
+			require.True(t, queued)
+		}
+	}
+
+	// cancel every third request of the stable coin
+	var expected []sdk.AccAddress
+	for n, addr := range addrs {
+		if n%3 == 0 {
+			require.NoError(t, k.CancelWithdrawal(ctx, addr, stableDenom))
+		} else {
+			expected = append(expected, addr)
+		}
+	}
+
+	// positions are counted within each denom
+	for n, addr := range expected {
+		withdrawal, found := k.GetQueuedWithdrawal(ctx, addr, stableDenom)
+		require.True(t, found)
+		require.Equal(t, int64(n+1), withdrawal.Position)
+	}
+	for n, addr := range addrs {
+		withdrawal, found := k.GetQueuedWithdrawal(ctx, addr, "xrp")
+		require.True(t, found)
+		require.Equal(t, int64(n+1), withdrawal.Position)
+	}
+}
+
+func TestKeeper_ProcessWithdrawalQueue(t *testing.T) {
+	ctx, k, bk := setupTestKeeper()
+	_, addrs := mock.GeneratePrivKeyAddressPairs(4)
+	first, second, third, borrower := addrs[0], addrs[1], addrs[2], addrs[3]
+	for _, addr := range []sdk.AccAddress{first, second, third} {
+		bk.AddCoins(ctx, addr, cs(c(stableDenom, 300)))
+		require.NoError(t, k.DepositFundFromAddress(ctx, addr, c(stableDenom, 300)))
+	}
+	require.NoError(t, k.DrawLiquidity(ctx, borrower, c(stableDenom, 900)))
+	for _, addr := range []sdk.AccAddress{first, second, third} {
+		queued, err := k.RequestWithdrawal(ctx, addr, c(stableDenom, 100))
+		require.NoError(t, err)
+		require.True(t, queued)
+	}
+
+	// nothing is paid without liquidity
+	require.Empty(t, EndBlocker(ctx, k))
+	require.True(t, bk.GetCoins(ctx, first).Empty())
+
+	// the queued liquidity can't be drawn
+	require.NoError(t, k.RepayLiquidity(ctx, borrower, c(stableDenom, 250)))
+	drawable, err := k.GetDrawableLiquidity(ctx, stableDenom)
+	require.NoError(t, err)
+	require.True(t, drawable.IsZero())
+	require.Error(t, k.DrawLiquidity(ctx, borrower, c(stableDenom, 1)))
+
+	// requests are paid in order, the last one in part
+	require.Empty(t, EndBlocker(ctx, k))
+	require.Equal(t, cs(c(stableDenom, 100)), bk.GetCoins(ctx, first))
+	require.Equal(t, cs(c(stableDenom, 100)), bk.GetCoins(ctx, second))
+	require.Equal(t, cs(c(stableDenom, 50)), bk.GetCoins(ctx, third))
+	_, found := k.GetQueuedWithdrawal(ctx, first, stableDenom)
+	require.False(t, found)
+	withdrawal, found := k.GetQueuedWithdrawal(ctx, third, stableDenom)
+	require.True(t, found)
+	require.Equal(t, c(stableDenom, 50), withdrawal.Amount)
+	require.Equal(t, int64(1), withdrawal.Position)
+	require.Equal(t, c(stableDenom, 250), k.GetAccountDenomShares(ctx, third, stableDenom))
+	require.Equal(t, i(50), k.GetQueuedWithdrawalsTotal(ctx, stableDenom))
+
+	// the rest is paid once more liquidity is repaid, leaving the extra liquidity drawable
+	require.NoError(t, k.RepayLiquidity(ctx, borrower, c(stableDenom, 100)))
+	require.Empty(t, EndBlocker(ctx, k))
+	require.Equal(t, cs(c(stableDenom, 100)), bk.GetCoins(ctx, third))
+	_, found = k.GetQueuedWithdrawal(ctx, third, stableDenom)
+	require.False(t, found)
+	require.True(t, k.GetQueuedWithdrawalsTotal(ctx, stableDenom).IsZero())
+	drawable, err = k.GetDrawableLiquidity(ctx, stableDenom)
+	require.NoError(t, err)
+	require.Equal(t, i(50), drawable)
+}
+
+func TestKeeper_ProcessWithdrawalQueue_LostFunds(t *testing.T) {
+	ctx, k, bk := setupTestKeeper()
+	_, addrs := mock.GeneratePrivKeyAddressPairs(3)
+	depositor, other, borrower := addrs[0], addrs[1], addrs[2]
+	bk.AddCoins(ctx, depositor, cs(c(stableDenom, 100)))
+	bk.AddCoins(ctx, other, cs(c(stableDenom, 100)))
+	require.NoError(t, k.DepositFundFromAddress(ctx, depositor, c(stableDenom, 100)))
+	require.NoError(t, k.DepositFundFromAddress(ctx, other, c(stableDenom, 100)))
+	require.NoError(t, k.DrawLiquidity(ctx, borrower, c(stableDenom, 200)))
+	queued, err := k.RequestWithdrawal(ctx, depositor, c(stableDenom, 100))
+	require.NoError(t, err)
+	require.True(t, queued)
+	queued, err = k.RequestWithdrawal(ctx, other, c(stableDenom, 20))
+	require.NoError(t, err)
+	require.True(t, queued)
+
+	// the pool loses value, so the first request asks for more than its funds are now worth
+	require.NoError(t, k.RepayLiquidity(ctx, borrower, c(stableDenom, 150)))
+	bk.SubtractCoins(ctx, ModuleAddress, cs(c(stableDenom, 50)))
+
+	// the first depositor gets what its funds are worth and the next request is paid too
+	require.Empty(t, EndBlocker(ctx, k))
+	require.Equal(t, cs(c(stableDenom, 75)), bk.GetCoins(ctx, depositor))
+	require.Equal(t, cs(c(stableDenom, 20)), bk.GetCoins(ctx, other))
+	_, found := k.GetQueuedWithdrawal(ctx, depositor, stableDenom)
+	require.False(t, found)
+	_, found = k.GetQueuedWithdrawal(ctx, other, stableDenom)
+	require.False(t, found)
+}
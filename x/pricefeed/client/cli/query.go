@@ -71,3 +71,22 @@ func GetCmdAssets(queryRoute string, cdc *codec.Codec) *cobra.Command {
 		},
 	}
 }
+
+// GetCmdOracles queries list of oracles in the pricefeed, with the assets they are assigned to
+func GetCmdOracles(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "oracles",
+		Short: "get the oracles in the pricefeed and the assets they can post the prices of",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			res, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", queryRoute, pricefeed.QueryOracles), nil)
+			if err != nil {
+				fmt.Printf("could not get oracles")
+				return nil
+			}
+			var out pricefeed.QueryOraclesResp
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
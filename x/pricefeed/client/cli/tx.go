@@ -9,7 +9,10 @@ import (
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	authtxb "github.com/cosmos/cosmos-sdk/x/auth/client/txbuilder"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+	govcli "github.com/cosmos/cosmos-sdk/x/gov/client/cli"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // GetCmdPostPrice cli command for posting prices.
@@ -47,3 +50,62 @@ func GetCmdPostPrice(cdc *codec.Codec) *cobra.Command {
 		},
 	}
 }
+
+// GetCmdSubmitAddOracleProposal implements the command to submit an add oracle proposal
+func GetCmdSubmitAddOracleProposal(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-oracle [oracle-address] [asset-name]...",
+		Short: "Submit a proposal to let an address post the prices of the given assets, or of any asset if none is given",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return submitOracleProposal(cdc, args, func(title, description string, oracle sdk.AccAddress, assetNames []string) gov.Content {
+				return pricefeed.NewAddOracleProposal(title, description, oracle, assetNames)
+			})
+		},
+	}
+	addOracleProposalFlags(cmd)
+	return cmd
+}
+
+// GetCmdSubmitRemoveOracleProposal implements the command to submit a remove oracle proposal
+func GetCmdSubmitRemoveOracleProposal(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove-oracle [oracle-address] [asset-name]...",
+		Short: "Submit a proposal to stop an oracle from posting the prices of the given assets, or to remove it if none is given",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return submitOracleProposal(cdc, args, func(title, description string, oracle sdk.AccAddress, assetNames []string) gov.Content {
+				return pricefeed.NewRemoveOracleProposal(title, description, oracle, assetNames)
+			})
+		},
+	}
+	addOracleProposalFlags(cmd)
+	return cmd
+}
+
+func addOracleProposalFlags(cmd *cobra.Command) {
+	cmd.Flags().String(govcli.FlagTitle, "", "title of proposal")
+	cmd.Flags().String(govcli.FlagDescription, "", "description of proposal")
+	cmd.Flags().String(govcli.FlagDeposit, "", "deposit of proposal")
+}
+
+func submitOracleProposal(cdc *codec.Codec, args []string, newContent func(string, string, sdk.AccAddress, []string) gov.Content) error {
+	txBldr := authtxb.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+	cliCtx := context.NewCLIContext().WithCodec(cdc).WithAccountDecoder(cdc)
+
+	oracle, err := sdk.AccAddressFromBech32(args[0])
+	if err != nil {
+		return err
+	}
+	deposit, err := sdk.ParseCoins(viper.GetString(govcli.FlagDeposit))
+	if err != nil {
+		return err
+	}
+
+	content := newContent(viper.GetString(govcli.FlagTitle), viper.GetString(govcli.FlagDescription), oracle, args[1:])
+	msg := gov.NewMsgSubmitProposal(content, deposit, cliCtx.GetFromAddress())
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+	return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+}
@@ -31,6 +31,7 @@ func (mc ModuleClient) GetQueryCmd() *cobra.Command {
 		pricefeedcmd.GetCmdCurrentPrice(mc.storeKey, mc.cdc),
 		pricefeedcmd.GetCmdRawPrices(mc.storeKey, mc.cdc),
 		pricefeedcmd.GetCmdAssets(mc.storeKey, mc.cdc),
+		pricefeedcmd.GetCmdOracles(mc.storeKey, mc.cdc),
 	)...)
 
 	return pricefeedQueryCmd
@@ -7,6 +7,8 @@ import (
 // RegisterCodec registers concrete types on the Amino codec
 func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgPostPrice{}, "pricefeed/MsgPostPrice", nil)
+	cdc.RegisterConcrete(AddOracleProposal{}, "pricefeed/AddOracleProposal", nil)
+	cdc.RegisterConcrete(RemoveOracleProposal{}, "pricefeed/RemoveOracleProposal", nil)
 }
 
 // generic sealed codec to be used throughout module
@@ -1,43 +1,152 @@
 package pricefeed
 
-import sdk "github.com/cosmos/cosmos-sdk/types"
+import (
+	"fmt"
+
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
 
 // GenesisState state at gensis
 type GenesisState struct {
-	Assets  []Asset
-	Oracles []Oracle
+	Params             PricefeedModuleParams `json:"params"`
+	Assets             []Asset               `json:"assets"`
+	Oracles            []Oracle              `json:"oracles"`
+	RawPrices          []types.PostedPrice   `json:"raw_prices"`
+	CurrentPrices      []types.CurrentPrice  `json:"current_prices"`
+	PendingPriceAssets []PendingPriceAsset   `json:"pending_price_assets"`
 }
 
 // InitGenesis sets distribution information for genesis.
 func InitGenesis(ctx sdk.Context, keeper Keeper, genState GenesisState) {
-	for _, asset := range genState.Assets {
-		keeper.AddAsset(ctx, asset.AssetCode, asset.Description)
+	if genState.Params.OracleQuorum.IsNil() {
+		genState.Params.OracleQuorum = DefaultParams().OracleQuorum // genesis files from before the quorum was a param
+	}
+	keeper.setParams(ctx, genState.Params)
+
+	// empty lists can't be stored, they are read back as empty anyway
+	if len(genState.Assets) > 0 {
+		keeper.setAssets(ctx, genState.Assets)
+	}
+	if len(genState.Oracles) > 0 {
+		keeper.setOracles(ctx, genState.Oracles)
+	}
+
+	// raw prices are stored in one list per asset
+	rawPrices := make(map[assetKey][]types.PostedPrice)
+	var rawPriceAssets []assetKey
+	for _, price := range genState.RawPrices {
+		asset := newAssetKey(price.AssetName, price.AssetCode)
+		if _, ok := rawPrices[asset]; !ok {
+			rawPriceAssets = append(rawPriceAssets, asset)
+		}
+		rawPrices[asset] = append(rawPrices[asset], price)
+	}
+	for _, asset := range rawPriceAssets {
+		keeper.setRawPrices(ctx, asset.AssetCode, asset.AssetName, rawPrices[asset])
 	}
 
-	for _, oracle := range genState.Oracles {
-		keeper.AddOracle(ctx, oracle.OracleAddress)
+	for _, price := range genState.CurrentPrices {
+		keeper.setCurrentPrice(ctx, price)
+	}
+	if len(genState.PendingPriceAssets) > 0 {
+		keeper.setPendingPriceAssets(ctx, genState.PendingPriceAssets)
 	}
+	keeper.setPriceKeysMigrated(ctx) // a new store has no legacy price keys
 }
 
 // DefaultGenesisState returns a default genesis state
 func DefaultGenesisState() GenesisState {
 	return GenesisState{
+		DefaultParams(),
 		[]Asset{
 			{Type: "ft", AssetName: "btc", Description: "a description"},
 			{Type: "nft", AssetName: "xrp", Description: "the standard"},
+			{Type: "ft", AssetName: "uatom", Description: "the stable coin"},
 		},
-		[]Oracle{}}
+		[]Oracle{},
+		[]types.PostedPrice{},
+		[]types.CurrentPrice{},
+		[]PendingPriceAsset{},
+	}
 }
 
 // ValidateGenesis performs basic validation of genesis data returning an
 // error for any failed validation criteria.
 func ValidateGenesis(data GenesisState) error {
-	// TODO
+	if !data.Params.OracleQuorum.IsNil() { // a missing quorum is set to the default one by InitGenesis
+		if err := data.Params.Validate(); err != nil {
+			return err
+		}
+	}
+
+	assets := make(map[string]bool)
+	assetNames := make(map[string]bool)
+	for _, asset := range data.Assets {
+		if err := ValidateAsset(asset); err != nil {
+			return err
+		}
+		key := asset.AssetName + ":" + asset.AssetCode
+		if assets[key] {
+			return fmt.Errorf("asset %s %s is repeated", asset.AssetName, asset.AssetCode)
+		}
+		assets[key] = true
+		assetNames[asset.AssetName] = true
+	}
+
+	oracles := make(map[string]Oracle)
+	for _, oracle := range data.Oracles {
+		if _, err := sdk.AccAddressFromBech32(oracle.OracleAddress); err != nil {
+			return fmt.Errorf("invalid oracle address %q: %s", oracle.OracleAddress, err)
+		}
+		if _, ok := oracles[oracle.OracleAddress]; ok {
+			return fmt.Errorf("oracle %s is repeated", oracle.OracleAddress)
+		}
+		oracles[oracle.OracleAddress] = oracle
+		assigned := make(map[string]bool)
+		for _, name := range oracle.AssetNames {
+			if !assetNames[name] || assigned[name] {
+				return fmt.Errorf("oracle %s is assigned to unknown or repeated asset %s", oracle.OracleAddress, name)
+			}
+			assigned[name] = true
+		}
+	}
+
+	for _, price := range data.RawPrices {
+		if !assets[price.AssetName+":"+price.AssetCode] {
+			return fmt.Errorf("price posted for unknown asset %s %s", price.AssetName, price.AssetCode)
+		}
+		if oracle, ok := oracles[price.OracleAddress]; !ok || !oracle.CanPostPrice(price.AssetName) {
+			return fmt.Errorf("price for asset %s %s posted by unknown oracle %s", price.AssetName, price.AssetCode, price.OracleAddress)
+		}
+	}
+	for _, price := range data.CurrentPrices {
+		if !assets[price.AssetName+":"+price.AssetCode] {
+			return fmt.Errorf("current price for unknown asset %s %s", price.AssetName, price.AssetCode)
+		}
+	}
+	return nil
+}
+
+// ValidateAsset checks an asset has a name and a valid type
+func ValidateAsset(asset Asset) error {
+	if len(asset.AssetName) == 0 {
+		return fmt.Errorf("asset must have a name")
+	}
+	if asset.Type != "ft" && asset.Type != "nft" {
+		return fmt.Errorf("asset %s has invalid type %q, expected ft or nft", asset.AssetName, asset.Type)
+	}
 	return nil
 }
 
 // ExportGenesis returns a GenesisState for a given context and keeper.
 func ExportGenesis(ctx sdk.Context, keeper Keeper) GenesisState {
-	// TODO implement this
-	return DefaultGenesisState()
+	return GenesisState{
+		Params:             keeper.GetParams(ctx),
+		Assets:             keeper.GetAssets(ctx),
+		Oracles:            keeper.GetOracles(ctx),
+		RawPrices:          keeper.getAllRawPrices(ctx),
+		CurrentPrices:      keeper.getAllCurrentPrices(ctx),
+		PendingPriceAssets: keeper.GetPendingPriceAssets(ctx),
+	}
 }
@@ -0,0 +1,144 @@
+package pricefeed
+
+import (
+	"testing"
+
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto"
+)
+
+func TestExportImportGenesis(t *testing.T) {
+	// Setup
+	helper := getMockApp(t, 2, GenesisState{}, nil)
+	header := abci.Header{Height: helper.mApp.LastBlockHeight() + 1}
+	helper.mApp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := helper.mApp.BaseApp.NewContext(false, header)
+	oracle1, oracle2 := helper.addrs[0].String(), helper.addrs[1].String()
+	genesis := GenesisState{
+		Params: PricefeedModuleParams{OracleQuorum: sdk.MustNewDecFromStr("0.75")},
+		Assets: []Asset{
+			{Type: "ft", AssetName: "btc", Description: "a description"},
+			{Type: "nft", AssetName: "art", AssetCode: "1", Description: "a painting"},
+		},
+		Oracles: []Oracle{{OracleAddress: oracle1}, {OracleAddress: oracle2, AssetNames: []string{"btc"}}},
+		// prices are exported in store order, fungible tokens first as their asset code is empty
+		RawPrices: []types.PostedPrice{
+			{AssetName: "btc", OracleAddress: oracle1, Price: sdk.NewInt(8000), Expiry: sdk.NewInt(100)},
+			{AssetName: "btc", OracleAddress: oracle2, Price: sdk.NewInt(8100), Expiry: sdk.NewInt(90)},
+			{AssetName: "art", AssetCode: "1", OracleAddress: oracle1, Price: sdk.NewInt(500), Expiry: sdk.NewInt(100)},
+		},
+		CurrentPrices: []types.CurrentPrice{
+			{AssetName: "btc", Price: sdk.NewInt(8050), Expiry: sdk.NewInt(95)},
+			{AssetName: "art", AssetCode: "1", Price: sdk.NewInt(500), Expiry: sdk.NewInt(100), Stale: true},
+		},
+		PendingPriceAssets: []PendingPriceAsset{{AssetName: "art", AssetCode: "2"}},
+	}
+	require.NoError(t, ValidateGenesis(genesis))
+
+	// Run test function
+	InitGenesis(ctx, helper.keeper, genesis)
+	exported := ExportGenesis(ctx, helper.keeper)
+
+	// Check
+	require.Equal(t, genesis, exported)
+}
+
+func TestExportGenesis_RemovedOracle(t *testing.T) {
+	// Setup two oracles posting the prices of two assets
+	helper := getMockApp(t, 2, GenesisState{}, nil)
+	header := abci.Header{Height: helper.mApp.LastBlockHeight() + 1}
+	helper.mApp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := helper.mApp.BaseApp.NewContext(false, header)
+	oracle1, oracle2 := helper.addrs[0].String(), helper.addrs[1].String()
+	InitGenesis(ctx, helper.keeper, GenesisState{
+		Params:  DefaultParams(),
+		Assets:  []Asset{{Type: "ft", AssetName: "btc", Description: "a description"}, {Type: "ft", AssetName: "xrp", Description: "a description"}},
+		Oracles: []Oracle{{OracleAddress: oracle1}, {OracleAddress: oracle2, AssetNames: []string{"btc", "xrp"}}},
+	})
+	for _, addr := range helper.addrs {
+		for _, asset := range []string{"btc", "xrp"} {
+			_, err := helper.keeper.SetPrice(ctx, addr, "", asset, sdk.NewInt(100), sdk.NewInt(100))
+			require.NoError(t, err)
+		}
+	}
+
+	// Unassign an oracle from an asset and remove the other one altogether
+	require.NoError(t, helper.keeper.RemoveOracle(ctx, oracle2, []string{"btc"}))
+	require.NoError(t, helper.keeper.RemoveOracle(ctx, oracle1, nil))
+
+	// Check only the prices the remaining oracle can post are left, and the exported genesis is valid
+	exported := ExportGenesis(ctx, helper.keeper)
+	require.Equal(t, []types.PostedPrice{{AssetName: "xrp", OracleAddress: oracle2, Price: sdk.NewInt(100), Expiry: sdk.NewInt(100)}},
+		exported.RawPrices)
+	require.NoError(t, ValidateGenesis(exported))
+	require.Empty(t, helper.keeper.GetRawPrices(ctx, "", "btc"))
+}
+
+func TestInitGenesis_MissingQuorum(t *testing.T) {
+	helper := getMockApp(t, 1, GenesisState{}, nil)
+	header := abci.Header{Height: helper.mApp.LastBlockHeight() + 1}
+	helper.mApp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := helper.mApp.BaseApp.NewContext(false, header)
+
+	// genesis files from before the quorum was a param get the default one
+	genesis := DefaultGenesisState()
+	genesis.Params = PricefeedModuleParams{}
+	require.NoError(t, ValidateGenesis(genesis))
+	InitGenesis(ctx, helper.keeper, genesis)
+	require.Equal(t, DefaultParams(), helper.keeper.GetParams(ctx))
+	require.Equal(t, DefaultParams(), ExportGenesis(ctx, helper.keeper).Params)
+}
+
+func TestValidateGenesis(t *testing.T) {
+	oracle := sdk.AccAddress(crypto.AddressHash([]byte("someName"))).String()
+	tests := []struct {
+		name       string
+		modify     func(*GenesisState)
+		expectPass bool
+	}{
+		{"default", func(*GenesisState) {}, true},
+		{"noQuorum", func(g *GenesisState) { g.Params.OracleQuorum = sdk.ZeroDec() }, true},
+		{"fullQuorum", func(g *GenesisState) { g.Params.OracleQuorum = sdk.OneDec() }, true},
+		{"missingQuorum", func(g *GenesisState) { g.Params = PricefeedModuleParams{} }, true},
+		{"negativeQuorum", func(g *GenesisState) { g.Params.OracleQuorum = sdk.NewDec(-1) }, false},
+		{"quorumAboveOne", func(g *GenesisState) { g.Params.OracleQuorum = sdk.MustNewDecFromStr("1.01") }, false},
+		{"invalidType", func(g *GenesisState) { g.Assets[0].Type = "coin" }, false},
+		{"emptyName", func(g *GenesisState) { g.Assets[0].AssetName = "" }, false},
+		{"repeatedAsset", func(g *GenesisState) { g.Assets[1] = g.Assets[0] }, false},
+		{"invalidOracle", func(g *GenesisState) { g.Oracles = []Oracle{{OracleAddress: "someName"}} }, false},
+		{"repeatedOracle", func(g *GenesisState) { g.Oracles = []Oracle{{OracleAddress: oracle}, {OracleAddress: oracle}} }, false},
+		{"assignedOracle", func(g *GenesisState) {
+			g.Oracles = []Oracle{{OracleAddress: oracle, AssetNames: []string{"btc", "xrp"}}}
+			g.RawPrices = []types.PostedPrice{{AssetName: "btc", OracleAddress: oracle, Price: sdk.NewInt(1), Expiry: sdk.NewInt(1)}}
+		}, true},
+		{"oracleOfUnknownAsset", func(g *GenesisState) { g.Oracles = []Oracle{{OracleAddress: oracle, AssetNames: []string{"eth"}}} }, false},
+		{"oracleOfRepeatedAsset", func(g *GenesisState) {
+			g.Oracles = []Oracle{{OracleAddress: oracle, AssetNames: []string{"btc", "btc"}}}
+		}, false},
+		{"priceFromUnassignedOracle", func(g *GenesisState) {
+			g.Oracles = []Oracle{{OracleAddress: oracle, AssetNames: []string{"xrp"}}}
+			g.RawPrices = []types.PostedPrice{{AssetName: "btc", OracleAddress: oracle, Price: sdk.NewInt(1), Expiry: sdk.NewInt(1)}}
+		}, false},
+		{"priceFromUnknownOracle", func(g *GenesisState) {
+			g.RawPrices = []types.PostedPrice{{AssetName: "btc", OracleAddress: oracle, Price: sdk.NewInt(1), Expiry: sdk.NewInt(1)}}
+		}, false},
+		{"priceOfUnknownAsset", func(g *GenesisState) {
+			g.CurrentPrices = []types.CurrentPrice{{AssetName: "eth", Price: sdk.NewInt(1), Expiry: sdk.NewInt(1)}}
+		}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			genesis := DefaultGenesisState()
+			tc.modify(&genesis)
+			err := ValidateGenesis(genesis)
+			if tc.expectPass {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
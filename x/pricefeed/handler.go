@@ -35,7 +35,7 @@ func HandleMsgPostPrice(ctx sdk.Context, k Keeper, msg MsgPostPrice) sdk.Result
 		return err.Result()
 	}
 
-	_, err = k.SetPrice(ctx, msg.From, msg.AssetName, msg.AssetCode, msg.Price, msg.Expiry)
+	_, err = k.SetPrice(ctx, msg.From, msg.AssetCode, msg.AssetName, msg.Price, msg.Expiry)
 	if err != nil {
 		return err.Result()
 	}
@@ -51,7 +51,7 @@ func EndBlocker(ctx sdk.Context, k Keeper) sdk.Tags {
 	// which seems preferable to having state storage values change in response to multiple transactions
 	// which occur during a block
 	//TODO use an iterator and update the prices for all assets in the store
-	k.SetCurrentPrices(ctx)
+	tags, _ := k.SetCurrentPrices(ctx)
 
-	return sdk.Tags{}
+	return tags
 }
@@ -1,12 +1,15 @@
 package pricefeed
 
 import (
+	"encoding/binary"
+	"fmt"
 	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
 	"sort"
 	"strings"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/params"
 )
 
 // TODO refactor constants to app.go
@@ -43,42 +46,137 @@ const (
 
 	// EstimableAssetPrefix store prefix for the estimable assets
 	EstimableAssetPrefix = StoreKey + ":estimableassets"
+
+	// TagStalePrice tag of the assets whose price is not updated for lack of oracle quorum, as name:code
+	TagStalePrice = "stale-price"
 )
 
 // Keeper struct for pricefeed module
 type Keeper struct {
-	priceStoreKey          sdk.StoreKey
-	pricesRequestsStoreKey sdk.StoreKey
-	cdc                    *codec.Codec
-	codespace              sdk.CodespaceType
-	cdpKeeper              types.CdpKeeper
+	priceStoreKey  sdk.StoreKey
+	cdc            *codec.Codec
+	paramsSubspace params.Subspace
+	codespace      sdk.CodespaceType
+	cdpKeeper      types.CdpKeeper
 }
 
 // NewKeeper returns a new keeper for the pricefeed modle
-func NewKeeper(storeKey sdk.StoreKey, cdc *codec.Codec, codespace sdk.CodespaceType, cdpKeeper types.CdpKeeper) Keeper {
+func NewKeeper(storeKey sdk.StoreKey, cdc *codec.Codec, subspace params.Subspace, codespace sdk.CodespaceType, cdpKeeper types.CdpKeeper) Keeper {
 	return Keeper{
-		priceStoreKey: storeKey,
-		cdc:           cdc,
-		codespace:     codespace,
-		cdpKeeper:     cdpKeeper,
+		priceStoreKey:  storeKey,
+		cdc:            cdc,
+		paramsSubspace: subspace.WithKeyTable(ParamKeyTable()),
+		codespace:      codespace,
+		cdpKeeper:      cdpKeeper,
 	}
 }
 
-func (k Keeper) combineAssetInfo(assetCode string, assetName string) string {
-	return assetCode + "++" + assetName
+// GetParams returns the params of the pricefeed module, or the default ones if none have been set yet
+func (k Keeper) GetParams(ctx sdk.Context) PricefeedModuleParams {
+	if !k.paramsSubspace.Has(ctx, moduleParamsKey) {
+		return DefaultParams()
+	}
+	var p PricefeedModuleParams
+	k.paramsSubspace.Get(ctx, moduleParamsKey, &p)
+	return p
 }
 
-func (k Keeper) getAssetCodeAndName(code string) (string, string) {
-	result := strings.Split(code, "++")
-	return result[0], result[1]
+func (k Keeper) setParams(ctx sdk.Context, p PricefeedModuleParams) {
+	k.paramsSubspace.Set(ctx, moduleParamsKey, &p)
 }
 
-// AddOracle adds an Oracle to the store
-func (k Keeper) AddOracle(ctx sdk.Context, address string) {
+// AddOracle adds an Oracle to the store, assigned to the given assets or to any asset if none is given.
+// An oracle already in the store is assigned to the given assets as well.
+func (k Keeper) AddOracle(ctx sdk.Context, address string, assetNames []string) {
+	oracles := k.GetOracles(ctx)
+	for i := range oracles {
+		if oracles[i].OracleAddress != address {
+			continue
+		}
+		if len(assetNames) == 0 {
+			oracles[i].AssetNames = nil
+		} else if len(oracles[i].AssetNames) > 0 {
+			for _, name := range assetNames {
+				if !oracles[i].CanPostPrice(name) {
+					oracles[i].AssetNames = append(oracles[i].AssetNames, name)
+				}
+			}
+		}
+		k.setOracles(ctx, oracles)
+		return
+	}
+
+	oracles = append(oracles, Oracle{OracleAddress: address, AssetNames: assetNames})
+	k.setOracles(ctx, oracles)
+}
 
+// RemoveOracle unassigns an Oracle from the given assets, or removes it from the store if none is given.
+// An oracle left without assets is removed as well, as it would otherwise be able to post the price of any asset.
+func (k Keeper) RemoveOracle(ctx sdk.Context, address string, assetNames []string) sdk.Error {
 	oracles := k.GetOracles(ctx)
-	oracles = append(oracles, Oracle{OracleAddress: address})
+	for i, oracle := range oracles {
+		if oracle.OracleAddress != address {
+			continue
+		}
+		if len(assetNames) > 0 {
+			if len(oracle.AssetNames) == 0 {
+				return sdk.ErrInternal("oracle is assigned to any asset, it can only be removed altogether")
+			}
+			unassigned := make(map[string]bool)
+			for _, name := range assetNames {
+				if !oracle.CanPostPrice(name) {
+					return sdk.ErrInternal(fmt.Sprintf("oracle is not assigned to asset %s", name))
+				}
+				unassigned[name] = true
+			}
+			var remaining []string
+			for _, name := range oracle.AssetNames {
+				if !unassigned[name] {
+					remaining = append(remaining, name)
+				}
+			}
+			if len(remaining) > 0 {
+				oracles[i].AssetNames = remaining
+				k.setOracles(ctx, oracles)
+				k.deleteOracleRawPrices(ctx, address, assetNames)
+				return nil
+			}
+		}
+
+		k.setOracles(ctx, append(oracles[:i], oracles[i+1:]...))
+		k.deleteOracleRawPrices(ctx, address, assetNames)
+		return nil
+	}
+	return ErrInvalidOracle(k.codespace)
+}
+
+// deleteOracleRawPrices deletes the prices posted by an oracle for the given assets, or for all of them if none is given.
+func (k Keeper) deleteOracleRawPrices(ctx sdk.Context, address string, assetNames []string) {
+	unassigned := Oracle{OracleAddress: address, AssetNames: assetNames}
+	for _, asset := range k.GetAssets(ctx) {
+		if !unassigned.CanPostPrice(asset.AssetName) {
+			continue
+		}
+		prices := k.GetRawPrices(ctx, asset.AssetCode, asset.AssetName)
+		var remaining []types.PostedPrice
+		for _, price := range prices {
+			if price.OracleAddress != address {
+				remaining = append(remaining, price)
+			}
+		}
+		if len(remaining) < len(prices) {
+			k.setRawPrices(ctx, asset.AssetCode, asset.AssetName, remaining)
+		}
+	}
+}
+
+func (k Keeper) setOracles(ctx sdk.Context, oracles []Oracle) {
 	store := ctx.KVStore(k.priceStoreKey)
+	if len(oracles) == 0 {
+		// empty lists can't be stored
+		store.Delete([]byte(OraclePrefix))
+		return
+	}
 	store.Set(
 		[]byte(OraclePrefix), k.cdc.MustMarshalBinaryBare(oracles),
 	)
@@ -88,15 +186,30 @@ func (k Keeper) AddOracle(ctx sdk.Context, address string) {
 func (k Keeper) AddAsset(ctx sdk.Context, assetCode string, desc string) {
 	assets := k.GetAssets(ctx)
 	assets = append(assets, Asset{AssetCode: assetCode, Description: desc})
+	k.setAssets(ctx, assets)
+}
+
+// RegisterAsset adds a new asset to the store, as listed through governance
+func (k Keeper) RegisterAsset(ctx sdk.Context, asset Asset) sdk.Error {
+	if err := ValidateAsset(asset); err != nil {
+		return sdk.ErrInternal(err.Error())
+	}
+	if _, found := k.GetAsset(ctx, asset.AssetCode, asset.AssetName); found {
+		return sdk.ErrInternal(fmt.Sprintf("asset %s %s is already in the pricefeed", asset.AssetName, asset.AssetCode))
+	}
+	k.setAssets(ctx, append(k.GetAssets(ctx), asset))
+	return nil
+}
+
+func (k Keeper) setAssets(ctx sdk.Context, assets []Asset) {
 	store := ctx.KVStore(k.priceStoreKey)
 	store.Set([]byte(AssetPrefix), k.cdc.MustMarshalBinaryBare(assets))
 }
 
 // SetPrice updates the posted price for a specific oracle
-func (k Keeper) SetPrice(ctx sdk.Context, oracle sdk.AccAddress, assetName string, assetCode string, price sdk.Int, expiry sdk.Int) (types.PostedPrice, sdk.Error) {
+func (k Keeper) SetPrice(ctx sdk.Context, oracle sdk.AccAddress, assetCode string, assetName string, price sdk.Int, expiry sdk.Int) (types.PostedPrice, sdk.Error) {
 	// If the expiry is less than or equal to the current blockheight, we consider the price valid
 	if expiry.GTE(sdk.NewInt(ctx.BlockHeight())) {
-		store := ctx.KVStore(k.priceStoreKey)
 		prices := k.GetRawPrices(ctx, assetCode, assetName)
 		var index int
 		found := false
@@ -109,7 +222,7 @@ func (k Keeper) SetPrice(ctx sdk.Context, oracle sdk.AccAddress, assetName strin
 		}
 		// set the price for that particular oracle
 		if found {
-			prices[index] = types.PostedPrice{AssetCode: assetCode, OracleAddress: oracle.String(), Price: price, Expiry: expiry}
+			prices[index] = types.PostedPrice{AssetName: assetName, AssetCode: assetCode, OracleAddress: oracle.String(), Price: price, Expiry: expiry}
 		} else {
 			prices = append(prices, types.PostedPrice{
 				AssetName:     assetName,
@@ -121,11 +234,8 @@ func (k Keeper) SetPrice(ctx sdk.Context, oracle sdk.AccAddress, assetName strin
 			index = len(prices) - 1
 		}
 
-		store.Set([]byte(RawPriceFeedPrefix+k.combineAssetInfo(assetCode, assetName)), k.cdc.MustMarshalBinaryBare(prices))
-		err := k.cdpKeeper.ModifyCDPType(ctx, assetName, assetCode)
-		if err != nil {
-			return types.PostedPrice{}, err
-		}
+		k.setRawPrices(ctx, assetCode, assetName, prices)
+		k.cdpKeeper.ModifyCDPType(ctx, assetName, assetCode)
 
 		return prices[index], nil
 	}
@@ -134,16 +244,30 @@ func (k Keeper) SetPrice(ctx sdk.Context, oracle sdk.AccAddress, assetName strin
 
 }
 
-// SetCurrentPrices updates the price of an asset to the median of all valid oracle inputs
-func (k Keeper) SetCurrentPrices(ctx sdk.Context) sdk.Error {
+// SetCurrentPrices updates the price of an asset to the median of all valid oracle inputs.
+// When fewer oracles than the quorum have posted a valid price, the last price of the asset is kept and flagged stale,
+// and a tag is returned for the asset when its price turns stale.
+func (k Keeper) SetCurrentPrices(ctx sdk.Context) (sdk.Tags, sdk.Error) {
+	quorum := k.GetParams(ctx).OracleQuorum
+	oracles := k.GetOracles(ctx)
+	assignedOracles := make(map[string]Oracle)
+	for _, oracle := range oracles {
+		assignedOracles[oracle.OracleAddress] = oracle
+	}
+	tags := sdk.EmptyTags()
+
 	assets := k.GetAssets(ctx)
 	for _, v := range assets {
 		assetCode := v.AssetCode
 		assetName := v.AssetName
 		prices := k.GetRawPrices(ctx, assetCode, assetName)
 		var notExpiredPrices []types.CurrentPrice
-		// filter out expired prices
+		// filter out expired prices, and those of oracles no longer allowed to post the price of the asset
 		for _, v := range prices {
+			oracle, found := assignedOracles[v.OracleAddress]
+			if !found || !oracle.CanPostPrice(assetName) {
+				continue
+			}
 			if v.Expiry.GTE(sdk.NewInt(ctx.BlockHeight())) {
 				notExpiredPrices = append(notExpiredPrices, types.CurrentPrice{
 					AssetCode: v.AssetCode,
@@ -154,15 +278,24 @@ func (k Keeper) SetCurrentPrices(ctx sdk.Context) sdk.Error {
 			}
 		}
 		l := len(notExpiredPrices)
+
+		// the quorum is a share of the oracles allowed to post the price of the asset, only their prices are counted
+		var assetOracles int64
+		for _, oracle := range oracles {
+			if oracle.CanPostPrice(assetName) {
+				assetOracles++
+			}
+		}
+		if l == 0 || sdk.NewDec(int64(l)).LT(quorum.MulInt64(assetOracles)) {
+			if k.setCurrentPriceStale(ctx, assetCode, assetName) {
+				tags = tags.AppendTag(TagStalePrice, assetName+":"+assetCode)
+			}
+			continue
+		}
+
 		var medianPrice sdk.Int
 		var expiry sdk.Int
-		// TODO make threshold for acceptance (ie. require 51% of oracles to have posted valid prices
-		if l == 0 {
-			// Error if there are no valid prices in the raw pricefeed
-			// return ErrNoValidPrice(k.codespace)
-			medianPrice = sdk.NewInt(0)
-			expiry = sdk.NewInt(0)
-		} else if l == 1 {
+		if l == 1 {
 			// Return immediately if there's only one price
 			medianPrice = notExpiredPrices[0].Price
 			expiry = notExpiredPrices[0].Expiry
@@ -190,18 +323,31 @@ func (k Keeper) SetCurrentPrices(ctx sdk.Context) sdk.Error {
 			}
 		}
 
-		store := ctx.KVStore(k.priceStoreKey)
-		currentPrice := types.CurrentPrice{
+		k.setCurrentPrice(ctx, types.CurrentPrice{
 			AssetCode: assetCode,
+			AssetName: assetName,
 			Price:     medianPrice,
 			Expiry:    expiry,
-		}
-		store.Set(
-			[]byte(CurrentPricePrefix+k.combineAssetInfo(assetCode, assetName)), k.cdc.MustMarshalBinaryBare(currentPrice),
-		)
+		})
 	}
 
-	return nil
+	return tags, nil
+}
+
+// setCurrentPriceStale flags the last price of an asset as stale, an asset without a price keeps reporting zero.
+// It returns whether the price turned stale, rather than being stale already.
+func (k Keeper) setCurrentPriceStale(ctx sdk.Context, assetCode string, assetName string) bool {
+	store := ctx.KVStore(k.priceStoreKey)
+	if !store.Has(getCurrentPriceKey(newAssetKey(assetName, assetCode))) {
+		return false
+	}
+	price := k.GetCurrentPrice(ctx, assetCode, assetName)
+	if price.Stale {
+		return false
+	}
+	price.Stale = true
+	k.setCurrentPrice(ctx, price)
+	return true
 }
 
 // GetPendingPriceAssets returns the list of all those assets which prices are still pending
@@ -246,6 +392,16 @@ func (k Keeper) GetAsset(ctx sdk.Context, assetCode string, assetName string) (A
 
 }
 
+// hasAssetName returns whether any asset in the pricefeed system has the given name
+func (k Keeper) hasAssetName(ctx sdk.Context, assetName string) bool {
+	for _, asset := range k.GetAssets(ctx) {
+		if asset.AssetName == assetName {
+			return true
+		}
+	}
+	return false
+}
+
 // GetOracle returns the oracle address as a string if it is in the pricefeed store
 func (k Keeper) GetOracle(ctx sdk.Context, oracle string) (Oracle, bool) {
 	oracles := k.GetOracles(ctx)
@@ -266,13 +422,7 @@ func (k Keeper) GetOracle(ctx sdk.Context, oracle string) (Oracle, bool) {
 func (k Keeper) AskForPrice(ctx sdk.Context, assetCode string, assetName string) {
 
 	// recover the existing prices, if any
-	store := ctx.KVStore(k.pricesRequestsStoreKey)
-	existing := store.Get([]byte(RequiredPricesPrefix))
-
-	var requiredPrices []PendingPriceAsset
-	if existing != nil {
-		k.cdc.MustUnmarshalBinaryBare(existing, &requiredPrices)
-	}
+	requiredPrices := k.GetPendingPriceAssets(ctx)
 
 	// update the required prices
 	requiredPrices = append(requiredPrices, PendingPriceAsset{AssetName: assetName, AssetCode: assetCode})
@@ -280,16 +430,23 @@ func (k Keeper) AskForPrice(ctx sdk.Context, assetCode string, assetName string)
 	// TODO: this should probably take into consideration the fact that the price may be have been asked before.
 	// In this case it should be better to save the block height at which it has been retrieved the last time, and later
 	// decide whenever it is better to require it again or not
-	store.Set([]byte(RequiredPricesPrefix), k.cdc.MustMarshalBinaryBare(requiredPrices))
+	k.setPendingPriceAssets(ctx, requiredPrices)
+}
+
+func (k Keeper) setPendingPriceAssets(ctx sdk.Context, pendingPriceAssets []PendingPriceAsset) {
+	store := ctx.KVStore(k.priceStoreKey)
+	store.Set([]byte(RequiredPricesPrefix), k.cdc.MustMarshalBinaryBare(pendingPriceAssets))
 }
 
 // GetCurrentPrice fetches the current median price of all oracles for a specific asset
 func (k Keeper) GetCurrentPrice(ctx sdk.Context, assetCode string, assetName string) types.CurrentPrice {
 
 	store := ctx.KVStore(k.priceStoreKey)
-	var storedPriceKey string
-
-	bz := store.Get([]byte(storedPriceKey))
+	bz := store.Get(getCurrentPriceKey(newAssetKey(assetName, assetCode)))
+	if bz == nil {
+		// no price is known yet
+		return types.CurrentPrice{AssetName: assetName, AssetCode: assetCode, Price: sdk.ZeroInt(), Expiry: sdk.ZeroInt()}
+	}
 
 	var price types.CurrentPrice
 	k.cdc.MustUnmarshalBinaryBare(bz, &price)
@@ -300,12 +457,58 @@ func (k Keeper) GetCurrentPrice(ctx sdk.Context, assetCode string, assetName str
 // GetRawPrices fetches the set of all prices posted by oracles for an asset
 func (k Keeper) GetRawPrices(ctx sdk.Context, assetCode string, assetName string) []types.PostedPrice {
 	store := ctx.KVStore(k.priceStoreKey)
-	bz := store.Get([]byte(RawPriceFeedPrefix + assetCode))
+	bz := store.Get(getRawPricesKey(newAssetKey(assetName, assetCode)))
 	var prices []types.PostedPrice
 	k.cdc.MustUnmarshalBinaryBare(bz, &prices)
 	return prices
 }
 
+// getAllRawPrices returns the prices posted by oracles for every asset
+func (k Keeper) getAllRawPrices(ctx sdk.Context) []types.PostedPrice {
+	store := ctx.KVStore(k.priceStoreKey)
+	iter := sdk.KVStorePrefixIterator(store, []byte(RawPriceFeedPrefix))
+	defer iter.Close()
+
+	var allPrices []types.PostedPrice
+	for ; iter.Valid(); iter.Next() {
+		var prices []types.PostedPrice
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), &prices)
+		allPrices = append(allPrices, prices...)
+	}
+	return allPrices
+}
+
+// setRawPrices overwrites the prices posted by oracles for an asset
+func (k Keeper) setRawPrices(ctx sdk.Context, assetCode string, assetName string, prices []types.PostedPrice) {
+	store := ctx.KVStore(k.priceStoreKey)
+	if len(prices) == 0 {
+		// empty lists can't be stored
+		store.Delete(getRawPricesKey(newAssetKey(assetName, assetCode)))
+		return
+	}
+	store.Set(getRawPricesKey(newAssetKey(assetName, assetCode)), k.cdc.MustMarshalBinaryBare(prices))
+}
+
+// getAllCurrentPrices returns the current price of every asset that has one
+func (k Keeper) getAllCurrentPrices(ctx sdk.Context) []types.CurrentPrice {
+	store := ctx.KVStore(k.priceStoreKey)
+	iter := sdk.KVStorePrefixIterator(store, []byte(CurrentPricePrefix))
+	defer iter.Close()
+
+	var prices []types.CurrentPrice
+	for ; iter.Valid(); iter.Next() {
+		var price types.CurrentPrice
+		k.cdc.MustUnmarshalBinaryBare(iter.Value(), &price)
+		prices = append(prices, price)
+	}
+	return prices
+}
+
+func (k Keeper) setCurrentPrice(ctx sdk.Context, price types.CurrentPrice) {
+	store := ctx.KVStore(k.priceStoreKey)
+	store.Set(getCurrentPriceKey(newAssetKey(price.AssetName, price.AssetCode)), k.cdc.MustMarshalBinaryBare(price))
+}
+
 // ValidatePostPrice makes sure the person posting the price is an oracle
 func (k Keeper) ValidatePostPrice(ctx sdk.Context, msg MsgPostPrice) sdk.Error {
 
@@ -313,10 +516,108 @@ func (k Keeper) ValidatePostPrice(ctx sdk.Context, msg MsgPostPrice) sdk.Error {
 	if !assetFound {
 		return ErrInvalidAsset(k.codespace)
 	}
-	_, oracleFound := k.GetOracle(ctx, msg.From.String())
-	if !oracleFound {
+	oracle, oracleFound := k.GetOracle(ctx, msg.From.String())
+	if !oracleFound || !oracle.CanPostPrice(msg.AssetName) {
 		return ErrInvalidOracle(k.codespace)
 	}
 
 	return nil
 }
+
+// priceKeysVersionKey stores the version of the price keys used by the store, see MigratePriceKeys.
+// Since version 1 prices are stored under the keys built from an assetKey.
+var priceKeysVersionKey = []byte(StoreKey + ":priceKeysVersion")
+
+const priceKeysVersion byte = 1
+
+// getLegacyPriceKey returns the "<prefix><code>++<name>" key prices were stored under before version 1
+func getLegacyPriceKey(prefix string, assetCode string, assetName string) []byte {
+	return []byte(prefix + assetCode + "++" + assetName)
+}
+
+// parseLegacyPriceKey returns the asset code and name of a key prices were stored under before version 1.
+// Current keys start with the length of the asset code, which legacy keys are too short to match.
+func parseLegacyPriceKey(prefix string, key []byte) (string, string, bool) {
+	asset := key[len(prefix):]
+	if len(asset) >= 4 && uint64(binary.BigEndian.Uint32(asset)) <= uint64(len(asset)-4) {
+		return "", "", false
+	}
+	codeAndName := strings.SplitN(string(asset), "++", 2)
+	if len(codeAndName) != 2 {
+		return "", "", false
+	}
+	return codeAndName[0], codeAndName[1], true
+}
+func (k Keeper) getPriceKeysVersion(ctx sdk.Context) byte {
+	bz := ctx.KVStore(k.priceStoreKey).Get(priceKeysVersionKey)
+	if len(bz) != 1 {
+		return 0
+	}
+	return bz[0]
+}
+func (k Keeper) setPriceKeysMigrated(ctx sdk.Context) {
+	ctx.KVStore(k.priceStoreKey).Set(priceKeysVersionKey, []byte{priceKeysVersion})
+}
+
+// MigratePriceKeys moves the raw and current prices stored under the legacy "<code>++<name>" keys to the current keys.
+// It does nothing once the store is up to date, stores initialized from genesis already are.
+func (k Keeper) MigratePriceKeys(ctx sdk.Context) {
+	if k.getPriceKeysVersion(ctx) >= priceKeysVersion {
+		return
+	}
+	store := ctx.KVStore(k.priceStoreKey)
+
+	// Collect the prices first, the store can't be written while iterating.
+	// Older versions didn't always store the asset name in the prices, so the asset is taken from the key.
+	var legacyKeys [][]byte
+	var rawPrices [][]types.PostedPrice
+	iter := sdk.KVStorePrefixIterator(store, []byte(RawPriceFeedPrefix))
+	for ; iter.Valid(); iter.Next() {
+		assetCode, assetName, ok := parseLegacyPriceKey(RawPriceFeedPrefix, iter.Key())
+		if !ok {
+			continue
+		}
+		var prices []types.PostedPrice
+		if err := k.cdc.UnmarshalBinaryBare(iter.Value(), &prices); err != nil {
+			continue
+		}
+		for i := range prices {
+			prices[i].AssetCode = assetCode
+			prices[i].AssetName = assetName
+		}
+		legacyKeys = append(legacyKeys, iter.Key())
+		rawPrices = append(rawPrices, prices)
+	}
+	iter.Close()
+	for i, prices := range rawPrices {
+		store.Delete(legacyKeys[i])
+		if len(prices) > 0 {
+			k.setRawPrices(ctx, prices[0].AssetCode, prices[0].AssetName, prices)
+		}
+	}
+
+	legacyKeys = nil
+	var currentPrices []types.CurrentPrice
+	iter = sdk.KVStorePrefixIterator(store, []byte(CurrentPricePrefix))
+	for ; iter.Valid(); iter.Next() {
+		assetCode, assetName, ok := parseLegacyPriceKey(CurrentPricePrefix, iter.Key())
+		if !ok {
+			continue
+		}
+		var price types.CurrentPrice
+		if err := k.cdc.UnmarshalBinaryBare(iter.Value(), &price); err != nil {
+			continue
+		}
+		price.AssetCode = assetCode
+		price.AssetName = assetName
+		legacyKeys = append(legacyKeys, iter.Key())
+		currentPrices = append(currentPrices, price)
+	}
+	iter.Close()
+	for i, price := range currentPrices {
+		store.Delete(legacyKeys[i])
+		k.setCurrentPrice(ctx, price)
+	}
+
+	k.setPriceKeysMigrated(ctx)
+}
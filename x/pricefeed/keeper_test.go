@@ -3,6 +3,7 @@ package pricefeed
 import (
 	"testing"
 
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/stretchr/testify/require"
 	abci "github.com/tendermint/tendermint/abci/types"
@@ -19,7 +20,7 @@ func TestKeeper_SetGetAsset(t *testing.T) {
 	require.Equal(t, len(assets), 1)
 	require.Equal(t, assets[0].AssetCode, "tst")
 
-	_, found := helper.keeper.GetAsset(ctx, "tst")
+	_, found := helper.keeper.GetAsset(ctx, "tst", "")
 	require.Equal(t, found, true)
 
 	helper.keeper.AddAsset(ctx, "tst2", "2nd test asset")
@@ -28,7 +29,7 @@ func TestKeeper_SetGetAsset(t *testing.T) {
 	require.Equal(t, assets[0].AssetCode, "tst")
 	require.Equal(t, assets[1].AssetCode, "tst2")
 
-	_, found = helper.keeper.GetAsset(ctx, "nan")
+	_, found = helper.keeper.GetAsset(ctx, "nan", "")
 	require.Equal(t, found, false)
 }
 
@@ -41,33 +42,36 @@ func TestKeeper_GetSetPrice(t *testing.T) {
 	helper.keeper.AddAsset(ctx, "tst", "test asset")
 	// Set price by oracle 1
 	_, err := helper.keeper.SetPrice(
-		ctx, helper.addrs[0], "tst",
-		sdk.MustNewDecFromStr("0.33"),
+		ctx, helper.addrs[0], "tst", "",
+		sdk.NewInt(330),
 		sdk.NewInt(10))
 	require.NoError(t, err)
 	// Get raw prices
-	rawPrices := helper.keeper.GetRawPrices(ctx, "tst")
+	rawPrices := helper.keeper.GetRawPrices(ctx, "tst", "")
 	require.Equal(t, len(rawPrices), 1)
-	require.Equal(t, rawPrices[0].Price.Equal(sdk.MustNewDecFromStr("0.33")), true)
+	require.Equal(t, rawPrices[0].Price.Equal(sdk.NewInt(330)), true)
 	// Set price by oracle 2
 	_, err = helper.keeper.SetPrice(
-		ctx, helper.addrs[1], "tst",
-		sdk.MustNewDecFromStr("0.35"),
+		ctx, helper.addrs[1], "tst", "",
+		sdk.NewInt(350),
 		sdk.NewInt(10))
 	require.NoError(t, err)
 
-	rawPrices = helper.keeper.GetRawPrices(ctx, "tst")
+	rawPrices = helper.keeper.GetRawPrices(ctx, "tst", "")
 	require.Equal(t, len(rawPrices), 2)
-	require.Equal(t, rawPrices[1].Price.Equal(sdk.MustNewDecFromStr("0.35")), true)
+	require.Equal(t, rawPrices[1].Price.Equal(sdk.NewInt(350)), true)
 
 	// Update Price by Oracle 1
 	_, err = helper.keeper.SetPrice(
-		ctx, helper.addrs[0], "tst",
-		sdk.MustNewDecFromStr("0.37"),
+		ctx, helper.addrs[0], "tst", "",
+		sdk.NewInt(370),
 		sdk.NewInt(10))
 	require.NoError(t, err)
-	rawPrices = helper.keeper.GetRawPrices(ctx, "tst")
-	require.Equal(t, rawPrices[0].Price.Equal(sdk.MustNewDecFromStr("0.37")), true)
+	rawPrices = helper.keeper.GetRawPrices(ctx, "tst", "")
+	require.Equal(t, rawPrices[0].Price.Equal(sdk.NewInt(370)), true)
+
+	// the CDPs using the asset are updated every time
+	require.Equal(t, []assetKey{{"", "tst"}, {"", "tst"}, {"", "tst"}}, helper.cdpKeeper.modified)
 }
 
 // TestKeeper_GetSetCurrentPrice Test Setting the median price of an Asset
@@ -78,33 +82,236 @@ func TestKeeper_GetSetCurrentPrice(t *testing.T) {
 	ctx := helper.mApp.BaseApp.NewContext(false, abci.Header{})
 	// Odd number of oracles
 	helper.keeper.AddAsset(ctx, "tst", "test asset")
+	for _, addr := range helper.addrs {
+		helper.keeper.AddOracle(ctx, addr.String(), nil)
+	}
 	helper.keeper.SetPrice(
-		ctx, helper.addrs[0], "tst",
-		sdk.MustNewDecFromStr("0.33"),
+		ctx, helper.addrs[0], "tst", "",
+		sdk.NewInt(330),
 		sdk.NewInt(10))
 	helper.keeper.SetPrice(
-		ctx, helper.addrs[1], "tst",
-		sdk.MustNewDecFromStr("0.35"),
+		ctx, helper.addrs[1], "tst", "",
+		sdk.NewInt(350),
 		sdk.NewInt(10))
 	helper.keeper.SetPrice(
-		ctx, helper.addrs[2], "tst",
-		sdk.MustNewDecFromStr("0.34"),
+		ctx, helper.addrs[2], "tst", "",
+		sdk.NewInt(340),
 		sdk.NewInt(10))
 	// Set current price
-	err := helper.keeper.SetCurrentPrices(ctx)
+	_, err := helper.keeper.SetCurrentPrices(ctx)
 	require.NoError(t, err)
 	// Get Current price
-	price := helper.keeper.GetCurrentPrice(ctx, "tst")
-	require.Equal(t, price.Price.Equal(sdk.MustNewDecFromStr("0.34")), true)
+	price := helper.keeper.GetCurrentPrice(ctx, "tst", "")
+	require.Equal(t, price.Price.Equal(sdk.NewInt(340)), true)
 
 	// Even number of oracles
 	helper.keeper.SetPrice(
-		ctx, helper.addrs[3], "tst",
-		sdk.MustNewDecFromStr("0.36"),
+		ctx, helper.addrs[3], "tst", "",
+		sdk.NewInt(360),
 		sdk.NewInt(10))
-	err = helper.keeper.SetCurrentPrices(ctx)
+	_, err = helper.keeper.SetCurrentPrices(ctx)
+	require.NoError(t, err)
+	price = helper.keeper.GetCurrentPrice(ctx, "tst", "")
+	require.Equal(t, price.Price.Equal(sdk.NewInt(345)), true)
+
+}
+
+// TestKeeper_SetCurrentPrices_Quorum tests the current price is only updated when enough oracles posted a price
+func TestKeeper_SetCurrentPrices_Quorum(t *testing.T) {
+	helper := getMockApp(t, 4, GenesisState{}, nil)
+	var oracles []Oracle
+	for _, addr := range helper.addrs {
+		oracles = append(oracles, Oracle{OracleAddress: addr.String(), AssetNames: []string{"btc"}})
+	}
+	genesis := GenesisState{
+		Params:  DefaultParams(),
+		Assets:  []Asset{{Type: "ft", AssetName: "btc", Description: "a description"}},
+		Oracles: oracles,
+	}
+	header := abci.Header{Height: helper.mApp.LastBlockHeight() + 1}
+	helper.mApp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := helper.mApp.BaseApp.NewContext(false, header).WithBlockHeight(10)
+	InitGenesis(ctx, helper.keeper, genesis)
+	postPrice := func(oracle int, price int64, expiry int64) {
+		_, err := helper.keeper.SetPrice(ctx, helper.addrs[oracle], "", "btc", sdk.NewInt(price), sdk.NewInt(expiry))
+		require.NoError(t, err)
+	}
+	staleTags := sdk.NewTags(TagStalePrice, "btc:")
+
+	// Below quorum, without a last price: the price stays zero
+	postPrice(0, 300, 20)
+	postPrice(1, 310, 20)
+	tags, err := helper.keeper.SetCurrentPrices(ctx)
+	require.NoError(t, err)
+	require.Empty(t, tags)
+	price := helper.keeper.GetCurrentPrice(ctx, "", "btc")
+	require.True(t, price.Price.IsZero())
+	require.False(t, price.Stale)
+
+	// Odd number of oracles, 3 of 4 reach the 51% quorum
+	postPrice(2, 320, 30)
+	tags, err = helper.keeper.SetCurrentPrices(ctx)
+	require.NoError(t, err)
+	require.Empty(t, tags)
+	require.Equal(t, types.CurrentPrice{AssetName: "btc", Price: sdk.NewInt(310), Expiry: sdk.NewInt(20)},
+		helper.keeper.GetCurrentPrice(ctx, "", "btc"))
+
+	// Even number of oracles
+	postPrice(3, 330, 30)
+	tags, err = helper.keeper.SetCurrentPrices(ctx)
+	require.NoError(t, err)
+	require.Empty(t, tags)
+	require.Equal(t, types.CurrentPrice{AssetName: "btc", Price: sdk.NewInt(315), Expiry: sdk.NewInt(25)},
+		helper.keeper.GetCurrentPrice(ctx, "", "btc"))
+
+	// Below quorum once two prices expire: the last price is kept, flagged stale
+	ctx = ctx.WithBlockHeight(21)
+	tags, err = helper.keeper.SetCurrentPrices(ctx)
+	require.NoError(t, err)
+	require.Equal(t, staleTags, tags)
+	require.Equal(t, types.CurrentPrice{AssetName: "btc", Price: sdk.NewInt(315), Expiry: sdk.NewInt(25), Stale: true},
+		helper.keeper.GetCurrentPrice(ctx, "", "btc"))
+
+	// The tag is only returned when the price turns stale
+	tags, err = helper.keeper.SetCurrentPrices(ctx)
+	require.NoError(t, err)
+	require.Empty(t, tags)
+	require.True(t, helper.keeper.GetCurrentPrice(ctx, "", "btc").Stale)
+
+	// Lowering the quorum lets the remaining prices through
+	helper.keeper.setParams(ctx, PricefeedModuleParams{OracleQuorum: sdk.MustNewDecFromStr("0.5")})
+	tags, err = helper.keeper.SetCurrentPrices(ctx)
+	require.NoError(t, err)
+	require.Empty(t, tags)
+	require.Equal(t, types.CurrentPrice{AssetName: "btc", Price: sdk.NewInt(325), Expiry: sdk.NewInt(30)},
+		helper.keeper.GetCurrentPrice(ctx, "", "btc"))
+}
+
+// TestKeeper_SetCurrentPrices_UnassignedOracles tests the prices left by unassigned oracles don't count towards the quorum
+func TestKeeper_SetCurrentPrices_UnassignedOracles(t *testing.T) {
+	helper := getMockApp(t, 4, GenesisState{}, nil)
+	genesis := GenesisState{
+		Params: DefaultParams(),
+		Assets: []Asset{{Type: "ft", AssetName: "btc", Description: "a description"}},
+		Oracles: []Oracle{
+			{OracleAddress: helper.addrs[0].String(), AssetNames: []string{"btc"}},
+			{OracleAddress: helper.addrs[1].String(), AssetNames: []string{"btc"}},
+		},
+	}
+	header := abci.Header{Height: helper.mApp.LastBlockHeight() + 1}
+	helper.mApp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := helper.mApp.BaseApp.NewContext(false, header).WithBlockHeight(10)
+	InitGenesis(ctx, helper.keeper, genesis)
+
+	// One assigned oracle posts a price, next to the prices of two oracles no longer assigned to the asset
+	_, err := helper.keeper.SetPrice(ctx, helper.addrs[0], "", "btc", sdk.NewInt(300), sdk.NewInt(20))
 	require.NoError(t, err)
-	price = helper.keeper.GetCurrentPrice(ctx, "tst")
-	require.Equal(t, price.Price.Equal(sdk.MustNewDecFromStr("0.345")), true)
+	prices := helper.keeper.GetRawPrices(ctx, "", "btc")
+	for _, addr := range helper.addrs[2:] {
+		prices = append(prices, types.PostedPrice{AssetName: "btc", OracleAddress: addr.String(), Price: sdk.NewInt(900), Expiry: sdk.NewInt(20)})
+	}
+	helper.keeper.setRawPrices(ctx, "", "btc", prices)
+
+	// Check the three prices don't meet the quorum, as only one of the two assigned oracles posted
+	_, err = helper.keeper.SetCurrentPrices(ctx)
+	require.NoError(t, err)
+	require.True(t, helper.keeper.GetCurrentPrice(ctx, "", "btc").Price.IsZero())
+
+	// Check the median is only of the assigned oracles once they reach the quorum
+	_, err = helper.keeper.SetPrice(ctx, helper.addrs[1], "", "btc", sdk.NewInt(310), sdk.NewInt(20))
+	require.NoError(t, err)
+	_, err = helper.keeper.SetCurrentPrices(ctx)
+	require.NoError(t, err)
+	require.Equal(t, types.CurrentPrice{AssetName: "btc", Price: sdk.NewInt(305), Expiry: sdk.NewInt(20)},
+		helper.keeper.GetCurrentPrice(ctx, "", "btc"))
+}
+
+// TestKeeper_GetCurrentPrice_Assets tests the prices of assets sharing a name or a code are kept apart
+func TestKeeper_GetCurrentPrice_Assets(t *testing.T) {
+	genesis := GenesisState{Assets: []Asset{
+		{Type: "ft", AssetName: "art", Description: "a token"},
+		{Type: "nft", AssetName: "art", AssetCode: "1", Description: "a painting"},
+		{Type: "ft", AssetName: "art1", Description: "another token"},
+	}}
+	helper := getMockApp(t, 1, genesis, nil)
+	genesis.Oracles = []Oracle{{OracleAddress: helper.addrs[0].String()}}
+	header := abci.Header{Height: helper.mApp.LastBlockHeight() + 1}
+	helper.mApp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := helper.mApp.BaseApp.NewContext(false, header)
+	InitGenesis(ctx, helper.keeper, genesis)
+
+	// no price is known before one is posted
+	price := helper.keeper.GetCurrentPrice(ctx, "1", "art")
+	require.True(t, price.Price.IsZero())
+
+	_, err := helper.keeper.SetPrice(ctx, helper.addrs[0], "", "art", sdk.NewInt(10), sdk.NewInt(100))
+	require.NoError(t, err)
+	_, err = helper.keeper.SetPrice(ctx, helper.addrs[0], "1", "art", sdk.NewInt(500), sdk.NewInt(100))
+	require.NoError(t, err)
+	_, err = helper.keeper.SetPrice(ctx, helper.addrs[0], "", "art1", sdk.NewInt(20), sdk.NewInt(100))
+	require.NoError(t, err)
+	_, err = helper.keeper.SetCurrentPrices(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, sdk.NewInt(10), helper.keeper.GetCurrentPrice(ctx, "", "art").Price)
+	require.Equal(t, sdk.NewInt(500), helper.keeper.GetCurrentPrice(ctx, "1", "art").Price)
+	require.Equal(t, sdk.NewInt(20), helper.keeper.GetCurrentPrice(ctx, "", "art1").Price)
+	require.Len(t, helper.keeper.GetRawPrices(ctx, "1", "art"), 1)
+}
+
+func TestKeeper_MigratePriceKeys(t *testing.T) {
+	helper := getMockApp(t, 2, GenesisState{}, nil)
+	header := abci.Header{Height: helper.mApp.LastBlockHeight() + 1}
+	helper.mApp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := helper.mApp.BaseApp.NewContext(false, header)
+	store := ctx.KVStore(helper.keeper.priceStoreKey)
+	oracle1, oracle2 := helper.addrs[0].String(), helper.addrs[1].String()
+
+	// write prices under the legacy keys, as older versions did:
+	// an updated raw price lost its asset name and current prices never had one
+	legacyRawPrices := []types.PostedPrice{
+		{AssetName: "art", AssetCode: "1", OracleAddress: oracle1, Price: sdk.NewInt(500), Expiry: sdk.NewInt(100)},
+		{AssetCode: "1", OracleAddress: oracle2, Price: sdk.NewInt(600), Expiry: sdk.NewInt(100)},
+	}
+	legacyCurrentPrice := types.CurrentPrice{AssetCode: "1", Price: sdk.NewInt(550), Expiry: sdk.NewInt(100)}
+	legacyFTCurrentPrice := types.CurrentPrice{AssetCode: "", Price: sdk.NewInt(3), Expiry: sdk.NewInt(100)}
+	store.Set(getLegacyPriceKey(RawPriceFeedPrefix, "1", "art"), helper.keeper.cdc.MustMarshalBinaryBare(legacyRawPrices))
+	store.Set(getLegacyPriceKey(CurrentPricePrefix, "1", "art"), helper.keeper.cdc.MustMarshalBinaryBare(legacyCurrentPrice))
+	store.Set(getLegacyPriceKey(CurrentPricePrefix, "", "xrp"), helper.keeper.cdc.MustMarshalBinaryBare(legacyFTCurrentPrice))
+
+	// migrate
+	helper.keeper.MigratePriceKeys(ctx)
+
+	// check the prices moved to the new keys, with the asset taken from the legacy key
+	require.Nil(t, store.Get(getLegacyPriceKey(RawPriceFeedPrefix, "1", "art")))
+	require.Nil(t, store.Get(getLegacyPriceKey(CurrentPricePrefix, "1", "art")))
+	require.Nil(t, store.Get(getLegacyPriceKey(CurrentPricePrefix, "", "xrp")))
+	require.Equal(t, []types.PostedPrice{
+		{AssetName: "art", AssetCode: "1", OracleAddress: oracle1, Price: sdk.NewInt(500), Expiry: sdk.NewInt(100)},
+		{AssetName: "art", AssetCode: "1", OracleAddress: oracle2, Price: sdk.NewInt(600), Expiry: sdk.NewInt(100)},
+	}, helper.keeper.GetRawPrices(ctx, "1", "art"))
+	require.Equal(t, types.CurrentPrice{AssetName: "art", AssetCode: "1", Price: sdk.NewInt(550), Expiry: sdk.NewInt(100)}, helper.keeper.GetCurrentPrice(ctx, "1", "art"))
+	require.Equal(t, types.CurrentPrice{AssetName: "xrp", AssetCode: "", Price: sdk.NewInt(3), Expiry: sdk.NewInt(100)}, helper.keeper.GetCurrentPrice(ctx, "", "xrp"))
+
+	// check the migration only runs once
+	store.Set(getLegacyPriceKey(CurrentPricePrefix, "1", "art"), helper.keeper.cdc.MustMarshalBinaryBare(legacyCurrentPrice))
+	helper.keeper.MigratePriceKeys(ctx)
+	require.NotNil(t, store.Get(getLegacyPriceKey(CurrentPricePrefix, "1", "art")))
+}
+
+func TestParseLegacyPriceKey(t *testing.T) {
+	code, name, ok := parseLegacyPriceKey(RawPriceFeedPrefix, getLegacyPriceKey(RawPriceFeedPrefix, "1", "art"))
+	require.True(t, ok)
+	require.Equal(t, "1", code)
+	require.Equal(t, "art", name)
+	code, name, ok = parseLegacyPriceKey(CurrentPricePrefix, getLegacyPriceKey(CurrentPricePrefix, "", "xrp"))
+	require.True(t, ok)
+	require.Equal(t, "", code)
+	require.Equal(t, "xrp", name)
 
+	// current keys are never taken for legacy ones, even if the asset contains the separator
+	_, _, ok = parseLegacyPriceKey(RawPriceFeedPrefix, getRawPricesKey(newAssetKey("a++b", "")))
+	require.False(t, ok)
+	_, _, ok = parseLegacyPriceKey(CurrentPricePrefix, getCurrentPriceKey(newAssetKey("art", "1++2")))
+	require.False(t, ok)
 }
@@ -1,7 +1,38 @@
 package pricefeed
 
+import (
+	"bytes"
+	"encoding/binary"
+)
+
 // constants for Fungible and Non fungible token
 const (
 	_FT  = "FT"
 	_NFT = "NFT"
 )
+
+// assetKey identifies an asset in the store by its name and its code, which is the id of a non fungible token
+// and is empty for fungible ones. Every price of an asset is stored under a key built from it.
+type assetKey struct {
+	AssetName string
+	AssetCode string
+}
+
+func newAssetKey(assetName string, assetCode string) assetKey {
+	return assetKey{AssetName: assetName, AssetCode: assetCode}
+}
+
+// Bytes encodes the asset, the code is length prefixed so that two assets never share the same encoding.
+// Fungible tokens come first in the store, as their code is empty.
+func (ak assetKey) Bytes() []byte {
+	codeLength := make([]byte, 4)
+	binary.BigEndian.PutUint32(codeLength, uint32(len(ak.AssetCode)))
+	return bytes.Join([][]byte{codeLength, []byte(ak.AssetCode), []byte(ak.AssetName)}, nil)
+}
+
+func getRawPricesKey(asset assetKey) []byte {
+	return bytes.Join([][]byte{[]byte(RawPriceFeedPrefix), asset.Bytes()}, nil)
+}
+func getCurrentPriceKey(asset assetKey) []byte {
+	return bytes.Join([][]byte{[]byte(CurrentPricePrefix), asset.Bytes()}, nil)
+}
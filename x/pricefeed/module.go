@@ -101,12 +101,13 @@ func (am AppModule) ExportGenesis(ctx sdk.Context) json.RawMessage {
 }
 
 // BeginBlock module begin-block
-func (AppModule) BeginBlock(_ sdk.Context, _ abci.RequestBeginBlock) sdk.Tags {
+func (am AppModule) BeginBlock(ctx sdk.Context, _ abci.RequestBeginBlock) sdk.Tags {
+	am.keeper.MigratePriceKeys(ctx)
 	return sdk.EmptyTags()
 }
 
 // EndBlock module end-block
 func (am AppModule) EndBlock(ctx sdk.Context, _ abci.RequestEndBlock) ([]abci.ValidatorUpdate, sdk.Tags) {
-	EndBlocker(ctx, am.keeper)
-	return []abci.ValidatorUpdate{}, sdk.Tags{}
+	tags := EndBlocker(ctx, am.keeper)
+	return []abci.ValidatorUpdate{}, tags
 }
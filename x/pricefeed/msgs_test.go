@@ -11,21 +11,21 @@ func TestMsgPlaceBid_ValidateBasic(t *testing.T) {
 	// oracles := []Oracle{Oracle{
 	// 	OracleAddress: addr.String(),
 	// }}
-	price, _ := sdk.NewDecFromStr("0.3005")
+	price := sdk.NewInt(3005)
 	expiry, _ := sdk.NewIntFromString("10")
 	negativeExpiry, _ := sdk.NewIntFromString("-3")
-	negativePrice, _ := sdk.NewDecFromStr("-3.05")
+	negativePrice := sdk.NewInt(-305)
 
 	tests := []struct {
 		name       string
 		msg        MsgPostPrice
 		expectPass bool
 	}{
-		{"normal", MsgPostPrice{addr, "xrp", price, expiry}, true},
-		{"emptyAddr", MsgPostPrice{sdk.AccAddress{}, "xrp", price, expiry}, false},
-		{"emptyAsset", MsgPostPrice{addr, "", price, expiry}, false},
-		{"negativePrice", MsgPostPrice{addr, "xrp", negativePrice, expiry}, false},
-		{"negativeExpiry", MsgPostPrice{addr, "xrp", price, negativeExpiry}, false},
+		{"normal", MsgPostPrice{addr, "xrp", "1", price, expiry}, true},
+		{"emptyAddr", MsgPostPrice{sdk.AccAddress{}, "xrp", "1", price, expiry}, false},
+		{"emptyAsset", MsgPostPrice{addr, "xrp", "", price, expiry}, false},
+		{"negativePrice", MsgPostPrice{addr, "xrp", "1", negativePrice, expiry}, false},
+		{"negativeExpiry", MsgPostPrice{addr, "xrp", "1", price, negativeExpiry}, false},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
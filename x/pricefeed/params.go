@@ -1,23 +1,59 @@
 package pricefeed
 
-import "github.com/cosmos/cosmos-sdk/x/params"
+import (
+	"fmt"
+	"strings"
 
-// ParamStoreKeyOracleList key to get the list of oracle
-var ParamStoreKeyOracleList = []byte("oraclelist")
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/params"
+)
 
-// ParamKeyTable keytable
+// The oracles are not a param, they are managed through the AddOracleProposal and RemoveOracleProposal gov proposals.
+
+// The params are stored all together under one key, as in the cdp module
+var moduleParamsKey = []byte("PricefeedModuleParams")
+
+// ParamKeyTable returns the key table of the pricefeed params
 func ParamKeyTable() params.KeyTable {
 	return params.NewKeyTable(
-		ParamStoreKeyOracleList, []string{},
+		moduleParamsKey, PricefeedModuleParams{},
 	)
 }
 
+// PricefeedModuleParams params for the pricefeed module
+type PricefeedModuleParams struct {
+	// OracleQuorum is the share of the oracles assigned to an asset that must have posted a valid price
+	// for the current price of the asset to be updated
+	OracleQuorum sdk.Dec `json:"oracle_quorum"`
+}
+
+// DefaultParams requires more than half of the oracles of an asset to agree on its price
+func DefaultParams() PricefeedModuleParams {
+	return PricefeedModuleParams{
+		OracleQuorum: sdk.MustNewDecFromStr("0.51"),
+	}
+}
+
+// Validate checks the quorum is a share between 0 and 1
+func (p PricefeedModuleParams) Validate() error {
+	if p.OracleQuorum.IsNil() || p.OracleQuorum.IsNegative() || p.OracleQuorum.GT(sdk.OneDec()) {
+		return fmt.Errorf("oracle quorum must be between 0 and 1, is %s", p.OracleQuorum)
+	}
+	return nil
+}
+
+// implement fmt.Stringer
+func (p PricefeedModuleParams) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`Pricefeed Params:
+  Oracle Quorum: %s`, p.OracleQuorum))
+}
+
 /*
 Keys:								Values:
 pricefeed						N/A (top level prefix)
 pricefeed:raw:x 		[]PostedPrice{AssetCode: string, OracleAddress: string, Price: sdk.Dec, Expiry: sdk.Int}
 pricefeed:current:x CurrentPrice{AssetCode: string, Price: sdk.Dec, Expiry: sdk.Int}
-pricefeed:oracles:x []Oracle{OracleAddress: string}
+pricefeed:oracles:x []Oracle{OracleAddress: string, AssetNames: []string}
 pricefeed:assets 		[]Asset{AssetCode:string, Description: string}
 
 To update the price for a particular oracle after they have made a MsgPostPrice transaction:
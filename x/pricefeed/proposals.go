@@ -0,0 +1,156 @@
+package pricefeed
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/gov"
+)
+
+const (
+	// ProposalTypeAddOracle type of AddOracleProposal
+	ProposalTypeAddOracle = "AddOracle"
+	// ProposalTypeRemoveOracle type of RemoveOracleProposal
+	ProposalTypeRemoveOracle = "RemoveOracle"
+)
+
+var _ gov.Content = AddOracleProposal{}
+var _ gov.Content = RemoveOracleProposal{}
+
+func init() {
+	gov.RegisterProposalType(ProposalTypeAddOracle)
+	gov.RegisterProposalTypeCodec(AddOracleProposal{}, "pricefeed/AddOracleProposal")
+	gov.RegisterProposalType(ProposalTypeRemoveOracle)
+	gov.RegisterProposalTypeCodec(RemoveOracleProposal{}, "pricefeed/RemoveOracleProposal")
+}
+
+// AddOracleProposal allows an address to post prices, for the given assets only or for any asset if none is given.
+// Proposing an oracle that already exists assigns it the given assets as well.
+type AddOracleProposal struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Oracle      sdk.AccAddress `json:"oracle"`
+	AssetNames  []string       `json:"asset_names"`
+}
+
+// NewAddOracleProposal creates a new add oracle proposal
+func NewAddOracleProposal(title, description string, oracle sdk.AccAddress, assetNames []string) AddOracleProposal {
+	return AddOracleProposal{title, description, oracle, assetNames}
+}
+
+// GetTitle returns the title of the proposal
+func (p AddOracleProposal) GetTitle() string { return p.Title }
+
+// GetDescription returns the description of the proposal
+func (p AddOracleProposal) GetDescription() string { return p.Description }
+
+// ProposalRoute returns the routing key of the proposal
+func (p AddOracleProposal) ProposalRoute() string { return RouterKey }
+
+// ProposalType returns the type of the proposal
+func (p AddOracleProposal) ProposalType() string { return ProposalTypeAddOracle }
+
+// ValidateBasic runs basic stateless validity checks
+func (p AddOracleProposal) ValidateBasic() sdk.Error {
+	err := gov.ValidateAbstract(DefaultCodespace, p)
+	if err != nil {
+		return err
+	}
+	return validateOracleAssignment(p.Oracle, p.AssetNames)
+}
+
+// implement fmt.Stringer
+func (p AddOracleProposal) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`Add Oracle Proposal:
+  Title:       %s
+  Description: %s
+  Oracle:      %s
+  Assets:      %s`, p.Title, p.Description, p.Oracle, strings.Join(p.AssetNames, ", ")))
+}
+
+// RemoveOracleProposal unassigns an oracle from the given assets, or removes it altogether if none is given
+type RemoveOracleProposal struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Oracle      sdk.AccAddress `json:"oracle"`
+	AssetNames  []string       `json:"asset_names"`
+}
+
+// NewRemoveOracleProposal creates a new remove oracle proposal
+func NewRemoveOracleProposal(title, description string, oracle sdk.AccAddress, assetNames []string) RemoveOracleProposal {
+	return RemoveOracleProposal{title, description, oracle, assetNames}
+}
+
+// GetTitle returns the title of the proposal
+func (p RemoveOracleProposal) GetTitle() string { return p.Title }
+
+// GetDescription returns the description of the proposal
+func (p RemoveOracleProposal) GetDescription() string { return p.Description }
+
+// ProposalRoute returns the routing key of the proposal
+func (p RemoveOracleProposal) ProposalRoute() string { return RouterKey }
+
+// ProposalType returns the type of the proposal
+func (p RemoveOracleProposal) ProposalType() string { return ProposalTypeRemoveOracle }
+
+// ValidateBasic runs basic stateless validity checks
+func (p RemoveOracleProposal) ValidateBasic() sdk.Error {
+	err := gov.ValidateAbstract(DefaultCodespace, p)
+	if err != nil {
+		return err
+	}
+	return validateOracleAssignment(p.Oracle, p.AssetNames)
+}
+
+// implement fmt.Stringer
+func (p RemoveOracleProposal) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`Remove Oracle Proposal:
+  Title:       %s
+  Description: %s
+  Oracle:      %s
+  Assets:      %s`, p.Title, p.Description, p.Oracle, strings.Join(p.AssetNames, ", ")))
+}
+
+func validateOracleAssignment(oracle sdk.AccAddress, assetNames []string) sdk.Error {
+	if oracle.Empty() {
+		return sdk.ErrInvalidAddress("invalid (empty) oracle address")
+	}
+	seen := make(map[string]bool)
+	for _, name := range assetNames {
+		if len(name) == 0 {
+			return sdk.ErrInternal("invalid (empty) asset name")
+		}
+		if seen[name] {
+			return sdk.ErrInternal(fmt.Sprintf("asset %s is repeated", name))
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// NewOracleProposalHandler handles the pricefeed governance proposals
+func NewOracleProposalHandler(k Keeper) gov.Handler {
+	return func(ctx sdk.Context, content gov.Content) sdk.Error {
+		switch c := content.(type) {
+		case AddOracleProposal:
+			return handleAddOracleProposal(ctx, k, c)
+		case RemoveOracleProposal:
+			return k.RemoveOracle(ctx, c.Oracle.String(), c.AssetNames)
+		default:
+			errMsg := fmt.Sprintf("unrecognized pricefeed proposal content type: %T", c)
+			return sdk.ErrUnknownRequest(errMsg)
+		}
+	}
+}
+
+func handleAddOracleProposal(ctx sdk.Context, k Keeper, p AddOracleProposal) sdk.Error {
+	// oracles can only be assigned to assets the pricefeed knows of
+	for _, name := range p.AssetNames {
+		if !k.hasAssetName(ctx, name) {
+			return ErrInvalidAsset(k.codespace)
+		}
+	}
+	k.AddOracle(ctx, p.Oracle.String(), p.AssetNames)
+	return nil
+}
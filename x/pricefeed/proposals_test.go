@@ -0,0 +1,94 @@
+package pricefeed
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+func TestOracleProposals_ValidateBasic(t *testing.T) {
+	addr := sdk.AccAddress([]byte("someName"))
+
+	tests := []struct {
+		name       string
+		proposal   AddOracleProposal
+		expectPass bool
+	}{
+		{"anyAsset", NewAddOracleProposal("title", "description", addr, nil), true},
+		{"someAssets", NewAddOracleProposal("title", "description", addr, []string{"btc", "art"}), true},
+		{"emptyTitle", NewAddOracleProposal("", "description", addr, nil), false},
+		{"emptyOracle", NewAddOracleProposal("title", "description", sdk.AccAddress{}, nil), false},
+		{"emptyAsset", NewAddOracleProposal("title", "description", addr, []string{""}), false},
+		{"repeatedAsset", NewAddOracleProposal("title", "description", addr, []string{"btc", "btc"}), false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			remove := NewRemoveOracleProposal(tc.proposal.Title, tc.proposal.Description, tc.proposal.Oracle, tc.proposal.AssetNames)
+			if tc.expectPass {
+				require.Nil(t, tc.proposal.ValidateBasic())
+				require.Nil(t, remove.ValidateBasic())
+			} else {
+				require.NotNil(t, tc.proposal.ValidateBasic())
+				require.NotNil(t, remove.ValidateBasic())
+			}
+		})
+	}
+}
+
+func TestOracleProposalHandler(t *testing.T) {
+	genesis := GenesisState{Assets: []Asset{
+		{Type: "ft", AssetName: "btc", Description: "a description"},
+		{Type: "nft", AssetName: "art", AssetCode: "1", Description: "a painting"},
+	}}
+	helper := getMockApp(t, 2, genesis, nil)
+	header := abci.Header{Height: helper.mApp.LastBlockHeight() + 1}
+	helper.mApp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	ctx := helper.mApp.BaseApp.NewContext(false, header)
+	InitGenesis(ctx, helper.keeper, genesis)
+	handler := NewOracleProposalHandler(helper.keeper)
+	reporter, appraiser := helper.addrs[0], helper.addrs[1]
+	postPrice := func(oracle sdk.AccAddress, assetName string, assetCode string) sdk.Error {
+		return helper.keeper.ValidatePostPrice(ctx, MsgPostPrice{oracle, assetName, assetCode, sdk.NewInt(10), sdk.NewInt(100)})
+	}
+
+	// oracles can't be assigned to unknown assets
+	require.NotNil(t, handler(ctx, NewAddOracleProposal("title", "description", reporter, []string{"eth"})))
+	require.Empty(t, helper.keeper.GetOracles(ctx))
+
+	// add an oracle for the fungible token and one for the paintings
+	require.Nil(t, handler(ctx, NewAddOracleProposal("title", "description", reporter, []string{"btc"})))
+	require.Nil(t, handler(ctx, NewAddOracleProposal("title", "description", appraiser, []string{"art"})))
+	require.Nil(t, postPrice(reporter, "btc", ""))
+	require.NotNil(t, postPrice(reporter, "art", "1"))
+	require.Nil(t, postPrice(appraiser, "art", "1"))
+	require.NotNil(t, postPrice(appraiser, "btc", ""))
+
+	// assign the appraiser to the fungible token as well
+	require.Nil(t, handler(ctx, NewAddOracleProposal("title", "description", appraiser, []string{"btc"})))
+	require.Equal(t, []Oracle{
+		{OracleAddress: reporter.String(), AssetNames: []string{"btc"}},
+		{OracleAddress: appraiser.String(), AssetNames: []string{"art", "btc"}},
+	}, helper.keeper.GetOracles(ctx))
+	require.Nil(t, postPrice(appraiser, "btc", ""))
+
+	// unassign it again
+	require.NotNil(t, handler(ctx, NewRemoveOracleProposal("title", "description", reporter, []string{"art"})))
+	require.Nil(t, handler(ctx, NewRemoveOracleProposal("title", "description", appraiser, []string{"btc"})))
+	require.NotNil(t, postPrice(appraiser, "btc", ""))
+	require.Nil(t, postPrice(appraiser, "art", "1"))
+
+	// an oracle left without assets is removed
+	require.Nil(t, handler(ctx, NewRemoveOracleProposal("title", "description", reporter, []string{"btc"})))
+	_, found := helper.keeper.GetOracle(ctx, reporter.String())
+	require.False(t, found)
+	require.NotNil(t, handler(ctx, NewRemoveOracleProposal("title", "description", reporter, nil)))
+
+	// an oracle assigned to any asset can only be removed altogether
+	require.Nil(t, handler(ctx, NewAddOracleProposal("title", "description", appraiser, nil)))
+	require.Nil(t, postPrice(appraiser, "btc", ""))
+	require.NotNil(t, handler(ctx, NewRemoveOracleProposal("title", "description", appraiser, []string{"art"})))
+	require.Nil(t, handler(ctx, NewRemoveOracleProposal("title", "description", appraiser, nil)))
+	require.Empty(t, helper.keeper.GetOracles(ctx))
+}
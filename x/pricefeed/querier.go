@@ -24,6 +24,9 @@ const (
 
 	// QueryPendingPrices command for pending prices
 	QueryPendingPrices = "pending-prices"
+
+	// QueryOracles command for oracles query
+	QueryOracles = "oracles"
 )
 
 // implement fmt.Stringer
@@ -38,6 +41,16 @@ func (a PendingPriceAsset) String() string {
 AssetCode: %s`, a.AssetName, a.AssetCode))
 }
 
+// implement fmt.Stringer
+func (o Oracle) String() string {
+	assets := "any"
+	if len(o.AssetNames) > 0 {
+		assets = strings.Join(o.AssetNames, ", ")
+	}
+	return strings.TrimSpace(fmt.Sprintf(`OracleAddress: %s
+Assets: %s`, o.OracleAddress, assets))
+}
+
 // QueryRawPricesResp response to a rawprice query
 type QueryRawPricesResp []string
 
@@ -54,6 +67,14 @@ func (n QueryAssetsResp) String() string {
 	return strings.Join(n[:], "\n")
 }
 
+// QueryOraclesResp response to a oracles query
+type QueryOraclesResp []string
+
+// implement fmt.Stringer
+func (n QueryOraclesResp) String() string {
+	return strings.Join(n[:], "\n")
+}
+
 // NewQuerier is the module level router for state queries
 func NewQuerier(keeper Keeper) sdk.Querier {
 	return func(ctx sdk.Context, path []string, req abci.RequestQuery) (res []byte, err sdk.Error) {
@@ -66,6 +87,8 @@ func NewQuerier(keeper Keeper) sdk.Querier {
 			return queryAssets(ctx, req, keeper)
 		case QueryPendingPrices:
 			return queryPendingPrices(ctx, req, keeper)
+		case QueryOracles:
+			return queryOracles(ctx, req, keeper)
 		default:
 			return nil, sdk.ErrUnknownRequest("unknown pricefeed query endpoint")
 		}
@@ -137,3 +160,16 @@ func queryPendingPrices(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) (
 
 	return bz, nil
 }
+
+func queryOracles(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) (res []byte, err sdk.Error) {
+	var oracleList QueryOraclesResp
+	for _, oracle := range keeper.GetOracles(ctx) {
+		oracleList = append(oracleList, oracle.String())
+	}
+	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, oracleList)
+	if err2 != nil {
+		panic("could not marshal result to JSON")
+	}
+
+	return bz, nil
+}
@@ -3,6 +3,7 @@ package pricefeed
 import (
 	"testing"
 
+	"github.com/commercionetwork/cosmos-hackatom-2019/blockchain/x/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/mock"
@@ -12,18 +13,30 @@ import (
 )
 
 type testHelper struct {
-	mApp     *mock.App
-	keeper   Keeper
-	addrs    []sdk.AccAddress
-	pubKeys  []crypto.PubKey
-	privKeys []crypto.PrivKey
+	mApp      *mock.App
+	keeper    Keeper
+	cdpKeeper *mockCdpKeeper
+	addrs     []sdk.AccAddress
+	pubKeys   []crypto.PubKey
+	privKeys  []crypto.PrivKey
+}
+
+// mockCdpKeeper records the assets whose CDPs are updated when a price is posted
+type mockCdpKeeper struct {
+	types.CdpKeeper
+	modified []assetKey
+}
+
+func (ck *mockCdpKeeper) ModifyCDPType(_ sdk.Context, assetName string, assetCode string) {
+	ck.modified = append(ck.modified, newAssetKey(assetName, assetCode))
 }
 
 func getMockApp(t *testing.T, numGenAccs int, genState GenesisState, genAccs []auth.Account) testHelper {
 	mApp := mock.NewApp()
 	RegisterCodec(mApp.Cdc)
 	keyPricefeed := sdk.NewKVStoreKey("pricefeed")
-	keeper := NewKeeper(keyPricefeed, mApp.Cdc, DefaultCodespace, nil)
+	cdpKeeper := &mockCdpKeeper{}
+	keeper := NewKeeper(keyPricefeed, mApp.Cdc, mApp.ParamsKeeper.Subspace(DefaultParamspace), DefaultCodespace, cdpKeeper)
 
 	// Register routes
 	mApp.Router().AddRoute(RouterKey, NewHandler(keeper))
@@ -53,5 +66,5 @@ func getMockApp(t *testing.T, numGenAccs int, genState GenesisState, genAccs []a
 	}
 
 	mock.SetGenesis(mApp, genAccs)
-	return testHelper{mApp, keeper, addrs, pubKeys, privKeys}
+	return testHelper{mApp, keeper, cdpKeeper, addrs, pubKeys, privKeys}
 }
@@ -12,9 +12,24 @@ type Asset struct {
 	Description string `json:"description"` // The asset description
 }
 
-// Oracle struct that documents which address an oracle is using
+// Oracle struct that documents which address an oracle is using, and the assets it is assigned to.
+// As NFTs are appraised one by one, oracles are assigned to the asset names rather than to single asset codes.
 type Oracle struct {
-	OracleAddress string `json:"oracle_address"`
+	OracleAddress string   `json:"oracle_address"`
+	AssetNames    []string `json:"asset_names"` // Empty if the oracle can post the price of any asset
+}
+
+// CanPostPrice returns whether the oracle is assigned to the asset with the given name
+func (o Oracle) CanPostPrice(assetName string) bool {
+	if len(o.AssetNames) == 0 {
+		return true
+	}
+	for _, name := range o.AssetNames {
+		if name == assetName {
+			return true
+		}
+	}
+	return false
 }
 
 // PendingPriceAsset struct that contains the info about the asset which price is still to be determined
@@ -10,10 +10,13 @@ type CdpKeeper interface {
 	ModifyCDP(ctx sdk.Context, owner sdk.AccAddress, collateral Collateral, liquidity Liquidity) sdk.Error
 	PartialSeizeCDP(ctx sdk.Context, owner sdk.AccAddress, collateral Collateral, collateralToSeize sdk.Int, debtToSeize sdk.Int) sdk.Error
 	ReduceGlobalDebt(ctx sdk.Context, amount sdk.Int) sdk.Error
-	GetStableDenom() string
+	ReturnSeizedLiquidity(ctx sdk.Context, amount sdk.Coin) sdk.Error
+	PayLiquidationPenalty(ctx sdk.Context, penalty sdk.Coin) sdk.Error
+	GetStableDenom(ctx sdk.Context) string
 	GetGovDenom() string
 	GetParams(ctx sdk.Context) CdpModuleParams
-	GetCDPs(ctx sdk.Context, collateralDenom string, price sdk.Int) (CDPs, sdk.Error)
+	AddCollateralParams(ctx sdk.Context, collateralParams CollateralParams) sdk.Error
+	GetCDPs(ctx sdk.Context, collateralDenom string, nftID string, price sdk.Int) (CDPs, sdk.Error)
 	GetCDP(ctx sdk.Context, owner sdk.AccAddress, collateralDenom string, nftID string) (CDP, bool)
 	GetGlobalDebt(ctx sdk.Context) sdk.Int
 	GetCollateralState(ctx sdk.Context, collateralDenom string) (CollateralState, bool)
@@ -22,7 +25,7 @@ type CdpKeeper interface {
 	SubtractCoins(ctx sdk.Context, address sdk.AccAddress, amount sdk.Coins) (sdk.Coins, sdk.Error)
 	GetCoins(ctx sdk.Context, address sdk.AccAddress) sdk.Coins
 	HasCoins(ctx sdk.Context, address sdk.AccAddress, amount sdk.Coins) bool
-	ModifyCDPType(ctx sdk.Context, AssetName string, AssetCode string) sdk.Error
+	ModifyCDPType(ctx sdk.Context, AssetName string, AssetCode string)
 }
 
 type PricefeedKeeper interface {
@@ -30,7 +33,7 @@ type PricefeedKeeper interface {
 	// These are used for testing TODO replace mockApp with keeper in tests to remove these
 	AddAsset(context sdk.Context, assetCode string, assetString string)
 	SetPrice(context sdk.Context, oracle sdk.AccAddress, assetCode string, assetName string, price sdk.Int, expiry sdk.Int) (PostedPrice, sdk.Error)
-	SetCurrentPrices(sdk.Context) sdk.Error
+	SetCurrentPrices(sdk.Context) (sdk.Tags, sdk.Error)
 	AskForPrice(ctx sdk.Context, assetCode string, assetName string)
 }
 
@@ -0,0 +1,27 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/crypto"
+)
+
+// NewModuleAddress returns the address of the account of a module, derived from the module name.
+// No private key exists for it, so its coins can only be moved by the module itself.
+func NewModuleAddress(moduleName string) sdk.AccAddress {
+	return sdk.AccAddress(crypto.AddressHash([]byte(moduleName)))
+}
+
+// ModuleAccount is the address and balance of the account of a module, as returned by the module account queries
+type ModuleAccount struct {
+	Address sdk.AccAddress `json:"address"`
+	Coins   sdk.Coins      `json:"coins"`
+}
+
+// implement fmt.Stringer
+func (ma ModuleAccount) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`Address: %s
+Coins: %s`, ma.Address, ma.Coins))
+}
@@ -48,13 +48,15 @@ type CurrentPrice struct {
 	AssetCode string  `json:"asset_code"`
 	Price     sdk.Int `json:"price"`
 	Expiry    sdk.Int `json:"expiry"`
+	Stale     bool    `json:"stale"` // not enough oracles posted a valid price since this one
 }
 
 // implement fmt.Stringer
 func (cp CurrentPrice) String() string {
 	return strings.TrimSpace(fmt.Sprintf(`AssetCode: %s
 Price: %s
-Expiry: %s`, cp.AssetCode, cp.Price, cp.Expiry))
+Expiry: %s
+Stale: %t`, cp.AssetCode, cp.Price, cp.Expiry, cp.Stale))
 }
 
 // CDP is the state of a single Collateralized Debt Position.
@@ -63,11 +65,16 @@ type CDP struct {
 	Owner      sdk.AccAddress `json:"owner"`      // Account that authorizes changes to the CDP
 	Collateral Collateral     `json:"collateral"` // Collateral given from the user into the system
 	Liquidity  Liquidity      `json:"liquidity"`  // Liquidity given to the user
+
+	AccumulatedFees sdk.Int `json:"accumulated_fees"` // Part of the liquidity debt made of stability fees, collected first when debt is repaid
+	FeesUpdated     int64   `json:"fees_updated"`     // Block height at which the stability fees were last added to the debt
 }
 
-func (cdp CDP) IsUnderCollateralized(price sdk.Int, liquidationRatio sdk.Dec) bool {
-	collateralValue := sdk.NewDecFromInt(cdp.Collateral.Amount).MulInt(price)
-	minCollateralValue := liquidationRatio.Mul(sdk.NewDecFromInt(cdp.Liquidity.Coin.Amount))
+// IsUnderCollateralized checks whether the value of the collateral is below the liquidation ratio times the value of the debt.
+// Both values are taken at the current prices of the collateral and of the stable coin the debt is in.
+func (cdp CDP) IsUnderCollateralized(collateralPrice sdk.Int, liquidityPrice sdk.Int, liquidationRatio sdk.Dec) bool {
+	collateralValue := sdk.NewDecFromInt(cdp.Collateral.Amount).MulInt(collateralPrice)
+	minCollateralValue := liquidationRatio.Mul(sdk.NewDecFromInt(cdp.Liquidity.Coin.Amount)).MulInt(liquidityPrice)
 	return collateralValue.LT(minCollateralValue) // TODO LT or LTE?
 }
 
@@ -75,10 +82,14 @@ func (cdp CDP) String() string {
 	return strings.TrimSpace(fmt.Sprintf(`CDP:
   Owner:      %s
   Collateral: %s
-  Liquidity:  %s`,
+  Liquidity:  %s
+  Accumulated Fees: %s
+  Fees Updated:     %d`,
 		cdp.Owner,
 		cdp.Collateral,
 		cdp.Liquidity,
+		cdp.AccumulatedFees,
+		cdp.FeesUpdated,
 	))
 }
 
@@ -94,13 +105,16 @@ func (cdps CDPs) String() string {
 
 // CollateralState stores global information tied to a particular collateral type.
 type CollateralState struct {
-	Denom     string  // Type of collateral
-	TotalDebt sdk.Int // total debt collateralized by a this coin type
-	//AccumulatedFees sdk.Int // Ignoring fees for now
+	Denom           string  // Type of collateral
+	TotalDebt       sdk.Int // total debt collateralized by a this coin type, including the fees not paid yet
+	AccumulatedFees sdk.Int // stability fees added to the debt of this coin type's CDPs and not paid yet
+	CollectedFees   sdk.Int // stability fees paid back by this coin type's CDPs, split between the pool and the liquidator module account
 }
 
 type CdpModuleParams struct {
 	GlobalDebtLimit  sdk.Int
+	StableDenom      string // Denom of the stable coin CDPs draw, the only one their debt can be in
+	PoolFeeShare     sdk.Dec // Part of the stability fees paid to the pool depositors, the rest goes to the liquidator as surplus
 	CollateralParams []CollateralParams
 }
 
@@ -108,17 +122,25 @@ type CdpModuleParams struct {
 func (p CdpModuleParams) String() string {
 	out := fmt.Sprintf(`Params:
 	Global Debt Limit: %s
+	Stable Denom:      %s
+	Pool Fee Share:    %s
 	Collateral Params:`,
 		p.GlobalDebtLimit,
+		p.StableDenom,
+		p.PoolFeeShare,
 	)
 	for _, cp := range p.CollateralParams {
 		out += fmt.Sprintf(`
 		%s
 			Liquidation Ratio: %s
-			Debt Limit:        %s`,
+			Debt Limit:        %s
+			Stability Fee:     %s
+			Debt Floor:        %s`,
 			cp.Denom,
 			cp.LiquidationRatio,
 			cp.DebtLimit,
+			cp.StabilityFee,
+			cp.DebtFloor,
 		)
 	}
 	return out
@@ -150,5 +172,6 @@ type CollateralParams struct {
 	Denom            string  // Coin name of collateral type
 	LiquidationRatio sdk.Dec // The ratio (Collateral (priced in stable coin) / Debt) under which a CDP will be liquidated
 	DebtLimit        sdk.Int // Maximum amount of debt allowed to be drawn from this collateral type
-	//DebtFloor        sdk.Int // used to prevent dust
+	StabilityFee     sdk.Dec // Annual interest rate charged on the debt, compounded every block
+	DebtFloor        sdk.Int // Minimum non-zero debt a CDP can have, used to prevent dust. Known as dust in Maker.
 }